@@ -0,0 +1,3 @@
+// Package docs holds the Swagger docs generated for every version of the API,
+// registered under the name of the version.
+package docs
@@ -1,75 +1,652 @@
 package external
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	ServerHost     string
 	DatabaseConfig DatabaseConfig
+	DynamoConfig   DynamoConfig
+	SyncConfig     SyncConfig
 	Environment    string
+	KitchenConfig  KitchenConfig
+	QueueConfig    QueueConfig
+	StoreConfig    StoreConfig
+	AuthConfig     AuthConfig
+	LimitsConfig   LimitsConfig
+	CORSConfig     CORSConfig
+	HeadersConfig  HeadersConfig
+	APIConfig      APIConfig
+	GRPCConfig     GRPCConfig
+	WebhookConfig  WebhookConfig
 }
 
+type KitchenConfig struct {
+	Stations       map[string]string
+	DefaultStation string
+}
+
+type QueueConfig struct {
+	PriorityAging    time.Duration
+	SLATargets       map[string]time.Duration
+	SLACheckInterval time.Duration
+
+	KitchenCapacity    int
+	DefaultPreparation time.Duration
+}
+
+// SyncConfig turns a store node running on a local database into a node that
+// replays its changes on the central DynamoDB, configured by DynamoConfig, every
+// Interval.
+type SyncConfig struct {
+	Enabled   bool
+	Interval  time.Duration
+	BatchSize int
+}
+
+type StoreConfig struct {
+	DefaultStoreId string
+}
+
+// AuthConfig tells how bearer tokens are validated. Tokens signed with HS256 are
+// checked against HMACSecret and tokens signed with RS256 against the keys of
+// the JWKS file or URL.
+type AuthConfig struct {
+	Enabled    bool
+	HMACSecret string
+	JWKSFile   string
+	JWKSURL    string
+	Issuer     string
+	Audience   string
+	RolesClaim string
+}
+
+// LimitsConfig protects the server from clients sending too many or too large
+// requests. A zero Rate disables rate limiting. The client IP is read from
+// X-Forwarded-For only for requests coming from the TrustedProxies ranges.
+type LimitsConfig struct {
+	Rate           float64
+	Burst          int
+	RateKey        string
+	TrustedProxies []string
+	BodyLimit      string
+	RequestTimeout time.Duration
+	ReadTimeout    time.Duration
+	IdleTimeout    time.Duration
+}
+
+// CORSConfig lists what browser apps of other origins, such as the kitchen
+// display, may do. No allowed origins disables CORS.
+type CORSConfig struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           int
+}
+
+// HeadersConfig holds the security headers sent on every response. Empty values
+// leave the header out.
+type HeadersConfig struct {
+	HSTSMaxAge            int
+	HSTSIncludeSubdomains bool
+	FrameOptions          string
+	ContentTypeNosniff    string
+	XSSProtection         string
+	ReferrerPolicy        string
+	ContentSecurityPolicy string
+}
+
+// APIConfig tells whether the routes outside a version, kept for the clients
+// written before the API was versioned, are still served, and the date they
+// stop being served, announced to those clients. A zero LegacySunset announces
+// no date.
+type APIConfig struct {
+	LegacyRoutes bool
+	LegacySunset time.Time
+}
+
+// GRPCConfig serves the production queue over gRPC on Host, a port of its own
+// next to the REST API. WatchInterval is how often a watched queue is checked
+// for changes.
+type GRPCConfig struct {
+	Enabled       bool
+	Host          string
+	WatchInterval time.Duration
+}
+
+// WebhookConfig tells how the status changes are posted to the webhook
+// subscriptions: each delivery is tried up to MaxAttempts times with a backoff
+// from RetryBaseDelay to RetryMaxDelay, and a subscription is disabled after
+// DisableAfter deliveries in a row failed. Workers post the events queued in a
+// queue of QueueSize events, waiting Timeout for each answer. AllowInsecureURLs
+// accepts http callbacks and local or private addresses, for development and
+// tests only.
+type WebhookConfig struct {
+	MaxAttempts       int
+	RetryBaseDelay    time.Duration
+	RetryMaxDelay     time.Duration
+	DisableAfter      int
+	Timeout           time.Duration
+	Workers           int
+	QueueSize         int
+	AllowInsecureURLs bool
+}
+
+const (
+	DYNAMO_DATABASE_DRIVER   = "dynamo"
+	POSTGRES_DATABASE_DRIVER = "postgres"
+	SQLITE_DATABASE_DRIVER   = "sqlite"
+)
+
+// DatabaseConfig chooses the database holding the production orders. The
+// connection settings of Postgres are used by the postgres driver, and Path,
+// the file of the embedded database, by the sqlite driver.
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DbName   string
+	Driver       string
+	Host         string
+	Port         string
+	User         string
+	Password     string
+	DbName       string
+	SSLMode      string
+	MaxOpenConns int
+	AutoMigrate  bool
+	Path         string
+}
+
+// PostgresURL returns the connection URL of the Postgres database.
+func (c DatabaseConfig) PostgresURL() string {
+	connectionURL := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Password),
+		Host:     net.JoinHostPort(c.Host, c.Port),
+		Path:     "/" + c.DbName,
+		RawQuery: url.Values{"sslmode": []string{c.SSLMode}}.Encode(),
+	}
+
+	return connectionURL.String()
+}
+
+// DynamoConfig tells how to reach DynamoDB. An empty Region, Endpoint or
+// Profile falls back to the AWS SDK defaults, such as AWS_REGION. TablePrefix
+// is prepended to every table name, so environments sharing an account keep
+// separate tables. Calls failing with transient errors are tried up to
+// MaxAttempts times, and BreakerFailures consecutive failures stop the calls
+// for BreakerOpenTimeout.
+type DynamoConfig struct {
+	Region           string
+	Endpoint         string
+	Profile          string
+	TablePrefix      string
+	TableName        string
+	AuditTableName   string
+	AutoCreateTables bool
+
+	// LegacyTableName is the orders table keyed by order id only, written
+	// before the orders were scoped by store, which the backfill subcommand
+	// copies into TableName.
+	LegacyTableName string
+
+	WebhookTableName         string
+	WebhookDeliveryTableName string
+
+	MaxAttempts        int
+	RetryBaseDelay     time.Duration
+	RetryMaxDelay      time.Duration
+	BreakerFailures    int
+	BreakerOpenTimeout time.Duration
+}
+
+// ProductionOrderTable returns the name of the production orders table.
+func (c DynamoConfig) ProductionOrderTable() string {
+	return c.TablePrefix + c.TableName
+}
+
+// LegacyProductionOrderTable returns the name of the orders table written
+// before the orders were scoped by store.
+func (c DynamoConfig) LegacyProductionOrderTable() string {
+	return c.TablePrefix + c.LegacyTableName
+}
+
+// AuditTable returns the name of the audit trail table.
+func (c DynamoConfig) AuditTable() string {
+	return c.TablePrefix + c.AuditTableName
+}
+
+// WebhookTable returns the name of the webhook subscriptions table.
+func (c DynamoConfig) WebhookTable() string {
+	return c.TablePrefix + c.WebhookTableName
+}
+
+// WebhookDeliveryTable returns the name of the webhook delivery log table.
+func (c DynamoConfig) WebhookDeliveryTable() string {
+	return c.TablePrefix + c.WebhookDeliveryTableName
 }
 
 var (
-	runOnce sync.Once
-	config  Config
+	runOnce    sync.Once
+	config     Config
+	errConfig  error
+	configFile string
 )
 
-func GetConfig() Config {
-	runOnce.Do(func() {
-		cfg, err := initConfig()
-		if err != nil {
-			fmt.Println(context.Background(), err, "could not load usecase configuration")
-		}
+// SetConfigFile sets the YAML or TOML file read by GetConfig. It must be called
+// before the configuration is first loaded.
+func SetConfigFile(file string) {
+	configFile = file
+}
 
-		fmt.Println(cfg.GetString("environment"))
-		config = Config{
-			ServerHost: cfg.GetString("server.host"),
-			DatabaseConfig: DatabaseConfig{
-				Host:     cfg.GetString("database.host"),
-				Port:     cfg.GetString("database.port"),
-				User:     cfg.GetString("database.user"),
-				Password: cfg.GetString("database.password"),
-				DbName:   cfg.GetString("database.dbname"),
-			},
-			Environment: cfg.GetString("environment"),
-		}
+// LoadConfig reads the configuration once, from the defaults, the config file
+// and the environment, in increasing precedence, and validates it.
+func LoadConfig() (Config, error) {
+	runOnce.Do(func() {
+		config, errConfig = loadConfig(configFile)
 	})
 
-	return config
+	return config, errConfig
+}
+
+// GetConfig returns the configuration loaded by LoadConfig. It panics on an
+// invalid configuration, so the process stops before serving any request.
+func GetConfig() Config {
+	cfg, err := LoadConfig()
+
+	if err != nil {
+		log.Panic(err.Error())
+	}
+
+	return cfg
 }
 
-func initConfig() (viper.Viper, error) {
+func loadConfig(file string) (Config, error) {
+	cfg, err := initConfig(file)
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	reader := &configReader{cfg: cfg}
+	config := Config{
+		ServerHost: reader.string("server.host"),
+		DatabaseConfig: DatabaseConfig{
+			Driver:       reader.string("database.driver"),
+			Host:         reader.string("database.host"),
+			Port:         reader.string("database.port"),
+			User:         reader.string("database.user"),
+			Password:     reader.string("database.password"),
+			DbName:       reader.string("database.dbname"),
+			SSLMode:      reader.string("database.sslmode"),
+			MaxOpenConns: reader.int("database.max_open_conns"),
+			AutoMigrate:  reader.bool("database.auto_migrate"),
+			Path:         reader.string("database.path"),
+		},
+		DynamoConfig: DynamoConfig{
+			Region:           reader.string("dynamo.region"),
+			Endpoint:         reader.string("dynamo.endpoint"),
+			Profile:          reader.string("dynamo.profile"),
+			TablePrefix:      reader.string("dynamo.table_prefix"),
+			TableName:        reader.string("dynamo.table_name"),
+			AuditTableName:   reader.string("dynamo.audit_table_name"),
+			LegacyTableName:  reader.string("dynamo.legacy_table_name"),
+			AutoCreateTables: reader.bool("dynamo.auto_create_tables"),
+
+			WebhookTableName:         reader.string("dynamo.webhook_table_name"),
+			WebhookDeliveryTableName: reader.string("dynamo.webhook_delivery_table_name"),
+
+			MaxAttempts:        reader.int("dynamo.max_attempts"),
+			RetryBaseDelay:     reader.duration("dynamo.retry_base_delay"),
+			RetryMaxDelay:      reader.duration("dynamo.retry_max_delay"),
+			BreakerFailures:    reader.int("dynamo.breaker_failures"),
+			BreakerOpenTimeout: reader.duration("dynamo.breaker_open_timeout"),
+		},
+		SyncConfig: SyncConfig{
+			Enabled:   reader.bool("sync.enabled"),
+			Interval:  reader.duration("sync.interval"),
+			BatchSize: reader.int("sync.batch_size"),
+		},
+		Environment: reader.string("environment"),
+		KitchenConfig: KitchenConfig{
+			Stations:       reader.keyValues("kitchen.stations"),
+			DefaultStation: reader.string("kitchen.default_station"),
+		},
+		QueueConfig: QueueConfig{
+			PriorityAging:    reader.duration("queue.priority_aging"),
+			SLATargets:       reader.durations("queue.sla_targets"),
+			SLACheckInterval: reader.duration("queue.sla_check_interval"),
+
+			KitchenCapacity:    reader.int("queue.kitchen_capacity"),
+			DefaultPreparation: reader.duration("queue.default_preparation"),
+		},
+		StoreConfig: StoreConfig{
+			DefaultStoreId: reader.string("store.default_id"),
+		},
+		AuthConfig: AuthConfig{
+			Enabled:    reader.bool("auth.enabled"),
+			HMACSecret: reader.string("auth.hmac_secret"),
+			JWKSFile:   reader.string("auth.jwks_file"),
+			JWKSURL:    reader.string("auth.jwks_url"),
+			Issuer:     reader.string("auth.issuer"),
+			Audience:   reader.string("auth.audience"),
+			RolesClaim: reader.string("auth.roles_claim"),
+		},
+		LimitsConfig: LimitsConfig{
+			Rate:           reader.float("limits.rate"),
+			Burst:          reader.int("limits.burst"),
+			RateKey:        reader.string("limits.rate_key"),
+			TrustedProxies: reader.list("limits.trusted_proxies"),
+			BodyLimit:      reader.string("limits.body"),
+			RequestTimeout: reader.duration("limits.request_timeout"),
+			ReadTimeout:    reader.duration("limits.read_timeout"),
+			IdleTimeout:    reader.duration("limits.idle_timeout"),
+		},
+		CORSConfig: CORSConfig{
+			AllowOrigins:     reader.list("cors.allow_origins"),
+			AllowMethods:     reader.list("cors.allow_methods"),
+			AllowHeaders:     reader.list("cors.allow_headers"),
+			ExposeHeaders:    reader.list("cors.expose_headers"),
+			AllowCredentials: reader.bool("cors.allow_credentials"),
+			MaxAge:           reader.int("cors.max_age"),
+		},
+		HeadersConfig: HeadersConfig{
+			HSTSMaxAge:            reader.int("headers.hsts_max_age"),
+			HSTSIncludeSubdomains: reader.bool("headers.hsts_include_subdomains"),
+			FrameOptions:          reader.string("headers.frame_options"),
+			ContentTypeNosniff:    reader.string("headers.content_type_nosniff"),
+			XSSProtection:         reader.string("headers.xss_protection"),
+			ReferrerPolicy:        reader.string("headers.referrer_policy"),
+			ContentSecurityPolicy: reader.string("headers.content_security_policy"),
+		},
+		APIConfig: APIConfig{
+			LegacyRoutes: reader.bool("api.legacy_routes"),
+			LegacySunset: reader.date("api.legacy_sunset"),
+		},
+		GRPCConfig: GRPCConfig{
+			Enabled:       reader.bool("grpc.enabled"),
+			Host:          reader.string("grpc.host"),
+			WatchInterval: reader.duration("grpc.watch_interval"),
+		},
+		WebhookConfig: WebhookConfig{
+			MaxAttempts:       reader.int("webhook.max_attempts"),
+			RetryBaseDelay:    reader.duration("webhook.retry_base_delay"),
+			RetryMaxDelay:     reader.duration("webhook.retry_max_delay"),
+			DisableAfter:      reader.int("webhook.disable_after"),
+			Timeout:           reader.duration("webhook.timeout"),
+			Workers:           reader.int("webhook.workers"),
+			QueueSize:         reader.int("webhook.queue_size"),
+			AllowInsecureURLs: reader.bool("webhook.allow_insecure_urls"),
+		},
+	}
+
+	err = errors.Join(append(reader.errs, config.Validate())...)
+
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+
+	return config, nil
+}
+
+func initConfig(file string) (*viper.Viper, error) {
 	cfg := viper.New()
+	cfg.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	cfg.AutomaticEnv()
-	var err error
 	initDefaults(cfg)
+
+	if file != "" {
+		cfg.SetConfigFile(file)
+		err := cfg.ReadInConfig()
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read config file %s: %w", file, err)
+		}
+	}
+
 	// workaround because viper does not resolve envs when unmarshalling
 	for _, key := range cfg.AllKeys() {
 		val := cfg.Get(key)
 		cfg.Set(key, val)
 	}
-	return *cfg, err
+	return cfg, nil
 }
 
 func initDefaults(config *viper.Viper) {
 	config.SetDefault("server.host", "0.0.0.0:8000")
+	config.SetDefault("database.driver", DYNAMO_DATABASE_DRIVER)
 	config.SetDefault("database.host", "postgres")
 	config.SetDefault("database.port", "5432")
 	config.SetDefault("database.user", "root")
 	config.SetDefault("database.password", "root")
 	config.SetDefault("database.dbname", "root")
+	config.SetDefault("database.sslmode", "disable")
+	config.SetDefault("database.max_open_conns", 10)
+	config.SetDefault("database.auto_migrate", true)
+	config.SetDefault("database.path", "production_orders.db")
+	config.SetDefault("dynamo.region", "")
+	config.SetDefault("dynamo.endpoint", "")
+	config.SetDefault("dynamo.profile", "")
+	config.SetDefault("dynamo.table_prefix", "")
+	config.SetDefault("dynamo.table_name", "production_order_by_store")
+	config.SetDefault("dynamo.legacy_table_name", "production_order")
+	config.SetDefault("dynamo.audit_table_name", "production_order_audit")
+	config.SetDefault("dynamo.auto_create_tables", true)
+	config.SetDefault("dynamo.webhook_table_name", "production_order_webhook")
+	config.SetDefault("dynamo.webhook_delivery_table_name", "production_order_webhook_delivery")
+	config.SetDefault("dynamo.max_attempts", 3)
+	config.SetDefault("dynamo.retry_base_delay", "50ms")
+	config.SetDefault("dynamo.retry_max_delay", "1s")
+	config.SetDefault("dynamo.breaker_failures", 5)
+	config.SetDefault("dynamo.breaker_open_timeout", "30s")
 	config.SetDefault("environment", "production")
+	config.SetDefault("kitchen.stations", "LANCHE=grill,ACOMPANHAMENTO=fryer,BEBIDA=drinks,SOBREMESA=dessert")
+	config.SetDefault("kitchen.default_station", "grill")
+	config.SetDefault("sync.enabled", false)
+	config.SetDefault("sync.interval", "30s")
+	config.SetDefault("sync.batch_size", 100)
+	config.SetDefault("queue.priority_aging", "5m")
+	config.SetDefault("queue.sla_targets", "RECEBIDO=5m,EM_PREPARACAO=15m,PRONTO=10m")
+	config.SetDefault("queue.sla_check_interval", "30s")
+	config.SetDefault("queue.kitchen_capacity", 3)
+	config.SetDefault("queue.default_preparation", "10m")
+	config.SetDefault("store.default_id", "default")
+	config.SetDefault("auth.enabled", true)
+	config.SetDefault("auth.hmac_secret", "")
+	config.SetDefault("auth.jwks_file", "")
+	config.SetDefault("auth.jwks_url", "")
+	config.SetDefault("auth.issuer", "")
+	config.SetDefault("auth.audience", "")
+	config.SetDefault("auth.roles_claim", "roles")
+	config.SetDefault("limits.rate", 10)
+	config.SetDefault("limits.burst", 20)
+	config.SetDefault("limits.rate_key", "ip")
+	config.SetDefault("limits.trusted_proxies", "")
+	config.SetDefault("limits.body", "1M")
+	config.SetDefault("limits.request_timeout", "10s")
+	config.SetDefault("limits.read_timeout", "15s")
+	config.SetDefault("limits.idle_timeout", "60s")
+	config.SetDefault("cors.allow_origins", "")
+	config.SetDefault("cors.allow_methods", "GET,POST,PUT,DELETE,OPTIONS")
+	config.SetDefault("cors.allow_headers", "Authorization,Content-Type,X-Actor,X-Station,X-Request-Id")
+	config.SetDefault("cors.expose_headers", "X-Request-Id,Retry-After,Deprecation,Sunset,Link")
+	config.SetDefault("cors.allow_credentials", false)
+	config.SetDefault("cors.max_age", 600)
+	config.SetDefault("headers.hsts_max_age", 31536000)
+	config.SetDefault("headers.hsts_include_subdomains", true)
+	config.SetDefault("headers.frame_options", "DENY")
+	config.SetDefault("headers.content_type_nosniff", "nosniff")
+	config.SetDefault("headers.xss_protection", "0")
+	config.SetDefault("headers.referrer_policy", "no-referrer")
+	config.SetDefault("headers.content_security_policy", "")
+	config.SetDefault("api.legacy_routes", true)
+	config.SetDefault("api.legacy_sunset", "")
+	config.SetDefault("grpc.enabled", true)
+	config.SetDefault("grpc.host", "0.0.0.0:9090")
+	config.SetDefault("grpc.watch_interval", "2s")
+	config.SetDefault("webhook.max_attempts", 5)
+	config.SetDefault("webhook.retry_base_delay", "1s")
+	config.SetDefault("webhook.retry_max_delay", "1m")
+	config.SetDefault("webhook.disable_after", 5)
+	config.SetDefault("webhook.timeout", "5s")
+	config.SetDefault("webhook.workers", 4)
+	config.SetDefault("webhook.queue_size", 1000)
+	config.SetDefault("webhook.allow_insecure_urls", false)
+}
+
+// parseKeyValues reads settings written as "KEY=value,KEY=value", such as the
+// category-to-station rules, so they can be overridden by a single environment
+// variable.
+func parseKeyValues(value string) map[string]string {
+	values := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return values
+}
+
+// parseList reads settings written as "value,value", such as the allowed CORS
+// origins.
+func parseList(value string) []string {
+	values := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		values = append(values, item)
+	}
+
+	return values
+}
+
+// parseDurations reads the values of a "KEY=value" setting as durations.
+func parseDurations(values map[string]string) (map[string]time.Duration, error) {
+	durations := map[string]time.Duration{}
+	for key, value := range values {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q for %s", value, key)
+		}
+
+		durations[key] = duration
+	}
+
+	return durations, nil
+}
+
+// configReader reads typed settings, keeping every invalid value it finds so
+// they can all be reported at once instead of silently becoming zero values.
+type configReader struct {
+	cfg  *viper.Viper
+	errs []error
+}
+
+func (r *configReader) invalid(key string, kind string) {
+	r.errs = append(r.errs, fmt.Errorf("%s: invalid %s %q", key, kind, r.cfg.Get(key)))
+}
+
+func (r *configReader) string(key string) string {
+	return r.cfg.GetString(key)
+}
+
+func (r *configReader) bool(key string) bool {
+	value, err := cast.ToBoolE(r.cfg.Get(key))
+	if err != nil {
+		r.invalid(key, "boolean")
+	}
+
+	return value
+}
+
+func (r *configReader) int(key string) int {
+	value, err := cast.ToIntE(r.cfg.Get(key))
+	if err != nil {
+		r.invalid(key, "integer")
+	}
+
+	return value
+}
+
+func (r *configReader) float(key string) float64 {
+	value, err := cast.ToFloat64E(r.cfg.Get(key))
+	if err != nil {
+		r.invalid(key, "number")
+	}
+
+	return value
+}
+
+func (r *configReader) duration(key string) time.Duration {
+	value, err := cast.ToDurationE(r.cfg.Get(key))
+	if err != nil {
+		r.invalid(key, "duration")
+	}
+
+	return value
+}
+
+// date reads a date or an RFC3339 timestamp. An empty value is the zero time.
+func (r *configReader) date(key string) time.Time {
+	if r.cfg.GetString(key) == "" {
+		return time.Time{}
+	}
+
+	value, err := cast.ToTimeE(r.cfg.Get(key))
+	if err != nil {
+		r.invalid(key, "date")
+	}
+
+	return value
+}
+
+// list reads a list written either as a config file list or as "value,value".
+func (r *configReader) list(key string) []string {
+	if values, ok := r.cfg.Get(key).([]interface{}); ok {
+		return parseList(strings.Join(cast.ToStringSlice(values), ","))
+	}
+
+	return parseList(r.cfg.GetString(key))
+}
+
+// keyValues reads a setting written either as a config file map or as
+// "KEY=value,KEY=value". Config file keys are lowercased by viper, so they are
+// uppercased back to match the categories and statuses.
+func (r *configReader) keyValues(key string) map[string]string {
+	if _, ok := r.cfg.Get(key).(map[string]interface{}); ok {
+		values := map[string]string{}
+		for mapKey, value := range r.cfg.GetStringMapString(key) {
+			values[strings.ToUpper(mapKey)] = value
+		}
+
+		return values
+	}
+
+	return parseKeyValues(r.cfg.GetString(key))
+}
+
+func (r *configReader) durations(key string) map[string]time.Duration {
+	durations, err := parseDurations(r.keyValues(key))
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: %w", key, err))
+	}
+
+	return durations
 }
@@ -0,0 +1,249 @@
+package external
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+
+	"github.com/labstack/gommon/bytes"
+)
+
+const redacted = "REDACTED"
+
+// tableNamePattern matches the names DynamoDB accepts for a table.
+var tableNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,255}$`)
+
+// Validate reports every setting the service cannot start with, so a
+// misconfigured deploy fails on start instead of on the first request.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(key string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if _, _, err := net.SplitHostPort(c.ServerHost); err != nil {
+		invalid("server.host", "must be host:port, got %q", c.ServerHost)
+	}
+
+	if c.GRPCConfig.Enabled {
+		if _, _, err := net.SplitHostPort(c.GRPCConfig.Host); err != nil {
+			invalid("grpc.host", "must be host:port, got %q", c.GRPCConfig.Host)
+		}
+
+		if c.GRPCConfig.WatchInterval <= 0 {
+			invalid("grpc.watch_interval", "must be positive")
+		}
+	}
+
+	switch c.DatabaseConfig.Driver {
+	case DYNAMO_DATABASE_DRIVER:
+	case POSTGRES_DATABASE_DRIVER:
+		if c.DatabaseConfig.Host == "" || c.DatabaseConfig.Port == "" || c.DatabaseConfig.User == "" || c.DatabaseConfig.DbName == "" {
+			invalid("database", "host, port, user and dbname are required by the postgres driver")
+		}
+
+		if c.DatabaseConfig.MaxOpenConns < 1 {
+			invalid("database.max_open_conns", "must be at least 1")
+		}
+	case SQLITE_DATABASE_DRIVER:
+		if c.DatabaseConfig.Path == "" {
+			invalid("database.path", "is required by the sqlite driver")
+		}
+	default:
+		invalid("database.driver", "must be dynamo, postgres or sqlite, got %q", c.DatabaseConfig.Driver)
+	}
+
+	if c.DynamoConfig.Endpoint != "" {
+		endpoint, err := url.Parse(c.DynamoConfig.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			invalid("dynamo.endpoint", "must be an absolute url, got %q", c.DynamoConfig.Endpoint)
+		}
+	}
+
+	if !tableNamePattern.MatchString(c.DynamoConfig.ProductionOrderTable()) {
+		invalid("dynamo.table_name", "%q with its prefix is not a valid table name", c.DynamoConfig.ProductionOrderTable())
+	}
+
+	if !tableNamePattern.MatchString(c.DynamoConfig.LegacyProductionOrderTable()) {
+		invalid("dynamo.legacy_table_name", "%q with its prefix is not a valid table name", c.DynamoConfig.LegacyProductionOrderTable())
+	} else if c.DynamoConfig.LegacyTableName == c.DynamoConfig.TableName {
+		invalid("dynamo.legacy_table_name", "must differ from dynamo.table_name, got %q", c.DynamoConfig.LegacyTableName)
+	}
+
+	if !tableNamePattern.MatchString(c.DynamoConfig.AuditTable()) {
+		invalid("dynamo.audit_table_name", "%q with its prefix is not a valid table name", c.DynamoConfig.AuditTable())
+	}
+
+	if !tableNamePattern.MatchString(c.DynamoConfig.WebhookTable()) {
+		invalid("dynamo.webhook_table_name", "%q with its prefix is not a valid table name", c.DynamoConfig.WebhookTable())
+	}
+
+	if !tableNamePattern.MatchString(c.DynamoConfig.WebhookDeliveryTable()) {
+		invalid("dynamo.webhook_delivery_table_name", "%q with its prefix is not a valid table name", c.DynamoConfig.WebhookDeliveryTable())
+	}
+
+	if c.DynamoConfig.MaxAttempts < 1 {
+		invalid("dynamo.max_attempts", "must be at least 1")
+	}
+
+	if c.DynamoConfig.RetryBaseDelay < 0 || c.DynamoConfig.RetryMaxDelay < c.DynamoConfig.RetryBaseDelay {
+		invalid("dynamo.retry_max_delay", "must not be less than dynamo.retry_base_delay, which must not be negative")
+	}
+
+	if c.DynamoConfig.BreakerFailures < 0 {
+		invalid("dynamo.breaker_failures", "must not be negative")
+	}
+
+	if c.DynamoConfig.BreakerFailures > 0 && c.DynamoConfig.BreakerOpenTimeout <= 0 {
+		invalid("dynamo.breaker_open_timeout", "must be positive while the circuit breaker is enabled")
+	}
+
+	if c.SyncConfig.Enabled {
+		if c.DatabaseConfig.Driver == DYNAMO_DATABASE_DRIVER {
+			invalid("sync.enabled", "needs a local database, set database.driver to sqlite or postgres")
+		}
+
+		if c.SyncConfig.Interval <= 0 {
+			invalid("sync.interval", "must be positive while sync is enabled")
+		}
+
+		if c.SyncConfig.BatchSize < 1 {
+			invalid("sync.batch_size", "must be at least 1")
+		}
+	}
+
+	if c.WebhookConfig.MaxAttempts < 1 {
+		invalid("webhook.max_attempts", "must be at least 1")
+	}
+
+	if c.WebhookConfig.RetryBaseDelay < 0 || c.WebhookConfig.RetryMaxDelay < c.WebhookConfig.RetryBaseDelay {
+		invalid("webhook.retry_max_delay", "must not be less than webhook.retry_base_delay, which must not be negative")
+	}
+
+	if c.WebhookConfig.DisableAfter < 0 {
+		invalid("webhook.disable_after", "must not be negative")
+	}
+
+	if c.WebhookConfig.Timeout <= 0 {
+		invalid("webhook.timeout", "must be positive")
+	}
+
+	if c.WebhookConfig.Workers < 1 {
+		invalid("webhook.workers", "must be at least 1")
+	}
+
+	if c.WebhookConfig.QueueSize < 1 {
+		invalid("webhook.queue_size", "must be at least 1")
+	}
+
+	if c.KitchenConfig.DefaultStation == "" {
+		invalid("kitchen.default_station", "is required")
+	}
+
+	if c.QueueConfig.PriorityAging < 0 {
+		invalid("queue.priority_aging", "must not be negative")
+	}
+
+	for status, target := range c.QueueConfig.SLATargets {
+		if target <= 0 {
+			invalid("queue.sla_targets", "target of %s must be positive", status)
+		}
+	}
+
+	if c.QueueConfig.SLACheckInterval < 0 {
+		invalid("queue.sla_check_interval", "must not be negative")
+	}
+
+	if c.QueueConfig.KitchenCapacity < 1 {
+		invalid("queue.kitchen_capacity", "must be at least 1")
+	}
+
+	if c.QueueConfig.DefaultPreparation <= 0 {
+		invalid("queue.default_preparation", "must be positive")
+	}
+
+	if c.StoreConfig.DefaultStoreId == "" {
+		invalid("store.default_id", "is required")
+	}
+
+	if c.AuthConfig.Enabled {
+		if c.AuthConfig.HMACSecret == "" && c.AuthConfig.JWKSFile == "" && c.AuthConfig.JWKSURL == "" {
+			invalid("auth", "one of hmac_secret, jwks_file or jwks_url is required while auth is enabled")
+		}
+
+		if c.AuthConfig.RolesClaim == "" {
+			invalid("auth.roles_claim", "is required while auth is enabled")
+		}
+	}
+
+	if c.LimitsConfig.Rate < 0 {
+		invalid("limits.rate", "must not be negative")
+	}
+
+	if c.LimitsConfig.Burst < 0 {
+		invalid("limits.burst", "must not be negative")
+	}
+
+	if c.LimitsConfig.RateKey != "ip" && c.LimitsConfig.RateKey != "subject" {
+		invalid("limits.rate_key", "must be ip or subject, got %q", c.LimitsConfig.RateKey)
+	}
+
+	for _, proxy := range c.LimitsConfig.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			invalid("limits.trusted_proxies", "must list address ranges such as 10.0.0.0/8, got %q", proxy)
+		}
+	}
+
+	if c.LimitsConfig.BodyLimit != "" {
+		if _, err := bytes.Parse(c.LimitsConfig.BodyLimit); err != nil {
+			invalid("limits.body", "must be a size such as 1M, got %q", c.LimitsConfig.BodyLimit)
+		}
+	}
+
+	if c.LimitsConfig.RequestTimeout < 0 || c.LimitsConfig.ReadTimeout < 0 || c.LimitsConfig.IdleTimeout < 0 {
+		invalid("limits", "timeouts must not be negative")
+	}
+
+	for _, origin := range c.CORSConfig.AllowOrigins {
+		if origin == "*" && c.CORSConfig.AllowCredentials {
+			invalid("cors.allow_origins", "must list the origins when cors.allow_credentials is set")
+		}
+	}
+
+	if c.CORSConfig.MaxAge < 0 {
+		invalid("cors.max_age", "must not be negative")
+	}
+
+	if c.HeadersConfig.HSTSMaxAge < 0 {
+		invalid("headers.hsts_max_age", "must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the configuration without its secrets, safe to be
+// logged.
+func (c Config) Redacted() Config {
+	if c.DatabaseConfig.Password != "" {
+		c.DatabaseConfig.Password = redacted
+	}
+
+	if c.AuthConfig.HMACSecret != "" {
+		c.AuthConfig.HMACSecret = redacted
+	}
+
+	if jwksURL, err := url.Parse(c.AuthConfig.JWKSURL); err == nil && jwksURL.User != nil {
+		jwksURL.User = url.User(redacted)
+		c.AuthConfig.JWKSURL = jwksURL.String()
+	}
+
+	return c
+}
+
+// String prints the configuration without its secrets.
+func (c Config) String() string {
+	type plainConfig Config
+	return fmt.Sprintf("%+v", plainConfig(c.Redacted()))
+}
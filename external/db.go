@@ -2,11 +2,13 @@ package external
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
 	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/guregu/dynamo/v2"
 )
@@ -17,26 +19,93 @@ var (
 
 func ConectaDB(config Config) *dynamo.DB {
 
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO())
+	options := []func(*awsconfig.LoadOptions) error{}
+
+	if config.DynamoConfig.Region != "" {
+		options = append(options, awsconfig.WithRegion(config.DynamoConfig.Region))
+	}
+
+	if config.DynamoConfig.Profile != "" {
+		options = append(options, awsconfig.WithSharedConfigProfile(config.DynamoConfig.Profile))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), options...)
 
 	if err != nil {
 		log.Println(err.Error())
 		log.Panic("Erro na conexao com banco de dados")
 	}
 
-	fmt.Println(config.Environment)
-	if config.Environment == "development" {
-		baseURL := "http://localstack:4566"
-		cfg.BaseEndpoint = &baseURL
+	log.Println(config.Environment)
+	// the calls are retried by the resilient adapter, so the SDK must not retry
+	// them again
+	cfg.Retryer = func() aws.Retryer {
+		return aws.NopRetryer{}
+	}
+
+	if config.DynamoConfig.Endpoint != "" {
+		cfg.BaseEndpoint = &config.DynamoConfig.Endpoint
 	}
 
 	DB = dynamo.New(cfg)
 
-	err = DB.CreateTable("production_order", entities.ProductionOrder{}).OnDemand(true).Run(context.TODO())
+	if config.DynamoConfig.AutoCreateTables {
+		createTables(DB, config.DynamoConfig)
+	}
+
+	return DB
+}
+
+// ErrIncompatibleTable is wrapped by the errors of CheckProductionOrderTable
+// when the table was read but is not the one the service expects.
+var ErrIncompatibleTable = errors.New("incompatible table")
+
+// CheckProductionOrderTable reads the description of the orders table and
+// fails when it cannot be read or is not the one the service expects.
+func CheckProductionOrderTable(db *dynamo.DB, config DynamoConfig) error {
+	description, err := db.Table(config.ProductionOrderTable()).Describe().Run(context.TODO())
 
 	if err != nil {
-		log.Println(err.Error())
+		return fmt.Errorf("describing table %s: %w", config.ProductionOrderTable(), err)
 	}
 
-	return DB
+	return checkProductionOrderTable(description)
+}
+
+// createTables creates the tables of the service. Tables that already exist are
+// left as they are.
+func createTables(db *dynamo.DB, config DynamoConfig) {
+	tables := map[string]interface{}{
+		config.ProductionOrderTable(): entities.ProductionOrder{},
+		config.AuditTable():           entities.AuditEntry{},
+		config.WebhookTable():         entities.WebhookSubscription{},
+		config.WebhookDeliveryTable(): entities.WebhookDelivery{},
+	}
+
+	for name, from := range tables {
+		err := db.CreateTable(name, from).OnDemand(true).Run(context.TODO())
+
+		if err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// checkProductionOrderTable fails when the orders table is not keyed by store
+// and order id or lacks the open orders index, as the tables created before
+// the orders were scoped by store are. Those are copied into a new table by
+// the backfill subcommand.
+func checkProductionOrderTable(description dynamo.Description) error {
+	if description.HashKey != "StoreID" || description.RangeKey != "ID" {
+		return fmt.Errorf("%w: table %s is keyed by %q and %q instead of \"StoreID\" and \"ID\", run the backfill subcommand to copy it into a new table",
+			ErrIncompatibleTable, description.Name, description.HashKey, description.RangeKey)
+	}
+
+	for _, index := range description.GSI {
+		if index.Name == entities.OPEN_ORDERS_INDEX && index.HashKey == "OpenStoreId" && index.RangeKey == "ID" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: table %s has no %s index keyed by \"OpenStoreId\" and \"ID\"", ErrIncompatibleTable, description.Name, entities.OPEN_ORDERS_INDEX)
 }
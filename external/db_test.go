@@ -0,0 +1,33 @@
+package external
+
+import (
+	"testing"
+
+	"github.com/guregu/dynamo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckProductionOrderTable(t *testing.T) {
+	openOrders := dynamo.Index{Name: "OpenOrders", HashKey: "OpenStoreId", RangeKey: "ID"}
+
+	assert.NoError(t, checkProductionOrderTable(dynamo.Description{
+		Name:     "production_order_by_store",
+		HashKey:  "StoreID",
+		RangeKey: "ID",
+		GSI:      []dynamo.Index{openOrders},
+	}))
+
+	assert.ErrorContains(t, checkProductionOrderTable(dynamo.Description{
+		Name:    "production_order",
+		HashKey: "ID",
+	}), `table production_order is keyed by "ID" and ""`)
+
+	err := checkProductionOrderTable(dynamo.Description{
+		Name:     "production_order_by_store",
+		HashKey:  "StoreID",
+		RangeKey: "ID",
+	})
+
+	assert.ErrorContains(t, err, "has no OpenOrders index")
+	assert.ErrorIs(t, err, ErrIncompatibleTable)
+}
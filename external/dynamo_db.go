@@ -22,49 +22,211 @@ func GetDynamoDatabase(cfg aws.Config) DynamoDatabase {
 	return dynamo.New(cfg)
 }
 
-type dynamoAdapter struct {
+// dynamoAdapter reads and writes the items of a table as values of T, using
+// the dynamo tags of T. Items that do not match T are reported as errors.
+type dynamoAdapter[T any] struct {
 	db    DynamoDatabase
 	table *string
 }
 
-type DynamoAdapter interface {
+type DynamoAdapter[T any] interface {
 	SetTable(table string)
-	GetAll() (value []map[string]interface{}, err error)
-	GetOneByKey(key string, valueKey interface{}) (value map[string]interface{}, err error)
-	Create(value interface{}) (err error)
-	UpdateValue(key string, valueKey interface{}, keyToUpdate string, valueToUpdate interface{}) (updatedValue map[string]interface{}, err error)
+	GetAll() (value []T, err error)
+	GetAllByIndex(index string) (value []T, err error)
+	GetAllByKey(key string, valueKey interface{}) (value []T, err error)
+	GetPageByKey(key string, valueKey interface{}, limit int, cursor string) (value []T, nextCursor string, err error)
+	GetPageByKeyDescending(key string, valueKey interface{}, limit int, cursor string) (value []T, nextCursor string, err error)
+	GetOneByKey(key string, valueKey interface{}) (value T, err error)
+	GetOneByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}) (value T, err error)
+	Create(value T) (err error)
+	CreateIfNotExists(value T, key string) (err error)
+	UpdateValue(key string, valueKey interface{}, keyToUpdate string, valueToUpdate interface{}) (updatedValue T, err error)
+	UpdateValues(key string, valueKey interface{}, valuesToUpdate map[string]interface{}) (updatedValue T, err error)
+	UpdateValuesByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}, valuesToUpdate map[string]interface{}) (updatedValue T, err error)
+	UpdateByKeys(key string, rangeKey string, update KeyedUpdate) (updatedValue T, err error)
+	UpdateAllByKeys(key string, rangeKey string, updates []KeyedUpdate) (err error)
+	DeleteByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}) (err error)
 }
 
-func NewDynamoAdapter(db DynamoDatabase) DynamoAdapter {
-	return &dynamoAdapter{
+// KeyedUpdate is the update of one item of a table with a composite key, as
+// written by UpdateByKeys and UpdateAllByKeys.
+type KeyedUpdate struct {
+	ValueKey       interface{}
+	ValueRangeKey  interface{}
+	ValuesToUpdate map[string]interface{}
+	// ValuesToAdd are added to the number attributes they name in the same
+	// write, so concurrent updates are never lost.
+	ValuesToAdd map[string]interface{}
+
+	// Version, when set, names a number attribute the update increments. With
+	// an ExpectedVersion the update only applies while the attribute still
+	// holds it, so an item changed since it was read is not overwritten.
+	Version         string
+	ExpectedVersion int64
+}
+
+func NewDynamoAdapter[T any](db DynamoDatabase) DynamoAdapter[T] {
+	return &dynamoAdapter[T]{
 		db:    db,
 		table: nil,
 	}
 }
 
-func (d *dynamoAdapter) SetTable(table string) {
+func (d *dynamoAdapter[T]) SetTable(table string) {
 	d.table = &table
 }
 
 // GetAll implements DynamoAdapter.
-func (d *dynamoAdapter) GetAll() (value []map[string]interface{}, err error) {
+func (d *dynamoAdapter[T]) GetAll() (value []T, err error) {
 	err = d.db.Table(*d.table).Scan().All(context.TODO(), &value)
 	return value, err
 }
 
-func (d *dynamoAdapter) GetOneByKey(key string, valueKey interface{}) (value map[string]interface{}, err error) {
+// GetAllByIndex returns every item of the given index, which reads only the
+// items of a sparse index instead of the whole table.
+func (d *dynamoAdapter[T]) GetAllByIndex(index string) (value []T, err error) {
+	err = d.db.Table(*d.table).Scan().Index(index).All(context.TODO(), &value)
+	return value, err
+}
+
+// GetAllByKey returns every item sharing the given hash key.
+func (d *dynamoAdapter[T]) GetAllByKey(key string, valueKey interface{}) (value []T, err error) {
+	err = d.db.Table(*d.table).Get(key, valueKey).All(context.TODO(), &value)
+	return value, err
+}
+
+// GetPageByKey reads up to limit items sharing the given hash key, starting
+// after the given cursor. The returned cursor is empty once every item was read.
+func (d *dynamoAdapter[T]) GetPageByKey(key string, valueKey interface{}, limit int, cursor string) (value []T, nextCursor string, err error) {
+	return d.getPageByKey(key, valueKey, limit, cursor, dynamo.Ascending)
+}
+
+// GetPageByKeyDescending reads pages like GetPageByKey, from the highest range
+// key down.
+func (d *dynamoAdapter[T]) GetPageByKeyDescending(key string, valueKey interface{}, limit int, cursor string) (value []T, nextCursor string, err error) {
+	return d.getPageByKey(key, valueKey, limit, cursor, dynamo.Descending)
+}
+
+func (d *dynamoAdapter[T]) getPageByKey(key string, valueKey interface{}, limit int, cursor string, order dynamo.Order) (value []T, nextCursor string, err error) {
+	startKey, err := decodeCursor(cursor)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	lastKey, err := d.db.Table(*d.table).Get(key, valueKey).Order(order).StartFrom(startKey).SearchLimit(limit).AllWithLastEvaluatedKey(context.TODO(), &value)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor, err = encodeCursor(lastKey)
+	return value, nextCursor, err
+}
+
+func (d *dynamoAdapter[T]) GetOneByKey(key string, valueKey interface{}) (value T, err error) {
 	err = d.db.Table(*d.table).Get(key, valueKey).One(context.TODO(), &value)
 	return
 }
 
-func (d *dynamoAdapter) Create(value interface{}) (err error) {
+func (d *dynamoAdapter[T]) GetOneByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}) (value T, err error) {
+	err = d.db.Table(*d.table).Get(key, valueKey).Range(rangeKey, dynamo.Equal, valueRangeKey).One(context.TODO(), &value)
+	return
+}
+
+func (d *dynamoAdapter[T]) Create(value T) (err error) {
 	err = d.db.Table(*d.table).Put(value).Run(context.TODO())
 	return
 }
 
-func (d *dynamoAdapter) UpdateValue(key string, valueKey interface{}, keyToUpdate string, valueToUpdate interface{}) (updatedValue map[string]interface{}, err error) {
+// CreateIfNotExists puts the value unless an item with the same key, named by
+// its hash key attribute, is already stored.
+func (d *dynamoAdapter[T]) CreateIfNotExists(value T, key string) (err error) {
+	err = d.db.Table(*d.table).Put(value).If("attribute_not_exists($)", key).Run(context.TODO())
+	return
+}
+
+func (d *dynamoAdapter[T]) UpdateValue(key string, valueKey interface{}, keyToUpdate string, valueToUpdate interface{}) (updatedValue T, err error) {
 	err = d.db.Table(*d.table).Update(key, valueKey).
 		Set(keyToUpdate, valueToUpdate).
 		Value(context.TODO(), &updatedValue)
 	return
 }
+
+func (d *dynamoAdapter[T]) UpdateValues(key string, valueKey interface{}, valuesToUpdate map[string]interface{}) (updatedValue T, err error) {
+	update := d.db.Table(*d.table).Update(key, valueKey)
+	for keyToUpdate, valueToUpdate := range valuesToUpdate {
+		update = update.Set(keyToUpdate, valueToUpdate)
+	}
+
+	err = update.Value(context.TODO(), &updatedValue)
+	return
+}
+
+// UpdateValuesByKeys updates an item of a table with a composite key. Unlike a
+// plain dynamo update it never creates the item when the keys do not match one.
+func (d *dynamoAdapter[T]) UpdateValuesByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}, valuesToUpdate map[string]interface{}) (updatedValue T, err error) {
+	update := d.db.Table(*d.table).Update(key, valueKey).
+		Range(rangeKey, valueRangeKey).
+		If("attribute_exists($)", key)
+	for keyToUpdate, valueToUpdate := range valuesToUpdate {
+		update = update.Set(keyToUpdate, valueToUpdate)
+	}
+
+	err = update.Value(context.TODO(), &updatedValue)
+	return
+}
+
+// UpdateByKeys updates an item of a table with a composite key, checking its
+// version when the update has one. Like UpdateValuesByKeys it never creates
+// the item.
+func (d *dynamoAdapter[T]) UpdateByKeys(key string, rangeKey string, update KeyedUpdate) (updatedValue T, err error) {
+	err = d.keyedUpdate(key, rangeKey, update).Value(context.TODO(), &updatedValue)
+	return
+}
+
+// UpdateAllByKeys updates items of a table with a composite key in a single
+// transaction, so either every item is updated or none is. Like
+// UpdateByKeys it never creates an item, failing the whole transaction when
+// the keys of an update do not match one or its version changed. The
+// transaction is idempotent, so DynamoDB applies a request it receives twice
+// only once.
+func (d *dynamoAdapter[T]) UpdateAllByKeys(key string, rangeKey string, updates []KeyedUpdate) (err error) {
+	tx := d.db.WriteTx().Idempotent(true)
+	for _, keyedUpdate := range updates {
+		tx = tx.Update(d.keyedUpdate(key, rangeKey, keyedUpdate))
+	}
+
+	err = tx.Run(context.TODO())
+	return
+}
+
+func (d *dynamoAdapter[T]) keyedUpdate(key string, rangeKey string, keyedUpdate KeyedUpdate) *dynamo.Update {
+	update := d.db.Table(*d.table).Update(key, keyedUpdate.ValueKey).
+		Range(rangeKey, keyedUpdate.ValueRangeKey).
+		If("attribute_exists($)", key)
+	for keyToUpdate, valueToUpdate := range keyedUpdate.ValuesToUpdate {
+		update = update.Set(keyToUpdate, valueToUpdate)
+	}
+
+	for keyToAdd, valueToAdd := range keyedUpdate.ValuesToAdd {
+		update = update.Add(keyToAdd, valueToAdd)
+	}
+
+	if keyedUpdate.Version != "" {
+		if keyedUpdate.ExpectedVersion != 0 {
+			update = update.If("$ = ?", keyedUpdate.Version, keyedUpdate.ExpectedVersion)
+		}
+
+		update = update.Add(keyedUpdate.Version, 1)
+	}
+
+	return update
+}
+
+// DeleteByKeys deletes an item of a table with a composite key. Deleting an
+// item that is not stored is not an error.
+func (d *dynamoAdapter[T]) DeleteByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}) (err error) {
+	err = d.db.Table(*d.table).Delete(key, valueKey).Range(rangeKey, valueRangeKey).Run(context.TODO())
+	return
+}
@@ -0,0 +1,80 @@
+package external
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/guregu/dynamo/v2"
+)
+
+// pagingKeyAttribute is the JSON form of a key attribute inside a cursor. Only
+// string and number keys are supported, which covers every table of the
+// service.
+type pagingKeyAttribute struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+}
+
+// encodeCursor turns a DynamoDB LastEvaluatedKey into an opaque string that can
+// be handed to API clients. An empty cursor means there are no more pages.
+func encodeCursor(key dynamo.PagingKey) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	attributes := map[string]pagingKeyAttribute{}
+	for name, value := range key {
+		switch typed := value.(type) {
+		case *types.AttributeValueMemberS:
+			attributes[name] = pagingKeyAttribute{S: &typed.Value}
+		case *types.AttributeValueMemberN:
+			attributes[name] = pagingKeyAttribute{N: &typed.Value}
+		default:
+			return "", fmt.Errorf("unsupported paging key attribute %s", name)
+		}
+	}
+
+	encoded, err := json.Marshal(attributes)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+func decodeCursor(cursor string) (dynamo.PagingKey, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+
+	attributes := map[string]pagingKeyAttribute{}
+	err = json.Unmarshal(decoded, &attributes)
+
+	if err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+
+	key := dynamo.PagingKey{}
+	for name, attribute := range attributes {
+		switch {
+		case attribute.S != nil:
+			key[name] = &types.AttributeValueMemberS{Value: *attribute.S}
+		case attribute.N != nil:
+			key[name] = &types.AttributeValueMemberN{Value: *attribute.N}
+		default:
+			return nil, repository.ErrInvalidCursor
+		}
+	}
+
+	return key, nil
+}
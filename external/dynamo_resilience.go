@@ -0,0 +1,294 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// ErrCircuitOpen is returned without calling DynamoDB while the circuit breaker
+// is open.
+var ErrCircuitOpen = errors.New("dynamodb is unavailable, circuit breaker is open")
+
+const (
+	circuitClosed = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker stops calls to DynamoDB after too many consecutive transient
+// failures. Once open it fails every call until the open timeout passes, then
+// lets a single call through to find out whether DynamoDB recovered.
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker builds the breaker shared by every adapter of a DynamoDB
+// connection. A zero failure threshold disables it.
+func NewCircuitBreaker(cfg DynamoConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		failureThreshold: cfg.BreakerFailures,
+		openTimeout:      cfg.BreakerOpenTimeout,
+		now:              time.Now,
+	}
+}
+
+// Healthy tells whether calls are reaching DynamoDB, which is what readiness
+// reports.
+func (b *CircuitBreaker) Healthy() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state != circuitOpen || b.now().Sub(b.openedAt) >= b.openTimeout
+}
+
+func (b *CircuitBreaker) allow() error {
+	if b.failureThreshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+
+		b.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		return ErrCircuitOpen
+	}
+
+	return nil
+}
+
+func (b *CircuitBreaker) record(err error) {
+	if b.failureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isTransient(err) {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.failureThreshold {
+		if b.state != circuitOpen {
+			log.Printf("dynamodb circuit breaker opened after %d failures: %s", b.failures, err.Error())
+		}
+
+		b.state = circuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// isTransient tells the errors worth retrying, such as throttling, timeouts and
+// 5xx answers, using the classification of the AWS SDK.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// isUnapplied tells the errors showing a write was refused before DynamoDB
+// applied it, which are the throttling ones. Any other failure may come after
+// the write was applied, so resending it could apply it twice or fail its
+// condition against its own result.
+func isUnapplied(err error) bool {
+	return err != nil && retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// resilientDynamoAdapter retries the reads of another adapter that fail with
+// transient errors, and its writes only when they were throttled, waiting an
+// exponential backoff with full jitter between the attempts. It stops calling
+// the adapter while the circuit breaker is open.
+type resilientDynamoAdapter[T any] struct {
+	adapter     DynamoAdapter[T]
+	breaker     *CircuitBreaker
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	sleep       func(time.Duration)
+}
+
+// NewResilientDynamoAdapter decorates an adapter with the retries and the
+// circuit breaker configured for DynamoDB.
+func NewResilientDynamoAdapter[T any](adapter DynamoAdapter[T], cfg DynamoConfig, breaker *CircuitBreaker) DynamoAdapter[T] {
+	return &resilientDynamoAdapter[T]{
+		adapter:     adapter,
+		breaker:     breaker,
+		maxAttempts: cfg.MaxAttempts,
+		baseDelay:   cfg.RetryBaseDelay,
+		maxDelay:    cfg.RetryMaxDelay,
+		sleep:       time.Sleep,
+	}
+}
+
+// withRetries makes the call until it succeeds, fails with an error retryable
+// does not accept or the attempts run out.
+func withRetries[T any, V any](r *resilientDynamoAdapter[T], retryable func(error) bool, call func() (V, error)) (value V, err error) {
+	for attempt := 1; ; attempt++ {
+		err = r.breaker.allow()
+
+		if err != nil {
+			return value, err
+		}
+
+		value, err = call()
+		r.breaker.record(err)
+
+		if !retryable(err) || attempt >= r.maxAttempts {
+			return value, err
+		}
+
+		r.sleep(r.backoff(attempt))
+	}
+}
+
+// backoff returns a random wait between zero and the exponential delay of the
+// attempt, capped by the max delay.
+func (r *resilientDynamoAdapter[T]) backoff(attempt int) time.Duration {
+	delay := r.baseDelay << (attempt - 1)
+	if delay <= 0 || delay > r.maxDelay {
+		delay = r.maxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+func (r *resilientDynamoAdapter[T]) SetTable(table string) {
+	r.adapter.SetTable(table)
+}
+
+func (r *resilientDynamoAdapter[T]) GetAll() (value []T, err error) {
+	return withRetries(r, isTransient, func() ([]T, error) {
+		return r.adapter.GetAll()
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) GetAllByIndex(index string) (value []T, err error) {
+	return withRetries(r, isTransient, func() ([]T, error) {
+		return r.adapter.GetAllByIndex(index)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) GetAllByKey(key string, valueKey interface{}) (value []T, err error) {
+	return withRetries(r, isTransient, func() ([]T, error) {
+		return r.adapter.GetAllByKey(key, valueKey)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) GetPageByKey(key string, valueKey interface{}, limit int, cursor string) (value []T, nextCursor string, err error) {
+	value, err = withRetries(r, isTransient, func() ([]T, error) {
+		var pageErr error
+		value, nextCursor, pageErr = r.adapter.GetPageByKey(key, valueKey, limit, cursor)
+		return value, pageErr
+	})
+
+	return value, nextCursor, err
+}
+
+func (r *resilientDynamoAdapter[T]) GetPageByKeyDescending(key string, valueKey interface{}, limit int, cursor string) (value []T, nextCursor string, err error) {
+	value, err = withRetries(r, isTransient, func() ([]T, error) {
+		var pageErr error
+		value, nextCursor, pageErr = r.adapter.GetPageByKeyDescending(key, valueKey, limit, cursor)
+		return value, pageErr
+	})
+
+	return value, nextCursor, err
+}
+
+func (r *resilientDynamoAdapter[T]) GetOneByKey(key string, valueKey interface{}) (value T, err error) {
+	return withRetries(r, isTransient, func() (T, error) {
+		return r.adapter.GetOneByKey(key, valueKey)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) GetOneByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}) (value T, err error) {
+	return withRetries(r, isTransient, func() (T, error) {
+		return r.adapter.GetOneByKeys(key, valueKey, rangeKey, valueRangeKey)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) Create(value T) (err error) {
+	_, err = withRetries(r, isUnapplied, func() (struct{}, error) {
+		return struct{}{}, r.adapter.Create(value)
+	})
+
+	return err
+}
+
+func (r *resilientDynamoAdapter[T]) CreateIfNotExists(value T, key string) (err error) {
+	_, err = withRetries(r, isUnapplied, func() (struct{}, error) {
+		return struct{}{}, r.adapter.CreateIfNotExists(value, key)
+	})
+
+	return err
+}
+
+func (r *resilientDynamoAdapter[T]) UpdateValue(key string, valueKey interface{}, keyToUpdate string, valueToUpdate interface{}) (updatedValue T, err error) {
+	return withRetries(r, isUnapplied, func() (T, error) {
+		return r.adapter.UpdateValue(key, valueKey, keyToUpdate, valueToUpdate)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) UpdateValues(key string, valueKey interface{}, valuesToUpdate map[string]interface{}) (updatedValue T, err error) {
+	return withRetries(r, isUnapplied, func() (T, error) {
+		return r.adapter.UpdateValues(key, valueKey, valuesToUpdate)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) UpdateValuesByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}, valuesToUpdate map[string]interface{}) (updatedValue T, err error) {
+	return withRetries(r, isUnapplied, func() (T, error) {
+		return r.adapter.UpdateValuesByKeys(key, valueKey, rangeKey, valueRangeKey, valuesToUpdate)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) UpdateByKeys(key string, rangeKey string, update KeyedUpdate) (updatedValue T, err error) {
+	return withRetries(r, isUnapplied, func() (T, error) {
+		return r.adapter.UpdateByKeys(key, rangeKey, update)
+	})
+}
+
+func (r *resilientDynamoAdapter[T]) UpdateAllByKeys(key string, rangeKey string, updates []KeyedUpdate) (err error) {
+	_, err = withRetries(r, isUnapplied, func() (struct{}, error) {
+		return struct{}{}, r.adapter.UpdateAllByKeys(key, rangeKey, updates)
+	})
+
+	return err
+}
+
+func (r *resilientDynamoAdapter[T]) DeleteByKeys(key string, valueKey interface{}, rangeKey string, valueRangeKey interface{}) (err error) {
+	_, err = withRetries(r, isUnapplied, func() (struct{}, error) {
+		return struct{}{}, r.adapter.DeleteByKeys(key, valueKey, rangeKey, valueRangeKey)
+	})
+
+	return err
+}
@@ -0,0 +1,188 @@
+package external
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var throttlingError = &smithy.GenericAPIError{Code: "ProvisionedThroughputExceededException"}
+
+// timeoutError is transient but tells nothing of whether a write was applied.
+var timeoutError = &smithy.GenericAPIError{Code: "RequestTimeout"}
+
+// flakyAdapter fails its calls with the given errors, in order, before
+// succeeding.
+type flakyAdapter struct {
+	DynamoAdapter[map[string]interface{}]
+	errs  []error
+	calls int
+}
+
+func (f *flakyAdapter) GetOneByKey(key string, valueKey interface{}) (map[string]interface{}, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+
+	return map[string]interface{}{"ID": valueKey}, nil
+}
+
+func (f *flakyAdapter) nextError() error {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+
+	return nil
+}
+
+func (f *flakyAdapter) CreateIfNotExists(value map[string]interface{}, key string) error {
+	return f.nextError()
+}
+
+func (f *flakyAdapter) UpdateByKeys(key string, rangeKey string, update KeyedUpdate) (map[string]interface{}, error) {
+	return nil, f.nextError()
+}
+
+func (f *flakyAdapter) UpdateAllByKeys(key string, rangeKey string, updates []KeyedUpdate) error {
+	return f.nextError()
+}
+
+func resilientAdapter(adapter DynamoAdapter[map[string]interface{}], breaker *CircuitBreaker) (*resilientDynamoAdapter[map[string]interface{}], *[]time.Duration) {
+	waits := []time.Duration{}
+	resilient := NewResilientDynamoAdapter(adapter, DynamoConfig{
+		MaxAttempts:    3,
+		RetryBaseDelay: 10 * time.Millisecond,
+		RetryMaxDelay:  15 * time.Millisecond,
+	}, breaker).(*resilientDynamoAdapter[map[string]interface{}])
+	resilient.sleep = func(wait time.Duration) {
+		waits = append(waits, wait)
+	}
+
+	return resilient, &waits
+}
+
+func TestResilientDynamoAdapterRetries(t *testing.T) {
+	adapter := &flakyAdapter{errs: []error{throttlingError, throttlingError}}
+	resilient, waits := resilientAdapter(adapter, NewCircuitBreaker(DynamoConfig{}))
+
+	got, err := resilient.GetOneByKey("ID", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ID": 1}, got)
+	assert.Equal(t, 3, adapter.calls)
+	assert.Len(t, *waits, 2)
+	assert.LessOrEqual(t, (*waits)[0], 10*time.Millisecond)
+	assert.LessOrEqual(t, (*waits)[1], 15*time.Millisecond)
+}
+
+func TestResilientDynamoAdapterGivesUp(t *testing.T) {
+	adapter := &flakyAdapter{errs: []error{throttlingError, throttlingError, throttlingError, throttlingError}}
+	resilient, _ := resilientAdapter(adapter, NewCircuitBreaker(DynamoConfig{}))
+
+	_, err := resilient.GetOneByKey("ID", 1)
+
+	assert.Equal(t, throttlingError, err)
+	assert.Equal(t, 3, adapter.calls)
+}
+
+func TestResilientDynamoAdapterDoesNotRetryPermanentErrors(t *testing.T) {
+	notFound := errors.New("dynamo: no item found")
+	adapter := &flakyAdapter{errs: []error{notFound}}
+	resilient, waits := resilientAdapter(adapter, NewCircuitBreaker(DynamoConfig{}))
+
+	_, err := resilient.GetOneByKey("ID", 1)
+
+	assert.Equal(t, notFound, err)
+	assert.Equal(t, 1, adapter.calls)
+	assert.Empty(t, *waits)
+}
+
+func TestResilientDynamoAdapterRetriesReadsAfterATimeout(t *testing.T) {
+	adapter := &flakyAdapter{errs: []error{timeoutError}}
+	resilient, _ := resilientAdapter(adapter, NewCircuitBreaker(DynamoConfig{}))
+
+	_, err := resilient.GetOneByKey("ID", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, adapter.calls)
+}
+
+func TestResilientDynamoAdapterDoesNotResendAmbiguousWrites(t *testing.T) {
+	for name, write := range map[string]func(DynamoAdapter[map[string]interface{}]) error{
+		"create": func(resilient DynamoAdapter[map[string]interface{}]) error {
+			return resilient.CreateIfNotExists(map[string]interface{}{"ID": 1}, "ID")
+		},
+		"versioned update": func(resilient DynamoAdapter[map[string]interface{}]) error {
+			_, err := resilient.UpdateByKeys("StoreID", "ID", KeyedUpdate{ValueKey: "loja-1", ValueRangeKey: 1, Version: "Version", ExpectedVersion: 2})
+			return err
+		},
+		"counter": func(resilient DynamoAdapter[map[string]interface{}]) error {
+			_, err := resilient.UpdateByKeys("StoreId", "SubscriptionId", KeyedUpdate{ValueKey: "loja-1", ValueRangeKey: "sub-1", ValuesToAdd: map[string]interface{}{"ConsecutiveFailures": 1}})
+			return err
+		},
+		"transaction": func(resilient DynamoAdapter[map[string]interface{}]) error {
+			return resilient.UpdateAllByKeys("StoreID", "ID", []KeyedUpdate{{ValueKey: "loja-1", ValueRangeKey: 1}})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			adapter := &flakyAdapter{errs: []error{timeoutError}}
+			resilient, waits := resilientAdapter(adapter, NewCircuitBreaker(DynamoConfig{}))
+
+			assert.Equal(t, timeoutError, write(resilient), "a write that may have been applied is not sent again")
+			assert.Equal(t, 1, adapter.calls)
+			assert.Empty(t, *waits)
+
+			adapter = &flakyAdapter{errs: []error{throttlingError}}
+			resilient, _ = resilientAdapter(adapter, NewCircuitBreaker(DynamoConfig{}))
+
+			assert.NoError(t, write(resilient), "a throttled write was not applied and is sent again")
+			assert.Equal(t, 2, adapter.calls)
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(DynamoConfig{BreakerFailures: 3, BreakerOpenTimeout: 30 * time.Second})
+	breaker.now = func() time.Time {
+		return now
+	}
+
+	adapter := &flakyAdapter{errs: []error{throttlingError, throttlingError, throttlingError, throttlingError}}
+	resilient, _ := resilientAdapter(adapter, breaker)
+
+	_, err := resilient.GetOneByKey("ID", 1)
+
+	assert.Equal(t, throttlingError, err)
+	assert.False(t, breaker.Healthy())
+
+	_, err = resilient.GetOneByKey("ID", 1)
+
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, 3, adapter.calls)
+
+	now = now.Add(31 * time.Second)
+	assert.True(t, breaker.Healthy())
+
+	_, err = resilient.GetOneByKey("ID", 1)
+
+	assert.Equal(t, ErrCircuitOpen, err, "a failed trial call opens the breaker again")
+	assert.Equal(t, 4, adapter.calls)
+	assert.False(t, breaker.Healthy())
+
+	now = now.Add(31 * time.Second)
+	got, err := resilient.GetOneByKey("ID", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ID": 1}, got)
+	assert.True(t, breaker.Healthy())
+}
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS production_orders (
+    store_id   TEXT        NOT NULL,
+    order_id   BIGINT      NOT NULL,
+    status     TEXT        NOT NULL,
+    priority   TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ,
+    items      JSONB       NOT NULL DEFAULT '[]',
+    tickets    JSONB       NOT NULL DEFAULT '[]',
+    history    JSONB       NOT NULL DEFAULT '[]',
+    version    BIGINT      NOT NULL DEFAULT 1,
+    PRIMARY KEY (store_id, order_id)
+);
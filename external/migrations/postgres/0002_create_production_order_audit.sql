@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS production_order_audit (
+    store_id        TEXT        NOT NULL,
+    order_id        BIGINT      NOT NULL,
+    entry_id        TEXT        NOT NULL,
+    at              TIMESTAMPTZ NOT NULL,
+    action          TEXT        NOT NULL,
+    actor           TEXT        NOT NULL,
+    station         TEXT        NOT NULL DEFAULT '',
+    previous_status TEXT        NOT NULL,
+    new_status      TEXT        NOT NULL,
+    request_id      TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (store_id, order_id, entry_id)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS sync_outbox (
+    id              BIGSERIAL   PRIMARY KEY,
+    kind            TEXT        NOT NULL,
+    store_id        TEXT        NOT NULL,
+    order_id        BIGINT      NOT NULL,
+    payload         JSONB       NOT NULL,
+    queued_at       TIMESTAMPTZ NOT NULL,
+    rejected_reason TEXT        NOT NULL DEFAULT ''
+);
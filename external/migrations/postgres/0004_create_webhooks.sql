@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    store_id             TEXT        NOT NULL,
+    subscription_id      TEXT        NOT NULL,
+    url                  TEXT        NOT NULL,
+    events               JSONB       NOT NULL DEFAULT '[]',
+    secret               TEXT        NOT NULL,
+    enabled              BOOLEAN     NOT NULL,
+    consecutive_failures INTEGER     NOT NULL DEFAULT 0,
+    disabled_at          TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (store_id, subscription_id)
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    store_id        TEXT        NOT NULL,
+    subscription_id TEXT        NOT NULL,
+    delivery_id     TEXT        NOT NULL,
+    event_id        TEXT        NOT NULL,
+    event           TEXT        NOT NULL,
+    attempt         INTEGER     NOT NULL,
+    at              TIMESTAMPTZ NOT NULL,
+    status_code     INTEGER     NOT NULL DEFAULT 0,
+    error           TEXT        NOT NULL DEFAULT '',
+    delivered       BOOLEAN     NOT NULL,
+    PRIMARY KEY (store_id, subscription_id, delivery_id)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS production_orders (
+    store_id   TEXT     NOT NULL,
+    order_id   INTEGER  NOT NULL,
+    status     TEXT     NOT NULL,
+    priority   TEXT     NOT NULL DEFAULT '',
+    created_at DATETIME,
+    items      TEXT     NOT NULL DEFAULT '[]',
+    tickets    TEXT     NOT NULL DEFAULT '[]',
+    history    TEXT     NOT NULL DEFAULT '[]',
+    version    INTEGER  NOT NULL DEFAULT 1,
+    PRIMARY KEY (store_id, order_id)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS sync_outbox (
+    id              INTEGER  PRIMARY KEY AUTOINCREMENT,
+    kind            TEXT     NOT NULL,
+    store_id        TEXT     NOT NULL,
+    order_id        INTEGER  NOT NULL,
+    payload         TEXT     NOT NULL,
+    queued_at       DATETIME NOT NULL,
+    rejected_reason TEXT     NOT NULL DEFAULT ''
+);
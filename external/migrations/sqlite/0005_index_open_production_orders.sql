@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS production_orders_open ON production_orders (store_id, order_id)
+    WHERE status NOT IN ('FINALIZADO', 'CANCELADO');
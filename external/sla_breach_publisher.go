@@ -0,0 +1,41 @@
+package external
+
+import (
+	"encoding/json"
+	"expvar"
+	"log"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher"
+)
+
+var slaBreaches = expvar.NewMap("production_sla_breaches")
+
+type logSLABreachPublisher struct{}
+
+// NewLogSLABreachPublisher publishes breaches as structured log lines and counts
+// them per status in the production_sla_breaches expvar metric.
+func NewLogSLABreachPublisher() publisher.SLABreachPublisher {
+	return &logSLABreachPublisher{}
+}
+
+func (l *logSLABreachPublisher) PublishSLABreach(breach entities.SLABreach) error {
+	event, err := json.Marshal(map[string]interface{}{
+		"event":           "production_order.sla_breached",
+		"store_id":        breach.StoreId,
+		"order_id":        breach.OrderId,
+		"status":          breach.Status,
+		"since":           breach.Since,
+		"elapsed_seconds": int64(breach.Elapsed.Seconds()),
+		"target_seconds":  int64(breach.Target.Seconds()),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	log.Println(string(event))
+	slaBreaches.Add(breach.Status, 1)
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package external
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrations embed.FS
+
+// migrationLockId identifies the advisory lock held while migrating, so pods
+// starting together do not apply the same migration twice.
+const migrationLockId = 350035
+
+// migrationDialect tells where the migrations of a database are and how to
+// write the statements recording them.
+type migrationDialect struct {
+	dir string
+	// placeholder replaces $1 in the statements run by the migrator.
+	placeholder string
+	// lock is run with migrationLockId at the start of every migration, when
+	// the database can be migrated by several processes at once.
+	lock string
+}
+
+var (
+	postgresMigrationDialect = migrationDialect{
+		dir:         "migrations/postgres",
+		placeholder: "$1",
+		lock:        "SELECT pg_advisory_xact_lock($1)",
+	}
+	sqliteMigrationDialect = migrationDialect{
+		dir:         "migrations/sqlite",
+		placeholder: "?1",
+	}
+)
+
+func ConectaPostgres(config Config) *sql.DB {
+	db, err := sql.Open("pgx", config.DatabaseConfig.PostgresURL())
+
+	if err != nil {
+		log.Println(err.Error())
+		log.Panic("Erro na conexao com banco de dados")
+	}
+
+	db.SetMaxOpenConns(config.DatabaseConfig.MaxOpenConns)
+	db.SetConnMaxIdleTime(5 * time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = db.PingContext(ctx)
+
+	if err != nil {
+		log.Println(err.Error())
+		log.Panic("Erro na conexao com banco de dados")
+	}
+
+	if config.DatabaseConfig.AutoMigrate {
+		err = MigratePostgres(ctx, db)
+
+		if err != nil {
+			log.Println(err.Error())
+			log.Panic("Erro ao migrar o banco de dados")
+		}
+	}
+
+	return db
+}
+
+// ConectaSQLite opens the embedded database file of single store deployments.
+// SQLite allows one writer at a time, so the pool keeps a single connection.
+func ConectaSQLite(config Config) *sql.DB {
+	dsn := "file:" + config.DatabaseConfig.Path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+
+	if err != nil {
+		log.Println(err.Error())
+		log.Panic("Erro na conexao com banco de dados")
+	}
+
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = db.PingContext(ctx)
+
+	if err != nil {
+		log.Println(err.Error())
+		log.Panic("Erro na conexao com banco de dados")
+	}
+
+	if config.DatabaseConfig.AutoMigrate {
+		err = MigrateSQLite(ctx, db)
+
+		if err != nil {
+			log.Println(err.Error())
+			log.Panic("Erro ao migrar o banco de dados")
+		}
+	}
+
+	return db
+}
+
+// MigratePostgres applies the embedded migrations not applied yet, in the order
+// of their file names. Each migration runs in its own transaction and is
+// recorded in schema_migrations.
+func MigratePostgres(ctx context.Context, db *sql.DB) error {
+	return migrate(ctx, db, postgresMigrationDialect)
+}
+
+// MigrateSQLite applies the embedded SQLite migrations the same way
+// MigratePostgres does.
+func MigrateSQLite(ctx context.Context, db *sql.DB) error {
+	return migrate(ctx, db, sqliteMigrationDialect)
+}
+
+func migrate(ctx context.Context, db *sql.DB, dialect migrationDialect) error {
+	names, err := fs.Glob(migrations, dialect.dir+"/*.sql")
+
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(names)
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT        PRIMARY KEY,
+		applied_at TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+
+	if err != nil {
+		return fmt.Errorf("could not create schema_migrations: %w", err)
+	}
+
+	for _, name := range names {
+		err = applyMigration(ctx, db, dialect, name)
+
+		if err != nil {
+			return fmt.Errorf("could not apply migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, dialect migrationDialect, name string) error {
+	version := strings.TrimSuffix(name[strings.LastIndex(name, "/")+1:], ".sql")
+	bind := func(query string) string {
+		return strings.ReplaceAll(query, "$1", dialect.placeholder)
+	}
+
+	statement, err := migrations.ReadFile(name)
+
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if dialect.lock != "" {
+		_, err = tx.ExecContext(ctx, bind(dialect.lock), migrationLockId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var applied bool
+	err = tx.QueryRowContext(ctx, bind("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)"), version).Scan(&applied)
+
+	if err != nil || applied {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, string(statement))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, bind("INSERT INTO schema_migrations (version) VALUES ($1)"), version)
+
+	if err != nil {
+		return err
+	}
+
+	log.Printf("applied migration %s", version)
+
+	return tx.Commit()
+}
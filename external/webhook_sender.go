@@ -0,0 +1,122 @@
+package external
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher"
+)
+
+const (
+	WEBHOOK_EVENT_HEADER     = "X-Webhook-Event"
+	WEBHOOK_ID_HEADER        = "X-Webhook-Id"
+	WEBHOOK_TIMESTAMP_HEADER = "X-Webhook-Timestamp"
+	WEBHOOK_SIGNATURE_HEADER = "X-Webhook-Signature"
+)
+
+type httpWebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewHTTPWebhookSender posts the events as JSON, waiting up to timeout for the
+// answer. Redirects are not followed, so an event is only posted to the URL
+// of the subscription, and connections are only made to the addresses the
+// policy allows, whatever the host name of the URL resolves to. Proxies are
+// not used, as the address connected to would be the one of the proxy.
+func NewHTTPWebhookSender(timeout time.Duration, policy entities.WebhookPolicy) publisher.WebhookSender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !policy.AllowsAddress(ip) {
+				return fmt.Errorf("webhook address %s is not allowed", host)
+			}
+
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &httpWebhookSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts the event to the subscription, signed with its secret, and fails
+// unless the subscriber answers with a 2xx status.
+func (h *httpWebhookSender) Send(subscription entities.WebhookSubscription, event entities.StatusChangeEvent) (int, error) {
+	body, err := json.Marshal(event)
+
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(h.now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "fastfood-order-production-webhooks")
+	request.Header.Set(WEBHOOK_EVENT_HEADER, event.Event)
+	request.Header.Set(WEBHOOK_ID_HEADER, event.EventId)
+	request.Header.Set(WEBHOOK_TIMESTAMP_HEADER, timestamp)
+	request.Header.Set(WEBHOOK_SIGNATURE_HEADER, SignWebhook(subscription.Secret, timestamp, body))
+
+	response, err := h.client.Do(request)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("subscriber answered %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// SignWebhook returns the signature of a webhook body sent at the given unix
+// timestamp: the hex HMAC-SHA256, keyed with the secret of the subscription, of
+// the timestamp and the body joined by a dot. Subscribers compute it again to
+// check an event came from this service and reject old timestamps to stop
+// replays.
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,74 @@
+package external
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPWebhookSender(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	answer := http.StatusNoContent
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(answer)
+	}))
+	defer subscriber.Close()
+
+	sender := NewHTTPWebhookSender(time.Second, entities.WebhookPolicy{AllowInsecureURLs: true}).(*httpWebhookSender)
+	sender.now = func() time.Time { return time.Unix(1728561600, 0) }
+	subscription := entities.WebhookSubscription{URL: subscriber.URL, Secret: "0123456789abcdef"}
+	event := entities.StatusChangeEvent{EventId: "loja-1#1#2024", Event: entities.ORDER_READY_EVENT, StoreId: "loja-1", OrderId: 1, Status: "PRONTO"}
+
+	statusCode, err := sender.Send(subscription, event)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, statusCode)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, entities.ORDER_READY_EVENT, received.Header.Get(WEBHOOK_EVENT_HEADER))
+	assert.Equal(t, "loja-1#1#2024", received.Header.Get(WEBHOOK_ID_HEADER))
+	assert.Equal(t, "1728561600", received.Header.Get(WEBHOOK_TIMESTAMP_HEADER))
+	assert.Equal(t, SignWebhook("0123456789abcdef", "1728561600", body), received.Header.Get(WEBHOOK_SIGNATURE_HEADER))
+	assert.Contains(t, string(body), `"Status":"PRONTO"`)
+
+	answer = http.StatusServiceUnavailable
+
+	statusCode, err = sender.Send(subscription, event)
+
+	assert.EqualError(t, err, "subscriber answered 503")
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode)
+}
+
+func TestHTTPWebhookSenderRefusesPrivateAddresses(t *testing.T) {
+	posted := false
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted = true
+	}))
+	defer subscriber.Close()
+
+	sender := NewHTTPWebhookSender(time.Second, entities.WebhookPolicy{})
+	subscription := entities.WebhookSubscription{URL: subscriber.URL, Secret: "0123456789abcdef"}
+
+	statusCode, err := sender.Send(subscription, entities.StatusChangeEvent{Event: entities.ORDER_READY_EVENT})
+
+	assert.ErrorContains(t, err, "webhook address 127.0.0.1 is not allowed")
+	assert.Zero(t, statusCode)
+	assert.False(t, posted)
+}
+
+func TestSignWebhook(t *testing.T) {
+	signature := SignWebhook("0123456789abcdef", "1728561600", []byte(`{"OrderId":1}`))
+
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+	assert.Equal(t, signature, SignWebhook("0123456789abcdef", "1728561600", []byte(`{"OrderId":1}`)))
+	assert.NotEqual(t, signature, SignWebhook("0123456789abcdef", "1728561601", []byte(`{"OrderId":1}`)))
+	assert.NotEqual(t, signature, SignWebhook("fedcba9876543210", "1728561600", []byte(`{"OrderId":1}`)))
+}
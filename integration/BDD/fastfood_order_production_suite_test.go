@@ -1,13 +1,111 @@
 package main_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/server"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/gateways"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
+// bddSecret signs the tokens of the suite. A live server given by BDD_BASE_URL
+// must be started with it, as docker-compose-test.yml does.
+const bddSecret = "bdd-secret"
+
+var baseURL string
+
 func TestFastfoodOrderProduction(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "FastfoodOrderProduction Suite")
 }
+
+// The suite runs against the server of BDD_BASE_URL when it is set, and
+// otherwise against the app started in-process on in-memory repositories.
+var _ = BeforeSuite(func() {
+	baseURL = os.Getenv("BDD_BASE_URL")
+
+	if baseURL != "" {
+		return
+	}
+
+	for key, value := range map[string]string{
+		"AUTH_HMAC_SECRET": bddSecret,
+		"LIMITS_RATE":      "0",
+		// The partner of the webhook scenario listens on http://127.0.0.1.
+		"WEBHOOK_ALLOW_INSECURE_URLS": "true",
+	} {
+		Expect(os.Setenv(key, value)).To(Succeed())
+	}
+
+	cfg, err := external.LoadConfig()
+	Expect(err).NotTo(HaveOccurred())
+
+	repositories := gateways.NewMemoryRepositories()
+	webhooks := server.NewWebhookDispatcher(cfg, repositories)
+	go webhooks.Dispatch()
+
+	testServer := httptest.NewServer(server.NewApp(cfg, repositories, server.NewProductionOrderUseCase(cfg, repositories, webhooks)))
+	DeferCleanup(testServer.Close)
+	baseURL = testServer.URL
+})
+
+// bearerToken signs a token for the role.
+func bearerToken(role string) string {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "bdd",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{role},
+	}).SignedString([]byte(bddSecret))
+
+	return "Bearer " + token
+}
+
+var storeSequence atomic.Int64
+
+// newStore returns a store of its own for a spec, so specs do not see the orders
+// of each other.
+func newStore() string {
+	return fmt.Sprintf("bdd-%d-%d", time.Now().UnixNano(), storeSequence.Add(1))
+}
+
+// call sends a request to the production routes of the store on the first
+// version of the API as the role and decodes the JSON response into out, when
+// given.
+func call(method string, storeId string, path string, role string, body string, out interface{}) *http.Response {
+	return callVersion("/v1", method, storeId, path, role, body, out)
+}
+
+// callVersion sends a request like call to the production routes of the store
+// under the version prefix, an empty prefix reaching the legacy routes.
+func callVersion(version string, method string, storeId string, path string, role string, body string, out interface{}) *http.Response {
+	req, err := http.NewRequest(method, fmt.Sprintf("%s%s/stores/%s/production%s", baseURL, version, storeId, path), strings.NewReader(body))
+	Expect(err).NotTo(HaveOccurred())
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", bearerToken(role))
+	res, err := http.DefaultClient.Do(req)
+	Expect(err).NotTo(HaveOccurred())
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	Expect(err).NotTo(HaveOccurred())
+
+	if out != nil {
+		Expect(json.Unmarshal(data, out)).To(Succeed(), string(data))
+	}
+
+	return res
+}
@@ -1,32 +1,339 @@
 package main_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
-	"strings"
+	"net/http/httptest"
+	"os"
 
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
 	. "github.com/onsi/ginkgo/v2"
-	"github.com/stretchr/testify/assert"
+	. "github.com/onsi/gomega"
 )
 
+func sendOrder(storeId string, orderId uint32, priority string) (entities.ProductionOrder, *http.Response) {
+	order := entities.ProductionOrder{}
+	res := call(http.MethodPost, storeId, "/order/send", "service", fmt.Sprintf(`{"order_id": %d, "priority": %q}`, orderId, priority), &order)
+
+	return order, res
+}
+
+func changeStatus(storeId string, orderId uint32, status string) (entities.ProductionOrder, *http.Response) {
+	order := entities.ProductionOrder{}
+	res := call(http.MethodPut, storeId, fmt.Sprintf("/order/%d/status", orderId), "kitchen", fmt.Sprintf(`{"status": %q}`, status), &order)
+
+	return order, res
+}
+
+func queueOrderIds(storeId string) []uint32 {
+	queue := []entities.ProductionOrder{}
+	res := call(http.MethodGet, storeId, "/queue", "kitchen", "", &queue)
+	Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+	orderIds := []uint32{}
+	for _, order := range queue {
+		orderIds = append(orderIds, order.OrderId)
+	}
+
+	return orderIds
+}
+
 var _ = Describe("Fila de Produção - Pedidos", func() {
+	var storeId string
+
+	BeforeEach(func() {
+		storeId = newStore()
+	})
+
+	Context("Serviço de pedidos envia um pedido para produção", func() {
+		It("o pedido deve entrar na fila como recebido", func() {
+			order, res := sendOrder(storeId, 99, "")
+
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(order.OrderId).To(Equal(uint32(99)))
+			Expect(order.Status).To(Equal(entities.RECEIVED_STATUS))
+			Expect(order.Priority).To(Equal(entities.NORMAL_PRIORITY))
+			Expect(order.History).To(HaveLen(1))
+			Expect(queueOrderIds(storeId)).To(Equal([]uint32{99}))
+		})
+
+		When("o pedido já foi enviado", func() {
+			BeforeEach(func() {
+				_, res := sendOrder(storeId, 99, "")
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("o envio repetido deve ser recusado", func() {
+				message := ""
+				res := call(http.MethodPost, storeId, "/order/send", "service", `{"order_id": 99}`, &message)
+
+				Expect(res.StatusCode).NotTo(Equal(http.StatusOK))
+				Expect(message).To(Equal("order already sended to production queue"))
+				Expect(queueOrderIds(storeId)).To(Equal([]uint32{99}))
+			})
+		})
+	})
 
 	Context("Usuário altera o status de um pedido de produção", func() {
 		When("o pedido está na fila", func() {
 			BeforeEach(func() {
-				req, _ := http.NewRequest(http.MethodPost, "http://localhost:8000/production/order/send", strings.NewReader(`{"order_id": 99}`))
-				req.Header.Set("Content-Type", "application/json")
-				_, err := http.DefaultClient.Do(req)
-				assert.NoError(GinkgoT(), err)
+				_, res := sendOrder(storeId, 99, "")
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
 			})
 
 			It("o status do pedido deve ser alterado para novo status", func() {
-				req, _ := http.NewRequest(http.MethodPut, "http://localhost:8000/production/order/99/status", strings.NewReader(`{"status": "EM_PREPARACAO"}`))
-				req.Header.Set("Content-Type", "application/json")
-				res, err := http.DefaultClient.Do(req)
+				order, res := changeStatus(storeId, 99, entities.IN_PREPARATION_STATUS)
 
-				assert.NoError(GinkgoT(), err)
-				assert.Equal(GinkgoT(), http.StatusOK, res.StatusCode)
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(order.Status).To(Equal(entities.IN_PREPARATION_STATUS))
 			})
+
+			It("o pedido deve passar por todos os status até ser finalizado", func() {
+				for _, status := range []string{entities.IN_PREPARATION_STATUS, entities.DONE_STATUS, entities.FINISHED_STATUS} {
+					order, res := changeStatus(storeId, 99, status)
+
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+					Expect(order.Status).To(Equal(status))
+				}
+
+				order := entities.ProductionOrder{}
+				res := call(http.MethodGet, storeId, "/order/99", "kitchen", "", &order)
+
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(order.Status).To(Equal(entities.FINISHED_STATUS))
+				Expect(order.History).To(HaveLen(4))
+
+				audit := []entities.AuditEntry{}
+				res = call(http.MethodGet, storeId, "/order/99/audit", "manager", "", &audit)
+
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(audit).To(HaveLen(3))
+				Expect(audit[2].NewStatus).To(Equal(entities.FINISHED_STATUS))
+			})
+
+			It("um status inválido deve ser recusado", func() {
+				message := ""
+				res := call(http.MethodPut, storeId, "/order/99/status", "kitchen", `{"status": "QUEIMADO"}`, &message)
+
+				Expect(res.StatusCode).NotTo(Equal(http.StatusOK))
+				Expect(message).To(ContainSubstring("Status: must be between"))
+
+				order := entities.ProductionOrder{}
+				call(http.MethodGet, storeId, "/order/99", "kitchen", "", &order)
+				Expect(order.Status).To(Equal(entities.RECEIVED_STATUS))
+			})
+		})
+
+		When("o pedido não existe", func() {
+			It("a alteração deve ser recusada", func() {
+				message := ""
+				res := call(http.MethodPut, storeId, "/order/404/status", "kitchen", `{"status": "EM_PREPARACAO"}`, &message)
+
+				Expect(res.StatusCode).NotTo(Equal(http.StatusOK))
+				Expect(message).To(Equal("Cant find production order"))
+			})
+		})
+	})
+
+	Context("Gerente consulta os pedidos da loja", func() {
+		BeforeEach(func() {
+			for orderId := uint32(1); orderId <= 5; orderId++ {
+				_, res := sendOrder(storeId, orderId, "")
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+			}
+
+			for _, orderId := range []uint32{2, 4, 5} {
+				for _, status := range []string{entities.IN_PREPARATION_STATUS, entities.DONE_STATUS, entities.FINISHED_STATUS} {
+					_, res := changeStatus(storeId, orderId, status)
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+				}
+			}
+		})
+
+		It("os pedidos finalizados devem ser listados em páginas, dos mais recentes aos mais antigos", func() {
+			orderIds := []uint32{}
+			cursor := ""
+			for pages := 0; pages < 5; pages++ {
+				page := entities.ProductionOrderPage{}
+				res := call(http.MethodGet, storeId, "/orders?status=FINALIZADO&sort=-order_id&limit=2&cursor="+cursor, "manager", "", &page)
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+				Expect(len(page.Orders)).To(BeNumerically("<=", 2))
+
+				for _, order := range page.Orders {
+					Expect(order.Status).To(Equal(entities.FINISHED_STATUS))
+					Expect(order.History).To(HaveLen(4))
+					orderIds = append(orderIds, order.OrderId)
+				}
+
+				if page.NextCursor == "" {
+					break
+				}
+
+				cursor = page.NextCursor
+			}
+
+			Expect(orderIds).To(Equal([]uint32{5, 4, 2}))
+		})
+
+		It("um filtro inválido deve ser recusado", func() {
+			message := ""
+			res := call(http.MethodGet, storeId, "/orders?status=QUEIMADO", "manager", "", &message)
+
+			Expect(res.StatusCode).NotTo(Equal(http.StatusOK))
+			Expect(message).To(ContainSubstring("must be between"))
+		})
+	})
+
+	Context("Caixa finaliza vários pedidos de uma vez", func() {
+		BeforeEach(func() {
+			for _, orderId := range []uint32{1, 2} {
+				_, res := sendOrder(storeId, orderId, "")
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+				for _, status := range []string{entities.IN_PREPARATION_STATUS, entities.DONE_STATUS} {
+					_, res = changeStatus(storeId, orderId, status)
+					Expect(res.StatusCode).To(Equal(http.StatusOK))
+				}
+			}
+		})
+
+		It("cada pedido deve ser finalizado por conta própria", func() {
+			results := []entities.StatusChangeResult{}
+			res := call(http.MethodPost, storeId, "/orders/status", "kitchen", `{"orders": [{"order_id": 1, "status": "FINALIZADO"}, {"order_id": 404, "status": "FINALIZADO"}]}`, &results)
+
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].Applied).To(BeTrue())
+			Expect(results[0].Order.Status).To(Equal(entities.FINISHED_STATUS))
+			Expect(results[1].Applied).To(BeFalse())
+			Expect(results[1].Error).To(Equal("Cant find production order"))
+			Expect(queueOrderIds(storeId)).To(Equal([]uint32{2}))
+		})
+
+		It("uma alteração atômica com falha não deve alterar nenhum pedido", func() {
+			results := []entities.StatusChangeResult{}
+			res := call(http.MethodPost, storeId, "/orders/status", "kitchen", `{"atomic": true, "orders": [{"order_id": 1, "status": "FINALIZADO"}, {"order_id": 404, "status": "FINALIZADO"}]}`, &results)
+
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].Applied).To(BeFalse())
+			Expect(queueOrderIds(storeId)).To(Equal([]uint32{1, 2}))
+
+			res = call(http.MethodPost, storeId, "/orders/status", "kitchen", `{"atomic": true, "orders": [{"order_id": 1, "status": "FINALIZADO"}, {"order_id": 2, "status": "FINALIZADO"}]}`, &results)
+
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(results[0].Applied).To(BeTrue())
+			Expect(results[1].Applied).To(BeTrue())
+			Expect(queueOrderIds(storeId)).To(BeEmpty())
+		})
+	})
+
+	Context("Cozinha consulta a fila de produção", func() {
+		BeforeEach(func() {
+			for _, order := range []struct {
+				orderId  uint32
+				priority string
+			}{
+				{1, entities.NORMAL_PRIORITY},
+				{2, entities.RUSH_PRIORITY},
+				{3, entities.NORMAL_PRIORITY},
+				{4, entities.NORMAL_PRIORITY},
+			} {
+				_, res := sendOrder(storeId, order.orderId, order.priority)
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+			}
+
+			_, res := changeStatus(storeId, 3, entities.IN_PREPARATION_STATUS)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		It("a fila deve ser ordenada por status, prioridade e chegada", func() {
+			Expect(queueOrderIds(storeId)).To(Equal([]uint32{3, 2, 1, 4}))
+		})
+
+		It("pedidos finalizados e cancelados devem sair da fila", func() {
+			for _, status := range []string{entities.IN_PREPARATION_STATUS, entities.DONE_STATUS, entities.FINISHED_STATUS} {
+				_, res := changeStatus(storeId, 1, status)
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+			}
+
+			res := call(http.MethodPost, storeId, "/order/4/cancel", "manager", "", nil)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+			Expect(queueOrderIds(storeId)).To(Equal([]uint32{3, 2}))
+		})
+	})
+
+	Context("Serviço de pedidos usa outras versões da API", func() {
+		It("a segunda versão deve recusar pedidos sem itens", func() {
+			var message string
+			res := callVersion("/v2", http.MethodPost, storeId, "/order/send", "service", `{"order_id": 1}`, &message)
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+
+			var order entities.ProductionOrder
+			res = callVersion("/v2", http.MethodPost, storeId, "/order/send", "service", `{"order_id": 1, "items": [{"name": "X-Burger", "category": "LANCHE", "quantity": 2}]}`, &order)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(order.Items).To(HaveLen(1))
+			Expect(res.Header.Get("Deprecation")).To(BeEmpty())
+		})
+
+		It("as rotas sem versão devem ser servidas como obsoletas", func() {
+			queue := []entities.ProductionOrder{}
+			res := callVersion("", http.MethodGet, storeId, "/queue", "kitchen", "", &queue)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(res.Header.Get("Deprecation")).NotTo(BeEmpty())
+			Expect(res.Header.Get("Link")).To(Equal(fmt.Sprintf(`</v1/stores/%s/production/queue>; rel="successor-version"`, storeId)))
+		})
+	})
+
+	Context("Parceiro de entrega assina as mudanças de status", func() {
+		It("o pedido pronto deve ser enviado assinado ao parceiro", func() {
+			if os.Getenv("BDD_BASE_URL") != "" {
+				Skip("the partner is only reachable from the in-process server")
+			}
+
+			const secret = "segredo-do-parceiro"
+			events := make(chan entities.StatusChangeEvent, 10)
+			partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Header.Get(external.WEBHOOK_SIGNATURE_HEADER) != external.SignWebhook(secret, r.Header.Get(external.WEBHOOK_TIMESTAMP_HEADER), body) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				event := entities.StatusChangeEvent{}
+				_ = json.Unmarshal(body, &event)
+				events <- event
+			}))
+			DeferCleanup(partner.Close)
+
+			subscription := entities.WebhookSubscription{}
+			res := call(http.MethodPost, storeId, "/webhooks", "manager", fmt.Sprintf(`{"url": %q, "events": ["order.ready"], "secret": %q}`, partner.URL, secret), &subscription)
+			Expect(res.StatusCode).To(Equal(http.StatusCreated))
+			Expect(subscription.Enabled).To(BeTrue())
+
+			_, res = sendOrder(storeId, 99, "")
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			for _, status := range []string{entities.IN_PREPARATION_STATUS, entities.DONE_STATUS} {
+				_, res = changeStatus(storeId, 99, status)
+				Expect(res.StatusCode).To(Equal(http.StatusOK))
+			}
+
+			event := entities.StatusChangeEvent{}
+			Eventually(events).Should(Receive(&event))
+			Expect(event.Event).To(Equal(entities.ORDER_READY_EVENT))
+			Expect(event.OrderId).To(Equal(uint32(99)))
+			Expect(event.PreviousStatus).To(Equal(entities.IN_PREPARATION_STATUS))
+			Consistently(events).ShouldNot(Receive())
+
+			deliveries := []entities.WebhookDelivery{}
+			res = call(http.MethodGet, storeId, "/webhooks/"+subscription.SubscriptionId+"/deliveries", "manager", "", &deliveries)
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			Expect(deliveries).To(HaveLen(1))
+			Expect(deliveries[0].Delivered).To(BeTrue())
 		})
 	})
 })
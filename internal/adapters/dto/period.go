@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+)
+
+const periodDateLayout = "2006-01-02"
+
+// PeriodDto is a time range given as RFC3339 timestamps or dates. A date used
+// as To includes the whole day. Without values the period is the 24 hours
+// before now.
+type PeriodDto struct {
+	From string `query:"from"`
+	To   string `query:"to"`
+}
+
+func (d PeriodDto) ToTimeRange(now time.Time) (from time.Time, to time.Time, err error) {
+	to = now
+	from = to.Add(-24 * time.Hour)
+
+	if d.To != "" {
+		parsedTo, isDate, err := parsePeriodTime(d.To)
+
+		if err != nil {
+			return from, to, err
+		}
+
+		if isDate {
+			parsedTo = parsedTo.Add(24 * time.Hour)
+		}
+
+		to = parsedTo
+		from = to.Add(-24 * time.Hour)
+	}
+
+	if d.From != "" {
+		parsedFrom, _, err := parsePeriodTime(d.From)
+
+		if err != nil {
+			return from, to, err
+		}
+
+		from = parsedFrom
+	}
+
+	return from, to, nil
+}
+
+func parsePeriodTime(value string) (parsed time.Time, isDate bool, err error) {
+	parsed, err = time.Parse(time.RFC3339, value)
+
+	if err == nil {
+		return parsed, false, nil
+	}
+
+	parsed, err = time.Parse(periodDateLayout, value)
+
+	if err != nil {
+		return parsed, false, fmt.Errorf("invalid date %q, expected RFC3339 or %s", value, periodDateLayout)
+	}
+
+	return parsed, true, nil
+}
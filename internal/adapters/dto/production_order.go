@@ -1,9 +1,135 @@
 package dto
 
+import (
+	"strings"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+)
+
 type SendOrderToProductionDto struct {
-	OrderId uint32 `json:"order_id" validate:"required"`
+	OrderId  uint32                   `json:"order_id" validate:"required"`
+	Priority string                   `json:"priority"`
+	Items    []ProductionOrderItemDto `json:"items" validate:"dive"`
+}
+
+// SendOrderToProductionV2Dto is the order sent to production on the second
+// version of the API, where the order carries the items to prepare.
+type SendOrderToProductionV2Dto struct {
+	OrderId  uint32                   `json:"order_id" validate:"required"`
+	Priority string                   `json:"priority"`
+	Items    []ProductionOrderItemDto `json:"items" validate:"required,min=1,dive"`
+}
+
+type ProductionOrderItemDto struct {
+	Name     string `json:"name" validate:"required"`
+	Category string `json:"category" validate:"required"`
+	Quantity uint32 `json:"quantity" validate:"required"`
 }
 
 type UpdateProductionOrderStatus struct {
 	Status string `json:"status"  validate:"required"`
 }
+
+// UpdateProductionOrderStatusesDto changes the status of several orders in one
+// request. Atomic changes are applied all together or not at all.
+type UpdateProductionOrderStatusesDto struct {
+	Orders []ProductionOrderStatusDto `json:"orders" validate:"required,min=1,max=100,dive"`
+	Atomic bool                       `json:"atomic"`
+}
+
+type ProductionOrderStatusDto struct {
+	OrderId uint32 `json:"order_id" validate:"required"`
+	Status  string `json:"status" validate:"required"`
+}
+
+// ListProductionOrdersDto holds the query of a listing. Statuses and priorities
+// may be repeated or separated by commas; from and to are RFC3339 timestamps or
+// dates, a date used as to including the whole day.
+type ListProductionOrdersDto struct {
+	Status   []string `query:"status"`
+	Priority []string `query:"priority"`
+	From     string   `query:"from"`
+	To       string   `query:"to"`
+	Sort     string   `query:"sort"`
+	Limit    int      `query:"limit"`
+	Cursor   string   `query:"cursor"`
+}
+
+type UpdateProductionOrderPriority struct {
+	Priority string `json:"priority"  validate:"required"`
+}
+
+func (d SendOrderToProductionDto) ToEntity() entities.ProductionOrder {
+	var items []entities.ProductionOrderItem
+	for _, item := range d.Items {
+		items = append(items, entities.ProductionOrderItem{
+			Name:     item.Name,
+			Category: item.Category,
+			Quantity: item.Quantity,
+		})
+	}
+
+	return entities.ProductionOrder{
+		OrderId:  d.OrderId,
+		Priority: d.Priority,
+		Items:    items,
+	}
+}
+
+func (d SendOrderToProductionV2Dto) ToEntity() entities.ProductionOrder {
+	return SendOrderToProductionDto(d).ToEntity()
+}
+
+func (d UpdateProductionOrderStatusesDto) ToEntities() []entities.StatusChange {
+	changes := []entities.StatusChange{}
+	for _, order := range d.Orders {
+		changes = append(changes, entities.StatusChange{
+			OrderId: order.OrderId,
+			Status:  order.Status,
+		})
+	}
+
+	return changes
+}
+
+func (d ListProductionOrdersDto) ToFilter() (filter entities.ProductionOrderFilter, err error) {
+	filter.Statuses = splitList(d.Status)
+	filter.Priorities = splitList(d.Priority)
+
+	if d.From != "" {
+		filter.From, _, err = parsePeriodTime(d.From)
+
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	if d.To != "" {
+		to, isDate, err := parsePeriodTime(d.To)
+
+		if err != nil {
+			return filter, err
+		}
+
+		if isDate {
+			to = to.AddDate(0, 0, 1)
+		}
+
+		filter.To = to
+	}
+
+	return filter, nil
+}
+
+func splitList(values []string) []string {
+	list := []string{}
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
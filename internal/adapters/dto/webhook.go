@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+// SubscribeWebhookDto asks for the events of the store to be posted to url,
+// signed with secret.
+type SubscribeWebhookDto struct {
+	URL    string   `json:"url" validate:"required"`
+	Events []string `json:"events" validate:"required,min=1"`
+	Secret string   `json:"secret" validate:"required"`
+}
+
+func (d SubscribeWebhookDto) ToEntity() entities.WebhookSubscription {
+	return entities.WebhookSubscription{
+		URL:    d.URL,
+		Events: d.Events,
+		Secret: d.Secret,
+	}
+}
+
+// ListWebhookDeliveriesDto holds the query of a delivery log.
+type ListWebhookDeliveriesDto struct {
+	Limit int `query:"limit"`
+}
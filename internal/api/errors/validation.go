@@ -0,0 +1,12 @@
+package custom_errors
+
+// ValidationError is returned when a request is well formed but cannot be
+// applied to the current state of what it changes, such as a status change an
+// order cannot make.
+type ValidationError struct {
+	Message string
+}
+
+func (v *ValidationError) Error() string {
+	return v.Message
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/middlewares"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	actorHeader   = "X-Actor"
+	stationHeader = "X-Station"
+)
+
+// actorFrom identifies who made the request for the audit trail: the subject
+// of the bearer token. The X-Actor header is only trusted when auth is
+// disabled, and ignored otherwise.
+func actorFrom(c echo.Context) entities.Actor {
+	name := middlewares.Subject(c)
+	if !middlewares.Authenticated(c) {
+		name = c.Request().Header.Get(actorHeader)
+	}
+
+	requestId := c.Response().Header().Get(echo.HeaderXRequestID)
+	if requestId == "" {
+		requestId = c.Request().Header.Get(echo.HeaderXRequestID)
+	}
+
+	return entities.Actor{
+		Name:      name,
+		Station:   c.Request().Header.Get(stationHeader),
+		RequestId: requestId,
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+)
+
+// errorStatus is the status code answering an error of a use case. Requests
+// the use case rejects answer 400, changes the current state does not allow
+// answer 409, and the errors the use cases do not tell apart answer 500.
+func errorStatus(err error) int {
+	var badRequest *custom_errors.BadRequestError
+	if errors.As(err, &badRequest) {
+		return http.StatusBadRequest
+	}
+
+	var validationError *custom_errors.ValidationError
+	if errors.As(err, &validationError) {
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type ProductionAuditHandler struct {
+	productionAuditUseCases usecase.ProductionAuditUseCases
+}
+
+func NewProductionAuditHandler(usecase usecase.ProductionAuditUseCases) ProductionAuditHandler {
+
+	return ProductionAuditHandler{
+		productionAuditUseCases: usecase,
+	}
+}
+
+// GetProductionOrderAudit lists who changed the status of the order and when,
+// oldest change first.
+//
+// @Tags production-orders
+// @Produce json
+// @Param orderId path int true "Order id"
+// @Success 200 {array} entities.AuditEntry
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/{orderId}/audit [get]
+func (h *ProductionAuditHandler) GetProductionOrderAudit(echo echo.Context) error {
+	orderId, err := strconv.Atoi(echo.Param("orderId"))
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	entries, err := h.productionAuditUseCases.GetProductionOrderAudit(storeIdFrom(echo), uint32(orderId))
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_usecase "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/utils"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProductionAuditHandler_GetProductionOrderAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionAuditUseCases(ctrl)
+
+	entries := []entities.AuditEntry{
+		entities.NewAuditEntry(
+			entities.ProductionOrder{StoreId: currentStore, OrderId: 1},
+			entities.STATUS_CHANGED_AUDIT_ACTION,
+			entities.RECEIVED_STATUS,
+			entities.IN_PREPARATION_STATUS,
+			entities.Actor{Name: "cozinheiro"},
+			time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+		),
+	}
+
+	testCases := []struct {
+		utils.TestCase
+		OrderId string
+	}{
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return the audit trail of the order",
+				SetupMocks: func() interface{} {
+					useCase.EXPECT().GetProductionOrderAudit(currentStore, uint32(1)).Return(entries, nil).Times(1)
+					res, err := json.Marshal(entries)
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusOK,
+						"body": string(res),
+					}
+				},
+			},
+			OrderId: "1",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 400 when the order id is invalid",
+				SetupMocks: func() interface{} {
+					res, err := json.Marshal(`strconv.Atoi: parsing "abc": invalid syntax`)
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusBadRequest,
+						"body": string(res),
+					}
+				},
+			},
+			OrderId: "abc",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 500 when cant read the audit trail",
+				SetupMocks: func() interface{} {
+					mockErr := errors.New("mock error")
+					useCase.EXPECT().GetProductionOrderAudit(currentStore, uint32(1)).Return(nil, mockErr).Times(1)
+					res, err := json.Marshal(mockErr.Error())
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusInternalServerError,
+						"body": string(res),
+					}
+				},
+			},
+			OrderId: "1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodGet, "/production/order/"+tt.OrderId+"/audit", nil)
+			ctx.SetParamNames("orderId")
+			ctx.SetParamValues(tt.OrderId)
+			handler := NewProductionAuditHandler(useCase)
+			err := handler.GetProductionOrderAudit(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestActorFrom(t *testing.T) {
+	ctx, req, _ := echoContext(http.MethodPut, "/production/order/1/status", nil)
+	req.Header.Set("X-Actor", "garcom")
+	req.Header.Set("X-Station", "balcao")
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+
+	assert.Equal(t, entities.Actor{Name: "garcom", Station: "balcao", RequestId: "req-1"}, actorFrom(ctx))
+
+	ctx.Set("subject", "cozinheiro")
+
+	assert.Equal(t, "cozinheiro", actorFrom(ctx).Name)
+
+	ctx.Set("subject", "")
+
+	assert.Empty(t, actorFrom(ctx).Name)
+}
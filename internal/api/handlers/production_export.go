@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/presenters"
+	"github.com/labstack/echo/v4"
+)
+
+type ProductionExportHandler struct {
+	productionExportUseCases usecase.ProductionExportUseCases
+}
+
+func NewProductionExportHandler(usecase usecase.ProductionExportUseCases) ProductionExportHandler {
+
+	return ProductionExportHandler{
+		productionExportUseCases: usecase,
+	}
+}
+
+// ExportProductionOrders streams the orders received in the period given by
+// the from and to query parameters as csv (default) or ndjson. Errors found
+// before the first order is written are answered as JSON; after that the
+// response is already committed and the stream is cut short.
+//
+// @Tags reports
+// @Produce text/csv,application/x-ndjson
+// @Param from query string false "From, RFC3339 or date"
+// @Param to query string false "To, RFC3339 or date including the whole day"
+// @Param format query string false "csv or ndjson" Enums(csv, ndjson) default(csv)
+// @Success 200 {file} file
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/export [get]
+func (h *ProductionExportHandler) ExportProductionOrders(echo echo.Context) error {
+	from, to, err := parsePeriod(echo)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	format := echo.QueryParam("format")
+	if format == "" {
+		format = presenters.CSV_EXPORT_FORMAT
+	}
+
+	response := echo.Response()
+	exporter, err := presenters.NewProductionOrderExporter(format, response)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	startStream := func() {
+		if response.Committed {
+			return
+		}
+
+		response.Header().Set("Content-Type", exporter.ContentType())
+		response.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=production-orders.%s", format))
+		response.WriteHeader(http.StatusOK)
+	}
+
+	err = h.productionExportUseCases.ExportProductionOrders(storeIdFrom(echo), from, to, func(order entities.ProductionOrder) error {
+		startStream()
+
+		if err := exporter.Write(order); err != nil {
+			return err
+		}
+
+		response.Flush()
+		return nil
+	})
+
+	if err != nil && !response.Committed {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	if err != nil {
+		log.Println(err.Error())
+		return nil
+	}
+
+	startStream()
+	err = exporter.Flush()
+
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	return nil
+}
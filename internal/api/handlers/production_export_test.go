@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_usecase "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProductionExportHandler_ExportProductionOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionExportUseCases(ctrl)
+
+	from := time.Date(2024, time.October, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.October, 11, 0, 0, 0, 0, time.UTC)
+	order := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		History: []entities.StatusTransition{
+			{Status: entities.RECEIVED_STATUS, At: from},
+		},
+	}
+
+	exportOrder := func(storeId string, from, to time.Time, write func(entities.ProductionOrder) error) error {
+		return write(order)
+	}
+
+	testCases := []struct {
+		utils.TestCase
+		Query string
+	}{
+		{
+			TestCase: utils.TestCase{
+				Name: "Should export orders as csv by default",
+				SetupMocks: func() interface{} {
+					useCase.EXPECT().ExportProductionOrders(currentStore, from, to, gomock.Any()).DoAndReturn(exportOrder).Times(1)
+					return map[string]interface{}{
+						"code":        http.StatusOK,
+						"contentType": "text/csv",
+						"body": "store_id,order_id,priority,created_at,current_status,transition_status,transition_at\n" +
+							"loja-1,1,,,RECEBIDO,RECEBIDO,2024-10-10T00:00:00Z\n",
+					}
+				},
+			},
+			Query: "from=2024-10-10&to=2024-10-10",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should export orders as ndjson",
+				SetupMocks: func() interface{} {
+					useCase.EXPECT().ExportProductionOrders(currentStore, from, to, gomock.Any()).DoAndReturn(exportOrder).Times(1)
+					res, err := json.Marshal(order)
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code":        http.StatusOK,
+						"contentType": "application/x-ndjson",
+						"body":        string(res) + "\n",
+					}
+				},
+			},
+			Query: "from=2024-10-10&to=2024-10-10&format=ndjson",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 400 when the format is invalid",
+				SetupMocks: func() interface{} {
+					res, err := json.Marshal("format must be between csv or ndjson")
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code":        http.StatusBadRequest,
+						"contentType": "application/json",
+						"body":        string(res) + "\n",
+					}
+				},
+			},
+			Query: "from=2024-10-10&to=2024-10-10&format=xml",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 500 when the export fails before streaming",
+				SetupMocks: func() interface{} {
+					mockErr := errors.New("mock error")
+					useCase.EXPECT().ExportProductionOrders(currentStore, from, to, gomock.Any()).Return(mockErr).Times(1)
+					res, err := json.Marshal(mockErr.Error())
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code":        http.StatusInternalServerError,
+						"contentType": "application/json",
+						"body":        string(res) + "\n",
+					}
+				},
+			},
+			Query: "from=2024-10-10&to=2024-10-10",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodGet, "/production/export?"+tt.Query, nil)
+			handler := NewProductionExportHandler(useCase)
+			err := handler.ExportProductionOrders(ctx)
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code":        res.Code,
+				"contentType": res.Header().Get("Content-Type"),
+				"body":        res.Body.String(),
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
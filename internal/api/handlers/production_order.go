@@ -9,6 +9,9 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// defaultListLimit is the page size of a listing without a limit.
+const defaultListLimit = 50
+
 type ProductionOrderHandler struct {
 	productionOrderUseCases usecase.ProductionOrderUseCases
 }
@@ -20,6 +23,17 @@ func NewProductionOrderHandler(usecase usecase.ProductionOrderUseCases) Producti
 	}
 }
 
+// @Summary Send an order to production
+// @Description Puts an order in the production queue as RECEBIDO. Items are optional in this version.
+// @Tags production-orders,v1
+// @Accept json
+// @Produce json
+// @Param order body dto.SendOrderToProductionDto true "Order"
+// @Success 200 {object} entities.ProductionOrder
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/send [post]
 func (h *ProductionOrderHandler) SendOrderToProduction(echo echo.Context) error {
 	sendOrderToProductionDto := dto.SendOrderToProductionDto{}
 
@@ -35,15 +49,109 @@ func (h *ProductionOrderHandler) SendOrderToProduction(echo echo.Context) error
 		return echo.JSON(http.StatusBadRequest, err.Error())
 	}
 
-	orderSend, err := h.productionOrderUseCases.SendOrderToProduction(sendOrderToProductionDto.OrderId)
+	orderSend, err := h.productionOrderUseCases.SendOrderToProduction(storeIdFrom(echo), sendOrderToProductionDto.ToEntity())
 
 	if err != nil {
-		return echo.JSON(http.StatusInternalServerError, err.Error())
+		return echo.JSON(errorStatus(err), err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, orderSend)
+}
+
+// SendOrderToProductionV2 sends an order to production like
+// SendOrderToProduction, but the order must carry the items to prepare.
+//
+// @Summary Send an order to production
+// @Description Puts an order in the production queue as RECEBIDO. The order must carry its items.
+// @Tags production-orders,v2
+// @Accept json
+// @Produce json
+// @Param order body dto.SendOrderToProductionV2Dto true "Order"
+// @Success 200 {object} entities.ProductionOrder
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/send [post]
+func (h *ProductionOrderHandler) SendOrderToProductionV2(echo echo.Context) error {
+	sendOrderToProductionDto := dto.SendOrderToProductionV2Dto{}
+
+	err := echo.Bind(&sendOrderToProductionDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	err = echo.Validate(sendOrderToProductionDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	orderSend, err := h.productionOrderUseCases.SendOrderToProduction(storeIdFrom(echo), sendOrderToProductionDto.ToEntity())
+
+	if err != nil {
+		return echo.JSON(errorStatus(err), err.Error())
 	}
 
 	return echo.JSON(http.StatusOK, orderSend)
 }
 
+// ListProductionOrders pages through the orders of the store, closed ones
+// included, filtered and sorted by the query parameters.
+//
+// @Summary List the orders of the store
+// @Tags production-orders
+// @Produce json
+// @Param status query []string false "Statuses, repeated or separated by commas" collectionFormat(multi)
+// @Param priority query []string false "Priorities, repeated or separated by commas" collectionFormat(multi)
+// @Param from query string false "Received from, RFC3339 or date"
+// @Param to query string false "Received until, RFC3339 or date including the whole day"
+// @Param sort query string false "order_id or -order_id" Enums(order_id, -order_id)
+// @Param limit query int false "Page size, from 1 to 100" default(50)
+// @Param cursor query string false "NextCursor of the previous page"
+// @Success 200 {object} entities.ProductionOrderPage
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/orders [get]
+func (h *ProductionOrderHandler) ListProductionOrders(echo echo.Context) error {
+	listProductionOrdersDto := dto.ListProductionOrdersDto{
+		Limit: defaultListLimit,
+	}
+
+	err := echo.Bind(&listProductionOrdersDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	filter, err := listProductionOrdersDto.ToFilter()
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	page, err := h.productionOrderUseCases.ListProductionOrders(storeIdFrom(echo), filter, listProductionOrdersDto.Sort, listProductionOrdersDto.Limit, listProductionOrdersDto.Cursor)
+
+	if err != nil {
+		return echo.JSON(errorStatus(err), err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, page)
+}
+
+// @Summary Change the status of an order
+// @Tags production-orders
+// @Accept json
+// @Produce json
+// @Param orderId path int true "Order id"
+// @Param status body dto.UpdateProductionOrderStatus true "New status"
+// @Success 200 {object} entities.ProductionOrder
+// @Failure 400 {string} string
+// @Failure 409 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/{orderId}/status [put]
 func (h *ProductionOrderHandler) UpdateProductionOrderStatus(echo echo.Context) error {
 	updateProductionOrderStatusDto := dto.UpdateProductionOrderStatus{}
 	orderId, err := strconv.Atoi(echo.Param("orderId"))
@@ -64,17 +172,172 @@ func (h *ProductionOrderHandler) UpdateProductionOrderStatus(echo echo.Context)
 		return echo.JSON(http.StatusBadRequest, err.Error())
 	}
 
-	productionOrderUpdated, err := h.productionOrderUseCases.UpdateProductionOrderStatus(uint32(orderId), updateProductionOrderStatusDto.Status)
+	productionOrderUpdated, err := h.productionOrderUseCases.UpdateProductionOrderStatus(storeIdFrom(echo), uint32(orderId), updateProductionOrderStatusDto.Status, actorFrom(echo))
+
+	if err != nil {
+		return echo.JSON(errorStatus(err), err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, productionOrderUpdated)
+}
+
+// UpdateProductionOrderStatuses answers with the result of every change. An
+// atomic update that was not applied answers 400 with the results telling which
+// changes failed.
+//
+// @Summary Change the status of several orders
+// @Tags production-orders
+// @Accept json
+// @Produce json
+// @Param changes body dto.UpdateProductionOrderStatusesDto true "Status changes"
+// @Success 200 {array} entities.StatusChangeResult
+// @Failure 400 {array} entities.StatusChangeResult
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/orders/status [post]
+func (h *ProductionOrderHandler) UpdateProductionOrderStatuses(echo echo.Context) error {
+	updateProductionOrderStatusesDto := dto.UpdateProductionOrderStatusesDto{}
+
+	err := echo.Bind(&updateProductionOrderStatusesDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	err = echo.Validate(updateProductionOrderStatusesDto)
 
 	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	results, err := h.productionOrderUseCases.UpdateProductionOrderStatuses(storeIdFrom(echo), updateProductionOrderStatusesDto.ToEntities(), updateProductionOrderStatusesDto.Atomic, actorFrom(echo))
+
+	if err != nil && results == nil {
 		return echo.JSON(http.StatusInternalServerError, err.Error())
 	}
 
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, results)
+	}
+
+	return echo.JSON(http.StatusOK, results)
+}
+
+// @Summary Cancel an order
+// @Tags production-orders
+// @Produce json
+// @Param orderId path int true "Order id"
+// @Success 200 {object} entities.ProductionOrder
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/{orderId}/cancel [post]
+func (h *ProductionOrderHandler) CancelProductionOrder(echo echo.Context) error {
+	orderId, err := strconv.Atoi(echo.Param("orderId"))
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	productionOrderCanceled, err := h.productionOrderUseCases.CancelProductionOrder(storeIdFrom(echo), uint32(orderId), actorFrom(echo))
+
+	if err != nil {
+		return echo.JSON(errorStatus(err), err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, productionOrderCanceled)
+}
+
+// @Summary Change the priority of an order
+// @Tags production-orders
+// @Accept json
+// @Produce json
+// @Param orderId path int true "Order id"
+// @Param priority body dto.UpdateProductionOrderPriority true "New priority"
+// @Success 200 {object} entities.ProductionOrder
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/{orderId}/priority [put]
+func (h *ProductionOrderHandler) UpdateProductionOrderPriority(echo echo.Context) error {
+	updateProductionOrderPriorityDto := dto.UpdateProductionOrderPriority{}
+	orderId, err := strconv.Atoi(echo.Param("orderId"))
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	err = echo.Bind(&updateProductionOrderPriorityDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	err = echo.Validate(updateProductionOrderPriorityDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	productionOrderUpdated, err := h.productionOrderUseCases.UpdateProductionOrderPriority(storeIdFrom(echo), uint32(orderId), updateProductionOrderPriorityDto.Priority)
+
+	if err != nil {
+		return echo.JSON(errorStatus(err), err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, productionOrderUpdated)
+}
+
+// @Summary Change the status of the ticket of a station
+// @Tags stations
+// @Accept json
+// @Produce json
+// @Param orderId path int true "Order id"
+// @Param station path string true "Station"
+// @Param status body dto.UpdateProductionOrderStatus true "New status"
+// @Success 200 {object} entities.ProductionOrder
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/{orderId}/stations/{station}/status [put]
+func (h *ProductionOrderHandler) UpdateStationTicketStatus(echo echo.Context) error {
+	updateStationTicketStatusDto := dto.UpdateProductionOrderStatus{}
+	orderId, err := strconv.Atoi(echo.Param("orderId"))
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	err = echo.Bind(&updateStationTicketStatusDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	err = echo.Validate(updateStationTicketStatusDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	productionOrderUpdated, err := h.productionOrderUseCases.UpdateStationTicketStatus(storeIdFrom(echo), uint32(orderId), echo.Param("station"), updateStationTicketStatusDto.Status, actorFrom(echo))
+
+	if err != nil {
+		return echo.JSON(errorStatus(err), err.Error())
+	}
+
 	return echo.JSON(http.StatusOK, productionOrderUpdated)
 }
 
+// @Summary Get the production queue
+// @Tags production-orders
+// @Produce json
+// @Success 200 {array} entities.ProductionOrder
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/queue [get]
 func (h *ProductionOrderHandler) GetProductionOrderQueue(echo echo.Context) error {
-	productionOrderQueue, err := h.productionOrderUseCases.GetProductionOrderQueue()
+	productionOrderQueue, err := h.productionOrderUseCases.GetProductionOrderQueue(storeIdFrom(echo))
 
 	if err != nil {
 		return echo.JSON(http.StatusInternalServerError, err.Error())
@@ -82,3 +345,46 @@ func (h *ProductionOrderHandler) GetProductionOrderQueue(echo echo.Context) erro
 
 	return echo.JSON(http.StatusOK, productionOrderQueue.Orders)
 }
+
+// @Summary Get the queue of a station
+// @Tags stations
+// @Produce json
+// @Param station path string true "Station"
+// @Success 200 {array} entities.ProductionOrder
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/stations/{station}/queue [get]
+func (h *ProductionOrderHandler) GetStationQueue(echo echo.Context) error {
+	stationQueue, err := h.productionOrderUseCases.GetStationQueue(storeIdFrom(echo), echo.Param("station"))
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, stationQueue.Orders)
+}
+
+// @Summary Get an order
+// @Tags production-orders
+// @Produce json
+// @Param orderId path int true "Order id"
+// @Success 200 {object} entities.ProductionOrder
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/order/{orderId} [get]
+func (h *ProductionOrderHandler) GetProductionOrder(echo echo.Context) error {
+	orderId, err := strconv.Atoi(echo.Param("orderId"))
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	productionOrder, err := h.productionOrderUseCases.GetProductionOrder(storeIdFrom(echo), uint32(orderId))
+
+	if err != nil {
+		return echo.JSON(errorStatus(err), err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, productionOrder)
+}
@@ -8,18 +8,24 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/8soat-grupo35/fastfood-order-production/external"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/adapters/dto"
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
 	mock_usecase "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase/mock"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/utils"
 	"github.com/go-playground/validator"
-	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
+const currentStore = "loja-1"
+
+// echoContext builds a request context already scoped to currentStore, as the
+// StoreScope middleware does for the routes.
 var echoContext = func(method string, targetPath string, body io.Reader) (echo.Context, *http.Request, *httptest.ResponseRecorder) {
 	e := echo.New()
 	e.Validator = &external.HandlerCustomValidator{
@@ -31,7 +37,10 @@ var echoContext = func(method string, targetPath string, body io.Reader) (echo.C
 	}
 	rec := httptest.NewRecorder()
 
-	return e.NewContext(req, rec), req, rec
+	ctx := e.NewContext(req, rec)
+	ctx.Set(storeIdContextKey, currentStore)
+
+	return ctx, req, rec
 }
 
 func TestProductionOrderHandler_GetProductionOrderQueue(t *testing.T) {
@@ -53,7 +62,7 @@ func TestProductionOrderHandler_GetProductionOrderQueue(t *testing.T) {
 		{
 			Name: "Should return production order queue successfully",
 			SetupMocks: func() interface{} {
-				useCase.EXPECT().GetProductionOrderQueue().Return(&queue, nil).Times(1)
+				useCase.EXPECT().GetProductionOrderQueue(currentStore).Return(&queue, nil).Times(1)
 				res, err := json.Marshal(queue.Orders)
 				assert.NoError(t, err)
 				return map[string]interface{}{
@@ -67,7 +76,7 @@ func TestProductionOrderHandler_GetProductionOrderQueue(t *testing.T) {
 			Name: "Should return 500 when cant find order queue successfully",
 			SetupMocks: func() interface{} {
 				mockErr := errors.New("mock error")
-				useCase.EXPECT().GetProductionOrderQueue().Return(nil, mockErr).Times(1)
+				useCase.EXPECT().GetProductionOrderQueue(currentStore).Return(nil, mockErr).Times(1)
 				res, err := json.Marshal(mockErr.Error())
 				assert.NoError(t, err)
 				return map[string]interface{}{
@@ -126,7 +135,7 @@ func TestProductionOrderHandler_SendOrderToProduction(t *testing.T) {
 		{
 			Name: "Should send order to production queue successfully",
 			SetupMocks: func() interface{} {
-				useCase.EXPECT().SendOrderToProduction(sendOrderDto.OrderId).Return(&sendOrderEntity, nil).Times(1)
+				useCase.EXPECT().SendOrderToProduction(currentStore, sendOrderDto.ToEntity()).Return(&sendOrderEntity, nil).Times(1)
 				res, err := json.Marshal(sendOrderEntity)
 				assert.NoError(t, err)
 				return map[string]interface{}{
@@ -136,11 +145,25 @@ func TestProductionOrderHandler_SendOrderToProduction(t *testing.T) {
 			},
 			WantErr: false,
 		},
+		{
+			Name: "Should return 400 when the order was already sent",
+			SetupMocks: func() interface{} {
+				mockErr := &custom_errors.BadRequestError{Message: "order already sended to production queue"}
+				useCase.EXPECT().SendOrderToProduction(currentStore, sendOrderDto.ToEntity()).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusBadRequest,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
 		{
 			Name: "Should return 500 when cant send order to production queue successfully",
 			SetupMocks: func() interface{} {
 				mockErr := errors.New("mock error")
-				useCase.EXPECT().SendOrderToProduction(sendOrderDto.OrderId).Return(nil, mockErr).Times(1)
+				useCase.EXPECT().SendOrderToProduction(currentStore, sendOrderDto.ToEntity()).Return(nil, mockErr).Times(1)
 				res, err := json.Marshal(mockErr.Error())
 				assert.NoError(t, err)
 				return map[string]interface{}{
@@ -176,6 +199,99 @@ func TestProductionOrderHandler_SendOrderToProduction(t *testing.T) {
 	}
 }
 
+func TestProductionOrderHandler_SendOrderToProductionV2(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	sendOrderDto := dto.SendOrderToProductionV2Dto{
+		OrderId: 1,
+		Items: []dto.ProductionOrderItemDto{
+			{Name: "X-Burger", Category: "LANCHE", Quantity: 2},
+		},
+	}
+
+	sendOrderEntity := entities.ProductionOrder{
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		Items: []entities.ProductionOrderItem{
+			{Name: "X-Burger", Category: "LANCHE", Quantity: 2},
+		},
+	}
+
+	sendOrderDtoStr, err := json.Marshal(sendOrderDto)
+	assert.NoError(t, err)
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should send order with items to production queue successfully",
+			SetupMocks: func() interface{} {
+				useCase.EXPECT().SendOrderToProduction(currentStore, sendOrderDto.ToEntity()).Return(&sendOrderEntity, nil).Times(1)
+				res, err := json.Marshal(sendOrderEntity)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when cant send order to production queue successfully",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().SendOrderToProduction(currentStore, sendOrderDto.ToEntity()).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodPost, "/v2/production/order/send", strings.NewReader(string(sendOrderDtoStr)))
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.SendOrderToProductionV2(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			if tt.WantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderHandler_SendOrderToProductionV2_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	ctx, _, res := echoContext(http.MethodPost, "/v2/production/order/send", strings.NewReader(`{"order_id": 1}`))
+
+	handler := NewProductionOrderHandler(useCase)
+	err := handler.SendOrderToProductionV2(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, res.Code, http.StatusBadRequest)
+}
+
 func TestProductionOrderHandler_UpdateProductionOrderStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -198,7 +314,7 @@ func TestProductionOrderHandler_UpdateProductionOrderStatus(t *testing.T) {
 		{
 			Name: "Should update order on production queue successfully",
 			SetupMocks: func() interface{} {
-				useCase.EXPECT().UpdateProductionOrderStatus(updateOrderEntity.OrderId, updateOrderDto.Status).Return(&updateOrderEntity, nil).Times(1)
+				useCase.EXPECT().UpdateProductionOrderStatus(currentStore, updateOrderEntity.OrderId, updateOrderDto.Status, entities.Actor{}).Return(&updateOrderEntity, nil).Times(1)
 				res, err := json.Marshal(updateOrderEntity)
 				assert.NoError(t, err)
 				return map[string]interface{}{
@@ -212,7 +328,7 @@ func TestProductionOrderHandler_UpdateProductionOrderStatus(t *testing.T) {
 			Name: "Should return 500 when cant send order to production queue successfully",
 			SetupMocks: func() interface{} {
 				mockErr := errors.New("mock error")
-				useCase.EXPECT().UpdateProductionOrderStatus(updateOrderEntity.OrderId, updateOrderDto.Status).Return(nil, mockErr).Times(1)
+				useCase.EXPECT().UpdateProductionOrderStatus(currentStore, updateOrderEntity.OrderId, updateOrderDto.Status, entities.Actor{}).Return(nil, mockErr).Times(1)
 				res, err := json.Marshal(mockErr.Error())
 				assert.NoError(t, err)
 				return map[string]interface{}{
@@ -222,6 +338,20 @@ func TestProductionOrderHandler_UpdateProductionOrderStatus(t *testing.T) {
 			},
 			WantErr: false,
 		},
+		{
+			Name: "Should return 409 when the order cant move to the status",
+			SetupMocks: func() interface{} {
+				mockErr := &custom_errors.ValidationError{Message: "cant change status of a FINALIZADO order to PRONTO"}
+				useCase.EXPECT().UpdateProductionOrderStatus(currentStore, updateOrderEntity.OrderId, updateOrderDto.Status, entities.Actor{}).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusConflict,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
 	}
 
 	for _, tt := range testCases {
@@ -265,3 +395,643 @@ func TestProductionOrderHandler_UpdateProductionOrderStatus_BadRequest(t *testin
 	assert.NoError(t, err)
 	assert.Equal(t, res.Code, http.StatusBadRequest)
 }
+
+func TestProductionOrderHandler_UpdateProductionOrderStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	body := `{"atomic": true, "orders": [{"order_id": 1, "status": "FINALIZADO"}, {"order_id": 2, "status": "FINALIZADO"}]}`
+	changes := []entities.StatusChange{
+		{OrderId: 1, Status: entities.FINISHED_STATUS},
+		{OrderId: 2, Status: entities.FINISHED_STATUS},
+	}
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should answer the result of every change",
+			SetupMocks: func() interface{} {
+				results := []entities.StatusChangeResult{
+					{OrderId: 1, Status: entities.FINISHED_STATUS, Applied: true, Order: &entities.ProductionOrder{OrderId: 1, Status: entities.FINISHED_STATUS}},
+					{OrderId: 2, Status: entities.FINISHED_STATUS, Applied: true, Order: &entities.ProductionOrder{OrderId: 2, Status: entities.FINISHED_STATUS}},
+				}
+				useCase.EXPECT().UpdateProductionOrderStatuses(currentStore, changes, true, entities.Actor{}).Return(results, nil).Times(1)
+				res, err := json.Marshal(results)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 400 with the results when the atomic update was not applied",
+			SetupMocks: func() interface{} {
+				results := []entities.StatusChangeResult{
+					{OrderId: 1, Status: entities.FINISHED_STATUS, Error: "not applied, another change failed"},
+					{OrderId: 2, Status: entities.FINISHED_STATUS, Error: "Cant find production order"},
+				}
+				useCase.EXPECT().UpdateProductionOrderStatuses(currentStore, changes, true, entities.Actor{}).Return(results, errors.New("no status was changed, some of the changes failed")).Times(1)
+				res, err := json.Marshal(results)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusBadRequest,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when the changes cant be stored",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().UpdateProductionOrderStatuses(currentStore, changes, true, entities.Actor{}).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodPost, "/production/orders/status", strings.NewReader(body))
+
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.UpdateProductionOrderStatuses(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderHandler_UpdateProductionOrderStatuses_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	for _, body := range []string{`{"orders": []}`, `{"orders": [{"order_id": 1}]}`} {
+		ctx, _, res := echoContext(http.MethodPost, "/production/orders/status", strings.NewReader(body))
+
+		handler := NewProductionOrderHandler(useCase)
+		err := handler.UpdateProductionOrderStatuses(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	}
+}
+
+func TestProductionOrderHandler_ListProductionOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	filter := entities.ProductionOrderFilter{
+		Statuses:   []string{entities.FINISHED_STATUS, entities.CANCELED_STATUS},
+		Priorities: []string{entities.RUSH_PRIORITY},
+		From:       time.Date(2024, time.October, 9, 0, 0, 0, 0, time.UTC),
+		To:         time.Date(2024, time.October, 10, 0, 0, 0, 0, time.UTC),
+	}
+	page := entities.ProductionOrderPage{
+		Orders:     []entities.ProductionOrder{{OrderId: 1, Status: entities.FINISHED_STATUS}},
+		NextCursor: "next",
+	}
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should list the orders matching the query",
+			SetupMocks: func() interface{} {
+				useCase.EXPECT().ListProductionOrders(currentStore, filter, entities.ORDER_ID_DESCENDING_SORT, 20, "cursor").Return(&page, nil).Times(1)
+				res, err := json.Marshal(page)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 400 when the use case rejects the query",
+			SetupMocks: func() interface{} {
+				mockErr := &custom_errors.BadRequestError{Message: "invalid cursor"}
+				useCase.EXPECT().ListProductionOrders(currentStore, filter, entities.ORDER_ID_DESCENDING_SORT, 20, "cursor").Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusBadRequest,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when the orders cant be listed",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().ListProductionOrders(currentStore, filter, entities.ORDER_ID_DESCENDING_SORT, 20, "cursor").Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodGet, "/production/orders?status=FINALIZADO,CANCELADO&priority=URGENTE&from=2024-10-09&to=2024-10-09&sort=-order_id&limit=20&cursor=cursor", nil)
+
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.ListProductionOrders(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderHandler_ListProductionOrders_Defaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+	useCase.EXPECT().ListProductionOrders(currentStore, entities.ProductionOrderFilter{
+		Statuses:   []string{entities.FINISHED_STATUS, entities.CANCELED_STATUS},
+		Priorities: []string{},
+	}, "", defaultListLimit, "").Return(&entities.ProductionOrderPage{Orders: []entities.ProductionOrder{}}, nil).Times(1)
+
+	ctx, _, res := echoContext(http.MethodGet, "/production/orders?status=FINALIZADO&status=CANCELADO", nil)
+
+	handler := NewProductionOrderHandler(useCase)
+	err := handler.ListProductionOrders(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestProductionOrderHandler_ListProductionOrders_BadRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	for _, query := range []string{"from=ontem", "to=2024-13-01", "limit=muitos"} {
+		ctx, _, res := echoContext(http.MethodGet, "/production/orders?"+query, nil)
+
+		handler := NewProductionOrderHandler(useCase)
+		err := handler.ListProductionOrders(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.Code, query)
+	}
+}
+
+func TestProductionOrderHandler_GetStationQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	queue := entities.ProductionOrderQueue{
+		Orders: []entities.ProductionOrder{
+			{
+				OrderId: 1,
+				Status:  entities.RECEIVED_STATUS,
+				Tickets: []entities.StationTicket{
+					{Station: "grill", Status: entities.RECEIVED_STATUS},
+				},
+			},
+		},
+	}
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should return station queue successfully",
+			SetupMocks: func() interface{} {
+				useCase.EXPECT().GetStationQueue(currentStore, "grill").Return(&queue, nil).Times(1)
+				res, err := json.Marshal(queue.Orders)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when cant find station queue",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().GetStationQueue(currentStore, "grill").Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodGet, "/production/stations/grill/queue", nil)
+			ctx.SetParamNames("station")
+			ctx.SetParamValues("grill")
+
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.GetStationQueue(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			if tt.WantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderHandler_UpdateStationTicketStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	updateTicketDto := dto.UpdateProductionOrderStatus{
+		Status: entities.DONE_STATUS,
+	}
+
+	updatedOrderEntity := entities.ProductionOrder{
+		OrderId: 1,
+		Status:  entities.DONE_STATUS,
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: entities.DONE_STATUS},
+		},
+	}
+
+	updateTicketDtoStr, err := json.Marshal(updateTicketDto)
+	assert.NoError(t, err)
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should update station ticket successfully",
+			SetupMocks: func() interface{} {
+				useCase.EXPECT().UpdateStationTicketStatus(currentStore, uint32(1), "grill", updateTicketDto.Status, entities.Actor{}).Return(&updatedOrderEntity, nil).Times(1)
+				res, err := json.Marshal(updatedOrderEntity)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 400 when the order has no ticket for the station",
+			SetupMocks: func() interface{} {
+				mockErr := &custom_errors.BadRequestError{Message: "order has no ticket for station grill"}
+				useCase.EXPECT().UpdateStationTicketStatus(currentStore, uint32(1), "grill", updateTicketDto.Status, entities.Actor{}).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusBadRequest,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when cant update station ticket",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().UpdateStationTicketStatus(currentStore, uint32(1), "grill", updateTicketDto.Status, entities.Actor{}).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodPut, "/production/order/1/stations/grill/status", strings.NewReader(string(updateTicketDtoStr)))
+			ctx.SetParamNames("orderId", "station")
+			ctx.SetParamValues("1", "grill")
+
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.UpdateStationTicketStatus(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			if tt.WantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderHandler_UpdateProductionOrderPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	updatePriorityDto := dto.UpdateProductionOrderPriority{
+		Priority: entities.RUSH_PRIORITY,
+	}
+
+	updatedOrderEntity := entities.ProductionOrder{
+		OrderId:  1,
+		Status:   entities.RECEIVED_STATUS,
+		Priority: entities.RUSH_PRIORITY,
+	}
+
+	updatePriorityDtoStr, err := json.Marshal(updatePriorityDto)
+	assert.NoError(t, err)
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should update order priority successfully",
+			SetupMocks: func() interface{} {
+				useCase.EXPECT().UpdateProductionOrderPriority(currentStore, uint32(1), updatePriorityDto.Priority).Return(&updatedOrderEntity, nil).Times(1)
+				res, err := json.Marshal(updatedOrderEntity)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 400 when the order is closed",
+			SetupMocks: func() interface{} {
+				mockErr := &custom_errors.BadRequestError{Message: "cant change priority of a closed order"}
+				useCase.EXPECT().UpdateProductionOrderPriority(currentStore, uint32(1), updatePriorityDto.Priority).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusBadRequest,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when cant update order priority",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().UpdateProductionOrderPriority(currentStore, uint32(1), updatePriorityDto.Priority).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodPut, "/production/order/1/priority", strings.NewReader(string(updatePriorityDtoStr)))
+			ctx.SetParamNames("orderId")
+			ctx.SetParamValues("1")
+
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.UpdateProductionOrderPriority(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			if tt.WantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderHandler_GetProductionOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	readyAt := time.Date(2024, time.October, 10, 12, 10, 0, 0, time.UTC)
+	orderEntity := entities.ProductionOrder{
+		OrderId:          1,
+		Status:           entities.RECEIVED_STATUS,
+		EstimatedReadyAt: &readyAt,
+	}
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should return production order successfully",
+			SetupMocks: func() interface{} {
+				useCase.EXPECT().GetProductionOrder(currentStore, uint32(1)).Return(&orderEntity, nil).Times(1)
+				res, err := json.Marshal(orderEntity)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 400 when the order is not in the queue",
+			SetupMocks: func() interface{} {
+				mockErr := &custom_errors.BadRequestError{Message: "Cant find production order"}
+				useCase.EXPECT().GetProductionOrder(currentStore, uint32(1)).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusBadRequest,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when cant find production order",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().GetProductionOrder(currentStore, uint32(1)).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodGet, "/production/order/1", nil)
+			ctx.SetParamNames("orderId")
+			ctx.SetParamValues("1")
+
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.GetProductionOrder(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			if tt.WantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderHandler_CancelProductionOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	canceledOrderEntity := entities.ProductionOrder{
+		OrderId: 1,
+		Status:  entities.CANCELED_STATUS,
+	}
+
+	testCases := []utils.TestCase{
+		{
+			Name: "Should cancel order successfully",
+			SetupMocks: func() interface{} {
+				useCase.EXPECT().CancelProductionOrder(currentStore, uint32(1), entities.Actor{}).Return(&canceledOrderEntity, nil).Times(1)
+				res, err := json.Marshal(canceledOrderEntity)
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusOK,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 400 when the order is already closed",
+			SetupMocks: func() interface{} {
+				mockErr := &custom_errors.BadRequestError{Message: "cant cancel a closed order"}
+				useCase.EXPECT().CancelProductionOrder(currentStore, uint32(1), entities.Actor{}).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusBadRequest,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+		{
+			Name: "Should return 500 when cant cancel order",
+			SetupMocks: func() interface{} {
+				mockErr := errors.New("mock error")
+				useCase.EXPECT().CancelProductionOrder(currentStore, uint32(1), entities.Actor{}).Return(nil, mockErr).Times(1)
+				res, err := json.Marshal(mockErr.Error())
+				assert.NoError(t, err)
+				return map[string]interface{}{
+					"code": http.StatusInternalServerError,
+					"body": string(res),
+				}
+			},
+			WantErr: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodPost, "/production/order/1/cancel", nil)
+			ctx.SetParamNames("orderId")
+			ctx.SetParamValues("1")
+
+			handler := NewProductionOrderHandler(useCase)
+			err := handler.CancelProductionOrder(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+
+			if tt.WantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
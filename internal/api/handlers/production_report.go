@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/adapters/dto"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type ProductionReportHandler struct {
+	productionReportUseCases usecase.ProductionReportUseCases
+}
+
+func NewProductionReportHandler(usecase usecase.ProductionReportUseCases) ProductionReportHandler {
+
+	return ProductionReportHandler{
+		productionReportUseCases: usecase,
+	}
+}
+
+// GetSummary reports on the orders received in the period given by the from and
+// to query parameters.
+//
+// @Tags reports
+// @Produce json
+// @Param from query string false "From, RFC3339 or date"
+// @Param to query string false "To, RFC3339 or date including the whole day"
+// @Success 200 {object} entities.ProductionReport
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/reports/summary [get]
+func (h *ProductionReportHandler) GetSummary(echo echo.Context) error {
+	from, to, err := parsePeriod(echo)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	report, err := h.productionReportUseCases.GetSummary(storeIdFrom(echo), from, to)
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, report)
+}
+
+// parsePeriod reads the period of reports and exports from the from and to
+// query parameters.
+func parsePeriod(echo echo.Context) (from time.Time, to time.Time, err error) {
+	periodDto := dto.PeriodDto{}
+
+	err = echo.Bind(&periodDto)
+
+	if err != nil {
+		return from, to, err
+	}
+
+	return periodDto.ToTimeRange(time.Now())
+}
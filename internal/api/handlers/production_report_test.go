@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_usecase "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProductionReportHandler_GetSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockProductionReportUseCases(ctrl)
+
+	from := time.Date(2024, time.October, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.October, 11, 0, 0, 0, 0, time.UTC)
+	report := entities.ProductionReport{
+		From:        from,
+		To:          to,
+		TotalOrders: 1,
+		CountsByStatus: map[string]int{
+			entities.RECEIVED_STATUS: 1,
+		},
+	}
+
+	testCases := []struct {
+		utils.TestCase
+		Query string
+	}{
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return the summary of the given dates",
+				SetupMocks: func() interface{} {
+					useCase.EXPECT().GetSummary(currentStore, from, to).Return(&report, nil).Times(1)
+					res, err := json.Marshal(report)
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusOK,
+						"body": string(res),
+					}
+				},
+			},
+			Query: "from=2024-10-10&to=2024-10-10",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return the summary of the given timestamps",
+				SetupMocks: func() interface{} {
+					useCase.EXPECT().GetSummary(currentStore, from, to).Return(&report, nil).Times(1)
+					res, err := json.Marshal(report)
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusOK,
+						"body": string(res),
+					}
+				},
+			},
+			Query: "from=2024-10-10T00:00:00Z&to=2024-10-11T00:00:00Z",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 400 when the period is invalid",
+				SetupMocks: func() interface{} {
+					res, err := json.Marshal(`invalid date "yesterday", expected RFC3339 or 2006-01-02`)
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusBadRequest,
+						"body": string(res),
+					}
+				},
+			},
+			Query: "from=yesterday",
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 500 when cant build the summary",
+				SetupMocks: func() interface{} {
+					mockErr := errors.New("mock error")
+					useCase.EXPECT().GetSummary(currentStore, from, to).Return(nil, mockErr).Times(1)
+					res, err := json.Marshal(mockErr.Error())
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusInternalServerError,
+						"body": string(res),
+					}
+				},
+			},
+			Query: "to=2024-10-10",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks()
+			ctx, _, res := echoContext(http.MethodGet, "/production/reports/summary?"+tt.Query, nil)
+			handler := NewProductionReportHandler(useCase)
+			err := handler.GetSummary(ctx)
+
+			responseBody := strings.ReplaceAll(res.Body.String(), "\n", "")
+
+			assert.Equal(t, expectedValue, map[string]interface{}{
+				"code": res.Code,
+				"body": responseBody,
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import "github.com/labstack/echo/v4"
+
+const storeIdContextKey = "storeId"
+
+// StoreScope resolves the store a request belongs to from the storeId path
+// parameter. Routes without that parameter are served for defaultStoreId, which
+// keeps the routes from before stores existed working.
+func StoreScope(defaultStoreId string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			storeId := echo.Param("storeId")
+			if storeId == "" {
+				storeId = defaultStoreId
+			}
+
+			echo.Set(storeIdContextKey, storeId)
+			return next(echo)
+		}
+	}
+}
+
+func storeIdFrom(echo echo.Context) string {
+	storeId, _ := echo.Get(storeIdContextKey).(string)
+	return storeId
+}
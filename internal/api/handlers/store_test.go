@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreScope(t *testing.T) {
+	app := echo.New()
+	scope := StoreScope("default")
+	respondStore := func(echo echo.Context) error {
+		return echo.String(http.StatusOK, storeIdFrom(echo))
+	}
+
+	app.Group("/production", scope).GET("/queue", respondStore)
+	app.Group("/stores/:storeId/production", scope).GET("/queue", respondStore)
+
+	testCases := map[string]string{
+		"/production/queue":               "default",
+		"/stores/loja-2/production/queue": "loja-2",
+	}
+
+	for path, expectedStore := range testCases {
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, expectedStore, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/adapters/dto"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	webhookUseCases usecase.WebhookUseCases
+}
+
+func NewWebhookHandler(usecase usecase.WebhookUseCases) WebhookHandler {
+
+	return WebhookHandler{
+		webhookUseCases: usecase,
+	}
+}
+
+// Subscribe registers a url to be posted the status changes of the store.
+//
+// @Summary Subscribe to the status changes of the store
+// @Description Posts the chosen events to the url, signed with the secret in the X-Webhook-Signature header.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param subscription body dto.SubscribeWebhookDto true "Subscription"
+// @Success 201 {object} entities.WebhookSubscription
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/webhooks [post]
+func (h *WebhookHandler) Subscribe(echo echo.Context) error {
+	subscribeWebhookDto := dto.SubscribeWebhookDto{}
+
+	err := echo.Bind(&subscribeWebhookDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	err = echo.Validate(subscribeWebhookDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	subscription, err := h.webhookUseCases.Subscribe(storeIdFrom(echo), subscribeWebhookDto.ToEntity())
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.JSON(http.StatusCreated, subscription)
+}
+
+// @Summary List the webhook subscriptions of the store
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} entities.WebhookSubscription
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/webhooks [get]
+func (h *WebhookHandler) GetSubscriptions(echo echo.Context) error {
+	subscriptions, err := h.webhookUseCases.GetSubscriptions(storeIdFrom(echo))
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, subscriptions)
+}
+
+// @Summary Delete a webhook subscription and its delivery log
+// @Tags webhooks
+// @Param subscriptionId path string true "Subscription id"
+// @Success 204
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/webhooks/{subscriptionId} [delete]
+func (h *WebhookHandler) Unsubscribe(echo echo.Context) error {
+	err := h.webhookUseCases.Unsubscribe(storeIdFrom(echo), echo.Param("subscriptionId"))
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.NoContent(http.StatusNoContent)
+}
+
+// EnableSubscription enables again a subscription disabled after its
+// deliveries kept failing.
+//
+// @Summary Enable a disabled webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Param subscriptionId path string true "Subscription id"
+// @Success 200 {object} entities.WebhookSubscription
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/webhooks/{subscriptionId}/enable [post]
+func (h *WebhookHandler) EnableSubscription(echo echo.Context) error {
+	subscription, err := h.webhookUseCases.EnableSubscription(storeIdFrom(echo), echo.Param("subscriptionId"))
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, subscription)
+}
+
+// GetDeliveries lists the attempts to post events to a subscription, latest
+// first.
+//
+// @Summary List the delivery log of a webhook subscription
+// @Tags webhooks
+// @Produce json
+// @Param subscriptionId path string true "Subscription id"
+// @Param limit query int false "Deliveries, from 1 to 100" default(50)
+// @Success 200 {array} entities.WebhookDelivery
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Security BearerAuth
+// @Router /production/webhooks/{subscriptionId}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(echo echo.Context) error {
+	listWebhookDeliveriesDto := dto.ListWebhookDeliveriesDto{
+		Limit: defaultListLimit,
+	}
+
+	err := echo.Bind(&listWebhookDeliveriesDto)
+
+	if err != nil {
+		return echo.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, err := h.webhookUseCases.GetDeliveries(storeIdFrom(echo), echo.Param("subscriptionId"), listWebhookDeliveriesDto.Limit)
+
+	if err != nil {
+		return echo.JSON(http.StatusInternalServerError, err.Error())
+	}
+
+	return echo.JSON(http.StatusOK, deliveries)
+}
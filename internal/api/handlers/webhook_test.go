@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_usecase "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/utils"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var partnerSubscription = entities.WebhookSubscription{
+	StoreId:        currentStore,
+	SubscriptionId: "sub-1",
+	URL:            "https://parceiro.example/webhook",
+	Events:         []string{entities.ORDER_READY_EVENT},
+	Secret:         "0123456789abcdef",
+	Enabled:        true,
+	CreatedAt:      time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+}
+
+func TestWebhookHandler_Subscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockWebhookUseCases(ctrl)
+
+	testCases := []struct {
+		utils.TestCase
+		Body string
+	}{
+		{
+			TestCase: utils.TestCase{
+				Name: "Should subscribe without echoing the secret",
+				SetupMocks: func() interface{} {
+					useCase.EXPECT().Subscribe(currentStore, entities.WebhookSubscription{
+						URL:    partnerSubscription.URL,
+						Events: partnerSubscription.Events,
+						Secret: partnerSubscription.Secret,
+					}).Return(&partnerSubscription, nil).Times(1)
+					res, err := json.Marshal(partnerSubscription)
+					assert.NoError(t, err)
+					assert.NotContains(t, string(res), partnerSubscription.Secret)
+					return map[string]interface{}{
+						"code": http.StatusCreated,
+						"body": string(res),
+					}
+				},
+			},
+			Body: `{"url":"https://parceiro.example/webhook","events":["order.ready"],"secret":"0123456789abcdef"}`,
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 400 without events",
+				SetupMocks: func() interface{} {
+					return map[string]interface{}{
+						"code": http.StatusBadRequest,
+					}
+				},
+			},
+			Body: `{"url":"https://parceiro.example/webhook","secret":"0123456789abcdef"}`,
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 500 when the subscription is refused",
+				SetupMocks: func() interface{} {
+					mockErr := errors.New("secret: the length must be between 16 and 256.")
+					useCase.EXPECT().Subscribe(currentStore, gomock.Any()).Return(nil, mockErr).Times(1)
+					res, err := json.Marshal(mockErr.Error())
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusInternalServerError,
+						"body": string(res),
+					}
+				},
+			},
+			Body: `{"url":"https://parceiro.example/webhook","events":["order.ready"],"secret":"curto"}`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks().(map[string]interface{})
+			ctx, _, res := echoContext(http.MethodPost, "/production/webhooks", strings.NewReader(tt.Body))
+			handler := NewWebhookHandler(useCase)
+			err := handler.Subscribe(ctx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedValue["code"], res.Code)
+			if body, ok := expectedValue["body"]; ok {
+				assert.Equal(t, body, strings.ReplaceAll(res.Body.String(), "\n", ""))
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_Unsubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockWebhookUseCases(ctrl)
+	useCase.EXPECT().Unsubscribe(currentStore, "sub-1").Return(nil).Times(1)
+
+	ctx, _, res := echoContext(http.MethodDelete, "/production/webhooks/sub-1", nil)
+	ctx.SetParamNames("subscriptionId")
+	ctx.SetParamValues("sub-1")
+	handler := NewWebhookHandler(useCase)
+
+	assert.NoError(t, handler.Unsubscribe(ctx))
+	assert.Equal(t, http.StatusNoContent, res.Code)
+}
+
+func TestWebhookHandler_GetDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := mock_usecase.NewMockWebhookUseCases(ctrl)
+	deliveries := []entities.WebhookDelivery{
+		entities.NewWebhookDelivery(partnerSubscription, entities.StatusChangeEvent{EventId: "evt-1", Event: entities.ORDER_READY_EVENT}, 1, partnerSubscription.CreatedAt, 200, nil),
+	}
+
+	testCases := []struct {
+		utils.TestCase
+		Query string
+	}{
+		{
+			TestCase: utils.TestCase{
+				Name: "Should list the last 50 deliveries by default",
+				SetupMocks: func() interface{} {
+					useCase.EXPECT().GetDeliveries(currentStore, "sub-1", 50).Return(deliveries, nil).Times(1)
+					res, err := json.Marshal(deliveries)
+					assert.NoError(t, err)
+					return map[string]interface{}{
+						"code": http.StatusOK,
+						"body": string(res),
+					}
+				},
+			},
+		},
+		{
+			TestCase: utils.TestCase{
+				Name: "Should return 400 when the limit is not a number",
+				SetupMocks: func() interface{} {
+					return map[string]interface{}{
+						"code": http.StatusBadRequest,
+					}
+				},
+			},
+			Query: "?limit=muitos",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			expectedValue := tt.SetupMocks().(map[string]interface{})
+			ctx, _, res := echoContext(http.MethodGet, "/production/webhooks/sub-1/deliveries"+tt.Query, nil)
+			ctx.SetParamNames("subscriptionId")
+			ctx.SetParamValues("sub-1")
+			handler := NewWebhookHandler(useCase)
+			err := handler.GetDeliveries(ctx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expectedValue["code"], res.Code)
+			if body, ok := expectedValue["body"]; ok {
+				assert.Equal(t, body, strings.ReplaceAll(res.Body.String(), "\n", ""))
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	KITCHEN_ROLE = "kitchen"
+	MANAGER_ROLE = "manager"
+	SERVICE_ROLE = "service"
+)
+
+const (
+	rolesContextKey   = "roles"
+	subjectContextKey = "subject"
+)
+
+// Authorizer validates the bearer token of the requests and checks the roles it
+// grants against the roles each route accepts.
+type Authorizer struct {
+	enabled    bool
+	hmacSecret []byte
+	jwks       *jsonWebKeySet
+	rolesClaim string
+	parser     *jwt.Parser
+}
+
+// NewAuthorizer builds an Authorizer from the auth configuration. At least one
+// of the HMAC secret, the JWKS file or the JWKS URL is required while auth is
+// enabled.
+func NewAuthorizer(cfg external.AuthConfig) (*Authorizer, error) {
+	authorizer := &Authorizer{
+		enabled:    cfg.Enabled,
+		rolesClaim: cfg.RolesClaim,
+	}
+
+	if !cfg.Enabled {
+		return authorizer, nil
+	}
+
+	if cfg.HMACSecret == "" && cfg.JWKSFile == "" && cfg.JWKSURL == "" {
+		return nil, errors.New("auth is enabled but no hmac secret, jwks file or jwks url is configured")
+	}
+
+	if cfg.HMACSecret != "" {
+		authorizer.hmacSecret = []byte(cfg.HMACSecret)
+	}
+
+	var err error
+	switch {
+	case cfg.JWKSFile != "":
+		authorizer.jwks, err = newFileJSONWebKeySet(cfg.JWKSFile)
+	case cfg.JWKSURL != "":
+		authorizer.jwks, err = newURLJSONWebKeySet(cfg.JWKSURL)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not load jwks: %w", err)
+	}
+
+	if authorizer.rolesClaim == "" {
+		authorizer.rolesClaim = "roles"
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+
+	authorizer.parser = jwt.NewParser(options...)
+
+	return authorizer, nil
+}
+
+// Authenticate rejects requests without a valid bearer token with 401 and keeps
+// the roles of the token for RequireRoles.
+func (a *Authorizer) Authenticate() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			if !a.enabled {
+				return next(echo)
+			}
+
+			subject, roles, err := a.Verify(echo.Request().Header.Get("Authorization"))
+
+			if err != nil {
+				return unauthorized(echo, err.Error())
+			}
+
+			echo.Set(subjectContextKey, subject)
+			echo.Set(rolesContextKey, roles)
+
+			return next(echo)
+		}
+	}
+}
+
+// Enabled tells whether requests are authenticated at all.
+func (a *Authorizer) Enabled() bool {
+	return a.enabled
+}
+
+// Verify validates the bearer token of an Authorization header and returns the
+// subject and the roles it grants, for the APIs served outside Echo.
+func (a *Authorizer) Verify(authorization string) (string, map[string]bool, error) {
+	bearer, found := strings.CutPrefix(authorization, "Bearer ")
+
+	if !found || bearer == "" {
+		return "", nil, errors.New("missing bearer token")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(bearer, claims, a.signingKey)
+
+	if err != nil {
+		return "", nil, errors.New("invalid bearer token")
+	}
+
+	subject, _ := claims.GetSubject()
+
+	// the subject is the actor of the audit trail, which must not come from a
+	// header the caller chooses
+	if subject == "" {
+		return "", nil, errors.New("bearer token has no subject")
+	}
+
+	return subject, a.rolesOf(claims), nil
+}
+
+// SubjectOf returns the subject of a valid bearer token, or an empty string
+// when the token is invalid or auth is disabled.
+func (a *Authorizer) SubjectOf(authorization string) string {
+	if !a.enabled {
+		return ""
+	}
+
+	subject, _, err := a.Verify(authorization)
+
+	if err != nil {
+		return ""
+	}
+
+	return subject
+}
+
+// RequireRoles answers 403 to authenticated requests whose token grants none of
+// the given roles.
+func (a *Authorizer) RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			if !a.enabled {
+				return next(echo)
+			}
+
+			granted, _ := echo.Get(rolesContextKey).(map[string]bool)
+
+			for _, role := range roles {
+				if granted[role] {
+					return next(echo)
+				}
+			}
+
+			return echo.JSON(http.StatusForbidden, "forbidden")
+		}
+	}
+}
+
+// Subject returns the subject of the token of an authenticated request, or an
+// empty string when auth is disabled.
+func Subject(echo echo.Context) string {
+	subject, _ := echo.Get(subjectContextKey).(string)
+	return subject
+}
+
+// Authenticated tells whether the request was authenticated by a token, which
+// it is not when auth is disabled.
+func Authenticated(echo echo.Context) bool {
+	_, authenticated := echo.Get(subjectContextKey).(string)
+	return authenticated
+}
+
+func (a *Authorizer) signingKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if a.hmacSecret == nil {
+			return nil, errors.New("hs256 tokens are not accepted")
+		}
+
+		return a.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		if a.jwks == nil {
+			return nil, errors.New("rs256 tokens are not accepted")
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return a.jwks.Key(kid)
+	}
+
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// rolesOf reads the roles claim, given either as a list or as a space separated
+// string.
+func (a *Authorizer) rolesOf(claims jwt.MapClaims) map[string]bool {
+	roles := map[string]bool{}
+
+	switch value := claims[a.rolesClaim].(type) {
+	case string:
+		for _, role := range strings.Fields(value) {
+			roles[role] = true
+		}
+	case []interface{}:
+		for _, role := range value {
+			if name, ok := role.(string); ok {
+				roles[name] = true
+			}
+		}
+	}
+
+	return roles
+}
+
+func unauthorized(echo echo.Context, message string) error {
+	echo.Response().Header().Set("WWW-Authenticate", "Bearer")
+	return echo.JSON(http.StatusUnauthorized, message)
+}
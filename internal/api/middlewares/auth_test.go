@@ -0,0 +1,219 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const hmacSecret = "test-secret"
+
+func hs256Token(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(hmacSecret))
+	assert.NoError(t, err)
+
+	return token
+}
+
+func rs256Token(t *testing.T, kid string, claims jwt.MapClaims) string {
+	pem, err := os.ReadFile("testdata/private_key.pem")
+	assert.NoError(t, err)
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+	assert.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(privateKey)
+	assert.NoError(t, err)
+
+	return signed
+}
+
+func validClaims(roles ...string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "tester",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": roles,
+	}
+}
+
+// protectedApp serves GET /cancel to managers only.
+func protectedApp(t *testing.T, cfg external.AuthConfig) *echo.Echo {
+	authorizer, err := NewAuthorizer(cfg)
+	assert.NoError(t, err)
+
+	app := echo.New()
+	app.GET("/cancel", func(echo echo.Context) error {
+		return echo.JSON(http.StatusOK, "ok")
+	}, authorizer.Authenticate(), authorizer.RequireRoles(MANAGER_ROLE))
+
+	return app
+}
+
+func TestAuthorizer(t *testing.T) {
+	cfg := external.AuthConfig{
+		Enabled:    true,
+		HMACSecret: hmacSecret,
+		JWKSFile:   "testdata/jwks.json",
+		RolesClaim: "roles",
+	}
+
+	expiredClaims := validClaims(MANAGER_ROLE)
+	expiredClaims["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	testCases := []struct {
+		Name          string
+		Authorization string
+		WantCode      int
+	}{
+		{
+			Name:     "Should return 401 without a token",
+			WantCode: http.StatusUnauthorized,
+		},
+		{
+			Name:          "Should return 401 with a token that is not a jwt",
+			Authorization: "Bearer not-a-token",
+			WantCode:      http.StatusUnauthorized,
+		},
+		{
+			Name:          "Should accept a hs256 token with the route role",
+			Authorization: "Bearer " + hs256Token(t, validClaims(MANAGER_ROLE)),
+			WantCode:      http.StatusOK,
+		},
+		{
+			Name:          "Should accept roles given as a space separated string",
+			Authorization: "Bearer " + hs256Token(t, jwt.MapClaims{"sub": "tester", "exp": time.Now().Add(time.Hour).Unix(), "roles": "kitchen manager"}),
+			WantCode:      http.StatusOK,
+		},
+		{
+			Name:          "Should return 401 with a token without subject",
+			Authorization: "Bearer " + hs256Token(t, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix(), "roles": []string{MANAGER_ROLE}}),
+			WantCode:      http.StatusUnauthorized,
+		},
+		{
+			Name:          "Should return 403 when the token lacks the route role",
+			Authorization: "Bearer " + hs256Token(t, validClaims(KITCHEN_ROLE)),
+			WantCode:      http.StatusForbidden,
+		},
+		{
+			Name:          "Should return 401 with an expired token",
+			Authorization: "Bearer " + hs256Token(t, expiredClaims),
+			WantCode:      http.StatusUnauthorized,
+		},
+		{
+			Name:          "Should return 401 with a token without expiration",
+			Authorization: "Bearer " + hs256Token(t, jwt.MapClaims{"roles": []string{MANAGER_ROLE}}),
+			WantCode:      http.StatusUnauthorized,
+		},
+		{
+			Name:          "Should accept a rs256 token signed by a jwks key",
+			Authorization: "Bearer " + rs256Token(t, "test-key", validClaims(MANAGER_ROLE)),
+			WantCode:      http.StatusOK,
+		},
+		{
+			Name:          "Should return 401 with a rs256 token of an unknown key",
+			Authorization: "Bearer " + rs256Token(t, "other-key", validClaims(MANAGER_ROLE)),
+			WantCode:      http.StatusUnauthorized,
+		},
+	}
+
+	app := protectedApp(t, cfg)
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/cancel", nil)
+			if tt.Authorization != "" {
+				req.Header.Set("Authorization", tt.Authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			app.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.WantCode, rec.Code)
+		})
+	}
+}
+
+func TestAuthorizerClaims(t *testing.T) {
+	app := protectedApp(t, external.AuthConfig{
+		Enabled:    true,
+		HMACSecret: hmacSecret,
+		Issuer:     "fastfood-auth",
+		Audience:   "fastfood-order-production",
+	})
+
+	claims := validClaims(MANAGER_ROLE)
+	claims["iss"] = "fastfood-auth"
+	claims["aud"] = "fastfood-order-production"
+
+	wrongIssuerClaims := validClaims(MANAGER_ROLE)
+	wrongIssuerClaims["iss"] = "someone-else"
+	wrongIssuerClaims["aud"] = "fastfood-order-production"
+
+	for token, wantCode := range map[string]int{
+		hs256Token(t, claims):             http.StatusOK,
+		hs256Token(t, wrongIssuerClaims):  http.StatusUnauthorized,
+		rs256Token(t, "test-key", claims): http.StatusUnauthorized,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/cancel", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+
+		app.ServeHTTP(rec, req)
+
+		assert.Equal(t, wantCode, rec.Code)
+	}
+}
+
+func TestAuthorizerJWKSURL(t *testing.T) {
+	jwks, err := os.ReadFile("testdata/jwks.json")
+	assert.NoError(t, err)
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(jwks)
+	}))
+	defer jwksServer.Close()
+
+	app := protectedApp(t, external.AuthConfig{
+		Enabled: true,
+		JWKSURL: jwksServer.URL,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/cancel", nil)
+	req.Header.Set("Authorization", "Bearer "+rs256Token(t, "test-key", validClaims(MANAGER_ROLE)))
+	rec := httptest.NewRecorder()
+
+	app.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAuthorizerDisabled(t *testing.T) {
+	app := protectedApp(t, external.AuthConfig{Enabled: false})
+
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cancel", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestNewAuthorizerWithoutKeys(t *testing.T) {
+	authorizer, err := NewAuthorizer(external.AuthConfig{Enabled: true})
+
+	assert.Nil(t, authorizer)
+	assert.EqualError(t, err, "auth is enabled but no hmac secret, jwks file or jwks url is configured")
+
+	authorizer, err = NewAuthorizer(external.AuthConfig{Enabled: true, JWKSFile: "testdata/missing.json"})
+
+	assert.Nil(t, authorizer)
+	assert.ErrorContains(t, err, "could not load jwks")
+}
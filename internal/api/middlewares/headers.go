@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// CORS answers preflight requests and adds the CORS headers for the configured
+// origins. Without allowed origins no CORS header is sent, so browsers keep
+// blocking other origins.
+func CORS(cfg external.CORSConfig) echo.MiddlewareFunc {
+	if len(cfg.AllowOrigins) == 0 {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	})
+}
+
+// SecureHeaders adds the security headers to every response. HSTS is only sent
+// over HTTPS, including requests forwarded by a proxy that terminated TLS.
+func SecureHeaders(cfg external.HeadersConfig) echo.MiddlewareFunc {
+	return middleware.SecureWithConfig(middleware.SecureConfig{
+		XSSProtection:         cfg.XSSProtection,
+		ContentTypeNosniff:    cfg.ContentTypeNosniff,
+		XFrameOptions:         cfg.FrameOptions,
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSExcludeSubdomains: !cfg.HSTSIncludeSubdomains,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		ReferrerPolicy:        cfg.ReferrerPolicy,
+	})
+}
@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var corsConfig = external.CORSConfig{
+	AllowOrigins:  []string{"https://painel.fastfood.com"},
+	AllowMethods:  []string{http.MethodGet, http.MethodPut},
+	AllowHeaders:  []string{"Authorization", "Content-Type"},
+	ExposeHeaders: []string{"X-Request-Id"},
+	MaxAge:        600,
+}
+
+func headersApp(middlewares ...echo.MiddlewareFunc) *echo.Echo {
+	app := echo.New()
+	app.Use(middlewares...)
+	app.GET("/queue", func(echo echo.Context) error {
+		return echo.JSON(http.StatusOK, "ok")
+	})
+
+	return app
+}
+
+func preflight(app *echo.Echo, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/queue", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestCORS(t *testing.T) {
+	app := headersApp(CORS(corsConfig))
+
+	allowed := preflight(app, "https://painel.fastfood.com")
+	assert.Equal(t, http.StatusNoContent, allowed.Code)
+	assert.Equal(t, "https://painel.fastfood.com", allowed.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET,PUT", allowed.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "600", allowed.Header().Get("Access-Control-Max-Age"))
+
+	denied := preflight(app, "https://outro.com")
+	assert.Empty(t, denied.Header().Get("Access-Control-Allow-Origin"))
+
+	req := httptest.NewRequest(http.MethodGet, "/queue", nil)
+	req.Header.Set("Origin", "https://painel.fastfood.com")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "X-Request-Id", rec.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestCORSDisabled(t *testing.T) {
+	app := headersApp(CORS(external.CORSConfig{}))
+
+	rec := preflight(app, "https://painel.fastfood.com")
+
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestSecureHeaders(t *testing.T) {
+	app := headersApp(SecureHeaders(external.HeadersConfig{
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+		ContentTypeNosniff:    "nosniff",
+		XSSProtection:         "0",
+		ReferrerPolicy:        "no-referrer",
+		ContentSecurityPolicy: "default-src 'none'",
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/queue", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "0", rec.Header().Get("X-XSS-Protection"))
+	assert.Equal(t, "no-referrer", rec.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "default-src 'none'", rec.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "max-age=31536000; includeSubdomains", rec.Header().Get("Strict-Transport-Security"))
+
+	plain := httptest.NewRecorder()
+	app.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, "/queue", nil))
+
+	assert.Empty(t, plain.Header().Get("Strict-Transport-Security"))
+}
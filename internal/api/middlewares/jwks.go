@@ -0,0 +1,163 @@
+package middlewares
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwksRefreshInterval limits how often an unknown key id makes the key set be
+// fetched again, so forged tokens cannot be used to flood the JWKS URL.
+const jwksRefreshInterval = time.Minute
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jsonWebKeySet keeps the RSA keys of a JWKS document by key id. Keys read from
+// a URL are fetched again when a token names a key that is not known yet, which
+// is how the issuer rotates its keys.
+type jsonWebKeySet struct {
+	load        func() ([]byte, error)
+	refreshable bool
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	refreshedAt time.Time
+}
+
+func newFileJSONWebKeySet(path string) (*jsonWebKeySet, error) {
+	keySet := &jsonWebKeySet{
+		load: func() ([]byte, error) {
+			return os.ReadFile(path)
+		},
+	}
+
+	return keySet, keySet.refresh()
+}
+
+func newURLJSONWebKeySet(url string) (*jsonWebKeySet, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	keySet := &jsonWebKeySet{
+		refreshable: true,
+		load: func() ([]byte, error) {
+			response, err := client.Get(url)
+
+			if err != nil {
+				return nil, err
+			}
+
+			defer response.Body.Close()
+
+			if response.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("jwks url answered %s", response.Status)
+			}
+
+			return io.ReadAll(response.Body)
+		},
+	}
+
+	return keySet, keySet.refresh()
+}
+
+// Key returns the public key with the given id.
+func (s *jsonWebKeySet) Key(kid string) (*rsa.PublicKey, error) {
+	s.mu.RLock()
+	key, found := s.keys[kid]
+	refreshedAt := s.refreshedAt
+	s.mu.RUnlock()
+
+	if found {
+		return key, nil
+	}
+
+	if s.refreshable && time.Since(refreshedAt) > jwksRefreshInterval {
+		err := s.refresh()
+
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.RLock()
+		key, found = s.keys[kid]
+		s.mu.RUnlock()
+
+		if found {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+func (s *jsonWebKeySet) refresh() error {
+	document, err := s.load()
+
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJSONWebKeySet(document)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.refreshedAt = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
+
+// parseJSONWebKeySet reads the RSA signing keys of a JWKS document. Keys of
+// other types or meant for encryption are ignored.
+func parseJSONWebKeySet(document []byte) (map[string]*rsa.PublicKey, error) {
+	keySet := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+
+	err := json.Unmarshal(document, &keySet)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, key := range keySet.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+
+		modulus, err := base64.RawURLEncoding.DecodeString(key.N)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of key %q: %w", key.Kid, err)
+		}
+
+		exponent, err := base64.RawURLEncoding.DecodeString(key.E)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of key %q: %w", key.Kid, err)
+		}
+
+		keys[key.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(modulus),
+			E: int(new(big.Int).SetBytes(exponent).Int64()),
+		}
+	}
+
+	return keys, nil
+}
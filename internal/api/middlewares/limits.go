@@ -0,0 +1,146 @@
+package middlewares
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+const (
+	IP_RATE_KEY      = "ip"
+	SUBJECT_RATE_KEY = "subject"
+)
+
+// rateLimitExpiration is how long the bucket of an idle client is kept.
+const rateLimitExpiration = 3 * time.Minute
+
+// IPExtractor reads the client IP from X-Forwarded-For only when the request
+// comes from one of the trusted proxy ranges, and from the connection
+// otherwise, so clients cannot pick the IP they are limited by.
+func IPExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range trustedProxies {
+		_, network, err := net.ParseCIDR(proxy)
+
+		if err == nil {
+			options = append(options, echo.TrustIPRange(network))
+		}
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// RateLimiter gives every client a token bucket refilled at cfg.Rate requests
+// per second holding up to cfg.Burst requests. Clients are told by their IP or,
+// with the subject rate key, by the subject of their token when it is valid.
+type RateLimiter struct {
+	store      middleware.RateLimiterStore
+	rateKey    string
+	authorizer *Authorizer
+	retryAfter string
+}
+
+// NewRateLimiter builds the buckets of the clients. A rate that is not positive
+// disables the limit.
+func NewRateLimiter(cfg external.LimitsConfig, authorizer *Authorizer) *RateLimiter {
+	if cfg.Rate <= 0 {
+		return &RateLimiter{}
+	}
+
+	burst := cfg.Burst
+	if burst < 1 {
+		burst = int(math.Ceil(cfg.Rate))
+	}
+
+	return &RateLimiter{
+		store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Limit(cfg.Rate),
+			Burst:     burst,
+			ExpiresIn: rateLimitExpiration,
+		}),
+		rateKey:    cfg.RateKey,
+		authorizer: authorizer,
+		retryAfter: strconv.Itoa(int(math.Ceil(1 / cfg.Rate))),
+	}
+}
+
+// Allow takes a token from the bucket of the client calling from ip with the
+// authorization header, and tells whether there was one.
+func (l *RateLimiter) Allow(authorization string, ip string) bool {
+	if l.store == nil {
+		return true
+	}
+
+	identifier := "ip:" + ip
+	if l.rateKey == SUBJECT_RATE_KEY {
+		if subject := l.authorizer.SubjectOf(authorization); subject != "" {
+			identifier = "subject:" + subject
+		}
+	}
+
+	allowed, err := l.store.Allow(identifier)
+
+	return err == nil && allowed
+}
+
+// RetryAfter is how many seconds a client over the limit waits for a token.
+func (l *RateLimiter) RetryAfter() string {
+	return l.retryAfter
+}
+
+// RateLimit limits the requests of every client with a RateLimiter. It runs
+// before Authenticate, so requests failing authentication are limited as well.
+// Requests over the limit are answered 429 with Retry-After.
+func RateLimit(cfg external.LimitsConfig, authorizer *Authorizer) echo.MiddlewareFunc {
+	limiter := NewRateLimiter(cfg, authorizer)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			if limiter.Allow(echo.Request().Header.Get("Authorization"), echo.RealIP()) {
+				return next(echo)
+			}
+
+			echo.Response().Header().Set("Retry-After", limiter.RetryAfter())
+			return echo.JSON(http.StatusTooManyRequests, "too many requests")
+		}
+	}
+}
+
+// RequestTimeout answers 503 to requests still running after timeout. It wraps
+// the whole server instead of being an echo middleware so a handler that goes on
+// after the timeout never writes to a response already sent. Requests accepted
+// by skip, such as streamed exports, are not limited.
+func RequestTimeout(timeout time.Duration, skip func(request *http.Request) bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+
+		limited := http.TimeoutHandler(next, timeout, `"request timed out"`)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
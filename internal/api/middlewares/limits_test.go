@@ -0,0 +1,158 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func limitedApp(middlewares ...echo.MiddlewareFunc) *echo.Echo {
+	app := echo.New()
+	app.IPExtractor = IPExtractor(nil)
+	app.GET("/queue", func(echo echo.Context) error {
+		return echo.JSON(http.StatusOK, "ok")
+	}, middlewares...)
+	app.GET("/slow", func(echo echo.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return echo.JSON(http.StatusOK, "ok")
+	}, middlewares...)
+
+	return app
+}
+
+func requestFrom(app *echo.Echo, path string, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func disabledAuthorizer(t *testing.T) *Authorizer {
+	authorizer, err := NewAuthorizer(external.AuthConfig{})
+	assert.NoError(t, err)
+
+	return authorizer
+}
+
+func TestRateLimit(t *testing.T) {
+	app := limitedApp(RateLimit(external.LimitsConfig{
+		Rate:    0.5,
+		Burst:   2,
+		RateKey: IP_RATE_KEY,
+	}, disabledAuthorizer(t)))
+
+	assert.Equal(t, http.StatusOK, requestFrom(app, "/queue", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, requestFrom(app, "/queue", "10.0.0.1").Code)
+
+	limited := requestFrom(app, "/queue", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "2", limited.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, requestFrom(app, "/queue", "10.0.0.2").Code)
+}
+
+func TestRateLimitSpoofedForwardedFor(t *testing.T) {
+	app := limitedApp(RateLimit(external.LimitsConfig{
+		Rate:    0.5,
+		Burst:   1,
+		RateKey: IP_RATE_KEY,
+	}, disabledAuthorizer(t)))
+
+	spoofed := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/queue", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, spoofed("203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, spoofed("203.0.113.2"))
+}
+
+func TestIPExtractorTrustedProxies(t *testing.T) {
+	extract := IPExtractor([]string{"10.1.0.0/16"})
+
+	fromProxy := httptest.NewRequest(http.MethodGet, "/queue", nil)
+	fromProxy.RemoteAddr = "10.1.0.5:1234"
+	fromProxy.Header.Set(echo.HeaderXForwardedFor, "203.0.113.1")
+	assert.Equal(t, "203.0.113.1", extract(fromProxy))
+
+	fromClient := httptest.NewRequest(http.MethodGet, "/queue", nil)
+	fromClient.RemoteAddr = "10.2.0.5:1234"
+	fromClient.Header.Set(echo.HeaderXForwardedFor, "203.0.113.1")
+	assert.Equal(t, "10.2.0.5", extract(fromClient))
+}
+
+func TestRateLimitBySubject(t *testing.T) {
+	authorizer, err := NewAuthorizer(external.AuthConfig{
+		Enabled:    true,
+		HMACSecret: hmacSecret,
+		RolesClaim: "roles",
+	})
+	assert.NoError(t, err)
+
+	cfg := external.LimitsConfig{
+		Rate:    1,
+		Burst:   1,
+		RateKey: SUBJECT_RATE_KEY,
+	}
+	app := limitedApp(RateLimit(cfg, authorizer), authorizer.Authenticate())
+
+	requestAs := func(authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, "/queue", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("Authorization", authorization)
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+	tablet := "Bearer " + hs256Token(t, jwt.MapClaims{"sub": "tablet-1", "exp": time.Now().Add(time.Hour).Unix()})
+	kiosk := "Bearer " + hs256Token(t, jwt.MapClaims{"sub": "kiosk-1", "exp": time.Now().Add(time.Hour).Unix()})
+
+	assert.Equal(t, http.StatusOK, requestAs(tablet))
+	assert.Equal(t, http.StatusTooManyRequests, requestAs(tablet))
+	assert.Equal(t, http.StatusOK, requestAs(kiosk))
+
+	// invalid tokens are limited by IP before they are rejected
+	assert.Equal(t, http.StatusUnauthorized, requestAs("Bearer not-a-token"))
+	assert.Equal(t, http.StatusTooManyRequests, requestAs("Bearer not-a-token"))
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	app := limitedApp(RateLimit(external.LimitsConfig{}, disabledAuthorizer(t)))
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, requestFrom(app, "/queue", "10.0.0.1").Code)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	skipQueue := func(request *http.Request) bool {
+		return request.URL.Path == "/queue"
+	}
+	app := limitedApp()
+	handler := RequestTimeout(10*time.Millisecond, skipQueue)(app)
+
+	timedOut := httptest.NewRecorder()
+	handler.ServeHTTP(timedOut, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, timedOut.Code)
+	assert.Equal(t, `"request timed out"`, timedOut.Body.String())
+
+	skipped := httptest.NewRecorder()
+	handler.ServeHTTP(skipped, httptest.NewRequest(http.MethodGet, "/queue", nil))
+	assert.Equal(t, http.StatusOK, skipped.Code)
+
+	assert.Equal(t, app, RequestTimeout(0, skipQueue)(app))
+}
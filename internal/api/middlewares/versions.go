@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Deprecation describes routes replaced by a newer version of the API.
+type Deprecation struct {
+	// At is when the routes were deprecated.
+	At time.Time
+	// Sunset is when the routes stop being served, zero while it is not decided.
+	Sunset time.Time
+	// Prefix is the path prefix of the deprecated routes and Successor the one
+	// of the routes replacing them, such as "/v1" and "/v2".
+	Prefix    string
+	Successor string
+}
+
+// Deprecated tells the clients of deprecated routes, on every response, since
+// when they are deprecated, when they stop being served once that is decided
+// and where the same route is in the successor version, with the Deprecation
+// (RFC 9745), Sunset (RFC 8594) and Link headers.
+func Deprecated(deprecation Deprecation) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echo echo.Context) error {
+			header := echo.Response().Header()
+			header.Set("Deprecation", fmt.Sprintf("@%d", deprecation.At.Unix()))
+
+			if !deprecation.Sunset.IsZero() {
+				header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+			}
+
+			successor := deprecation.Successor + strings.TrimPrefix(echo.Request().URL.Path, deprecation.Prefix)
+			header.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+
+			return next(echo)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	deprecatedAt := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	app := echo.New()
+	app.GET("/v1/production/queue", func(echo echo.Context) error {
+		return echo.JSON(http.StatusOK, "ok")
+	}, Deprecated(Deprecation{At: deprecatedAt, Prefix: "/v1", Successor: "/v2"}))
+	app.GET("/production/queue", func(echo echo.Context) error {
+		return echo.JSON(http.StatusOK, "ok")
+	}, Deprecated(Deprecation{At: deprecatedAt, Sunset: time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC), Successor: "/v1"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/production/queue", nil)
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "@1792368000", rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/production/queue>; rel="successor-version"`, rec.Header().Get("Link"))
+
+	req = httptest.NewRequest(http.MethodGet, "/production/queue", nil)
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+
+	assert.Equal(t, "@1792368000", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, `</v1/production/queue>; rel="successor-version"`, rec.Header().Get("Link"))
+}
@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/middlewares"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type subjectContextKey struct{}
+
+var (
+	kitchen  = []string{middlewares.KITCHEN_ROLE, middlewares.MANAGER_ROLE}
+	viewers  = []string{middlewares.KITCHEN_ROLE, middlewares.MANAGER_ROLE, middlewares.SERVICE_ROLE}
+	services = []string{middlewares.SERVICE_ROLE}
+)
+
+// methodRoles are the roles each method of the service accepts, the same as
+// the ones of the matching REST routes. Methods of other services, such as
+// health checking and reflection, need no token.
+var methodRoles = map[string][]string{
+	pb.ProductionOrderService_SendOrderToProduction_FullMethodName: services,
+	pb.ProductionOrderService_UpdateStatus_FullMethodName:          kitchen,
+	pb.ProductionOrderService_GetQueue_FullMethodName:              viewers,
+	pb.ProductionOrderService_GetOrder_FullMethodName:              viewers,
+	pb.ProductionOrderService_WatchQueue_FullMethodName:            viewers,
+}
+
+// authenticator checks the bearer token of the authorization metadata of the
+// calls like the Authenticate and RequireRoles middlewares do for the routes.
+type authenticator struct {
+	authorizer *middlewares.Authorizer
+}
+
+func (a authenticator) unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a authenticator) stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate answers Unauthenticated to calls without a valid token and
+// PermissionDenied to calls whose token grants none of the roles of the method,
+// and keeps the subject of the token for the audit trail.
+func (a authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	roles, protected := methodRoles[method]
+
+	if !protected || !a.authorizer.Enabled() {
+		return ctx, nil
+	}
+
+	subject, granted, err := a.authorizer.Verify(metadataValue(ctx, "authorization"))
+
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	for _, role := range roles {
+		if granted[role] {
+			return context.WithValue(ctx, subjectContextKey{}, subject), nil
+		}
+	}
+
+	return nil, status.Error(codes.PermissionDenied, "forbidden")
+}
+
+// authenticatedStream carries the context holding the subject of the token to
+// the handler of a streaming call.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// subjectFrom returns the subject of the token of the call and whether the
+// call was authenticated at all.
+func subjectFrom(ctx context.Context) (string, bool) {
+	subject, authenticated := ctx.Value(subjectContextKey{}).(string)
+	return subject, authenticated
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(values[0])
+}
@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/middlewares"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// limiter applies the rate limit and the request timeout of the REST API to the
+// calls of the service. Clients are told by the address they connect from, as
+// the port is not behind the proxies of the REST API. Methods of other
+// services, such as health checking, are not limited, like GET /ready.
+type limiter struct {
+	rateLimiter *middlewares.RateLimiter
+	timeout     time.Duration
+}
+
+// unary answers ResourceExhausted to calls over the limit and DeadlineExceeded
+// to calls still running after the timeout. A call that goes on after the
+// timeout finishes in the background, as with the REST API.
+func (l limiter) unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		if l.timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+
+		type result struct {
+			resp interface{}
+			err  error
+		}
+		done := make(chan result, 1)
+
+		go func() {
+			resp, err := handler(ctx, req)
+			done <- result{resp: resp, err: err}
+		}()
+
+		select {
+		case result := <-done:
+			return result.resp, result.err
+		case <-ctx.Done():
+			return nil, status.Error(codes.DeadlineExceeded, "request timed out")
+		}
+	}
+}
+
+// stream answers ResourceExhausted to streams opened over the limit. Streams
+// run until the client leaves, so they are not cut by the timeout, like the
+// streamed exports of the REST API.
+func (l limiter) stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.allow(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+func (l limiter) allow(ctx context.Context, method string) error {
+	if _, limited := methodRoles[method]; !limited {
+		return nil
+	}
+
+	if !l.rateLimiter.Allow(metadataValue(ctx, "authorization"), peerIP(ctx)) {
+		return status.Error(codes.ResourceExhausted, "too many requests")
+	}
+
+	return nil
+}
+
+// peerIP is the IP the call comes from, without the port.
+func peerIP(ctx context.Context) string {
+	client, ok := peer.FromContext(ctx)
+
+	if !ok || client.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(client.Addr.String())
+
+	if err != nil {
+		return client.Addr.String()
+	}
+
+	return host
+}
@@ -0,0 +1,298 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.3
+// source: production_order.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ProductionOrderService_SendOrderToProduction_FullMethodName = "/production.v1.ProductionOrderService/SendOrderToProduction"
+	ProductionOrderService_UpdateStatus_FullMethodName          = "/production.v1.ProductionOrderService/UpdateStatus"
+	ProductionOrderService_GetQueue_FullMethodName              = "/production.v1.ProductionOrderService/GetQueue"
+	ProductionOrderService_GetOrder_FullMethodName              = "/production.v1.ProductionOrderService/GetOrder"
+	ProductionOrderService_WatchQueue_FullMethodName            = "/production.v1.ProductionOrderService/WatchQueue"
+)
+
+// ProductionOrderServiceClient is the client API for ProductionOrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ProductionOrderService is the production queue of a store, served alongside
+// the REST API with the same rules. Requests without a store_id are served for
+// the default store.
+type ProductionOrderServiceClient interface {
+	// SendOrderToProduction puts an order in the queue as RECEBIDO.
+	SendOrderToProduction(ctx context.Context, in *SendOrderToProductionRequest, opts ...grpc.CallOption) (*ProductionOrder, error)
+	// UpdateStatus moves an order to the given status.
+	UpdateStatus(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*ProductionOrder, error)
+	// GetQueue returns the orders in production, in the order they are prepared.
+	GetQueue(ctx context.Context, in *GetQueueRequest, opts ...grpc.CallOption) (*ProductionOrderQueue, error)
+	// GetOrder returns an order, in production or not.
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*ProductionOrder, error)
+	// WatchQueue sends the queue when the call starts and again every time it
+	// changes, until the client cancels the call.
+	WatchQueue(ctx context.Context, in *GetQueueRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductionOrderQueue], error)
+}
+
+type productionOrderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProductionOrderServiceClient(cc grpc.ClientConnInterface) ProductionOrderServiceClient {
+	return &productionOrderServiceClient{cc}
+}
+
+func (c *productionOrderServiceClient) SendOrderToProduction(ctx context.Context, in *SendOrderToProductionRequest, opts ...grpc.CallOption) (*ProductionOrder, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductionOrder)
+	err := c.cc.Invoke(ctx, ProductionOrderService_SendOrderToProduction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productionOrderServiceClient) UpdateStatus(ctx context.Context, in *UpdateStatusRequest, opts ...grpc.CallOption) (*ProductionOrder, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductionOrder)
+	err := c.cc.Invoke(ctx, ProductionOrderService_UpdateStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productionOrderServiceClient) GetQueue(ctx context.Context, in *GetQueueRequest, opts ...grpc.CallOption) (*ProductionOrderQueue, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductionOrderQueue)
+	err := c.cc.Invoke(ctx, ProductionOrderService_GetQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productionOrderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*ProductionOrder, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductionOrder)
+	err := c.cc.Invoke(ctx, ProductionOrderService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productionOrderServiceClient) WatchQueue(ctx context.Context, in *GetQueueRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProductionOrderQueue], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProductionOrderService_ServiceDesc.Streams[0], ProductionOrderService_WatchQueue_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetQueueRequest, ProductionOrderQueue]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductionOrderService_WatchQueueClient = grpc.ServerStreamingClient[ProductionOrderQueue]
+
+// ProductionOrderServiceServer is the server API for ProductionOrderService service.
+// All implementations must embed UnimplementedProductionOrderServiceServer
+// for forward compatibility.
+//
+// ProductionOrderService is the production queue of a store, served alongside
+// the REST API with the same rules. Requests without a store_id are served for
+// the default store.
+type ProductionOrderServiceServer interface {
+	// SendOrderToProduction puts an order in the queue as RECEBIDO.
+	SendOrderToProduction(context.Context, *SendOrderToProductionRequest) (*ProductionOrder, error)
+	// UpdateStatus moves an order to the given status.
+	UpdateStatus(context.Context, *UpdateStatusRequest) (*ProductionOrder, error)
+	// GetQueue returns the orders in production, in the order they are prepared.
+	GetQueue(context.Context, *GetQueueRequest) (*ProductionOrderQueue, error)
+	// GetOrder returns an order, in production or not.
+	GetOrder(context.Context, *GetOrderRequest) (*ProductionOrder, error)
+	// WatchQueue sends the queue when the call starts and again every time it
+	// changes, until the client cancels the call.
+	WatchQueue(*GetQueueRequest, grpc.ServerStreamingServer[ProductionOrderQueue]) error
+	mustEmbedUnimplementedProductionOrderServiceServer()
+}
+
+// UnimplementedProductionOrderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProductionOrderServiceServer struct{}
+
+func (UnimplementedProductionOrderServiceServer) SendOrderToProduction(context.Context, *SendOrderToProductionRequest) (*ProductionOrder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendOrderToProduction not implemented")
+}
+func (UnimplementedProductionOrderServiceServer) UpdateStatus(context.Context, *UpdateStatusRequest) (*ProductionOrder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStatus not implemented")
+}
+func (UnimplementedProductionOrderServiceServer) GetQueue(context.Context, *GetQueueRequest) (*ProductionOrderQueue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueue not implemented")
+}
+func (UnimplementedProductionOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*ProductionOrder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
+func (UnimplementedProductionOrderServiceServer) WatchQueue(*GetQueueRequest, grpc.ServerStreamingServer[ProductionOrderQueue]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchQueue not implemented")
+}
+func (UnimplementedProductionOrderServiceServer) mustEmbedUnimplementedProductionOrderServiceServer() {
+}
+func (UnimplementedProductionOrderServiceServer) testEmbeddedByValue() {}
+
+// UnsafeProductionOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProductionOrderServiceServer will
+// result in compilation errors.
+type UnsafeProductionOrderServiceServer interface {
+	mustEmbedUnimplementedProductionOrderServiceServer()
+}
+
+func RegisterProductionOrderServiceServer(s grpc.ServiceRegistrar, srv ProductionOrderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedProductionOrderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ProductionOrderService_ServiceDesc, srv)
+}
+
+func _ProductionOrderService_SendOrderToProduction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOrderToProductionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductionOrderServiceServer).SendOrderToProduction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductionOrderService_SendOrderToProduction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductionOrderServiceServer).SendOrderToProduction(ctx, req.(*SendOrderToProductionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductionOrderService_UpdateStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductionOrderServiceServer).UpdateStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductionOrderService_UpdateStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductionOrderServiceServer).UpdateStatus(ctx, req.(*UpdateStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductionOrderService_GetQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductionOrderServiceServer).GetQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductionOrderService_GetQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductionOrderServiceServer).GetQueue(ctx, req.(*GetQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductionOrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductionOrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductionOrderService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductionOrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductionOrderService_WatchQueue_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetQueueRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProductionOrderServiceServer).WatchQueue(m, &grpc.GenericServerStream[GetQueueRequest, ProductionOrderQueue]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProductionOrderService_WatchQueueServer = grpc.ServerStreamingServer[ProductionOrderQueue]
+
+// ProductionOrderService_ServiceDesc is the grpc.ServiceDesc for ProductionOrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProductionOrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "production.v1.ProductionOrderService",
+	HandlerType: (*ProductionOrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendOrderToProduction",
+			Handler:    _ProductionOrderService_SendOrderToProduction_Handler,
+		},
+		{
+			MethodName: "UpdateStatus",
+			Handler:    _ProductionOrderService_UpdateStatus_Handler,
+		},
+		{
+			MethodName: "GetQueue",
+			Handler:    _ProductionOrderService_GetQueue_Handler,
+		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _ProductionOrderService_GetOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchQueue",
+			Handler:       _ProductionOrderService_WatchQueue_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "production_order.proto",
+}
@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/rpc/pb"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toOrder(order *entities.ProductionOrder) *pb.ProductionOrder {
+	message := &pb.ProductionOrder{
+		StoreId:   order.StoreId,
+		OrderId:   order.OrderId,
+		Status:    order.Status,
+		Priority:  order.Priority,
+		CreatedAt: toTimestamp(order.CreatedAt),
+		Items:     toItems(order.Items),
+	}
+
+	for _, ticket := range order.Tickets {
+		message.Tickets = append(message.Tickets, &pb.StationTicket{
+			Station: ticket.Station,
+			Status:  ticket.Status,
+			Items:   toItems(ticket.Items),
+		})
+	}
+
+	for _, transition := range order.History {
+		message.History = append(message.History, &pb.StatusTransition{
+			Status: transition.Status,
+			At:     toTimestamp(transition.At),
+		})
+	}
+
+	if order.SLA != nil {
+		message.Sla = &pb.OrderSLA{
+			ElapsedSeconds: order.SLA.ElapsedSeconds,
+			TargetSeconds:  order.SLA.TargetSeconds,
+			Late:           order.SLA.Late,
+		}
+	}
+
+	if order.EstimatedReadyAt != nil {
+		message.EstimatedReadyAt = toTimestamp(*order.EstimatedReadyAt)
+	}
+
+	return message
+}
+
+func toQueue(queue *entities.ProductionOrderQueue) *pb.ProductionOrderQueue {
+	message := &pb.ProductionOrderQueue{}
+	for i := range queue.Orders {
+		message.Orders = append(message.Orders, toOrder(&queue.Orders[i]))
+	}
+
+	return message
+}
+
+func toItems(items []entities.ProductionOrderItem) []*pb.ProductionOrderItem {
+	var messages []*pb.ProductionOrderItem
+	for _, item := range items {
+		messages = append(messages, &pb.ProductionOrderItem{
+			Name:     item.Name,
+			Category: item.Category,
+			Quantity: item.Quantity,
+		})
+	}
+
+	return messages
+}
+
+func fromItems(messages []*pb.ProductionOrderItem) []entities.ProductionOrderItem {
+	var items []entities.ProductionOrderItem
+	for _, message := range messages {
+		items = append(items, entities.ProductionOrderItem{
+			Name:     message.GetName(),
+			Category: message.GetCategory(),
+			Quantity: message.GetQuantity(),
+		})
+	}
+
+	return items
+}
+
+func toTimestamp(at time.Time) *timestamppb.Timestamp {
+	if at.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(at)
+}
+
+// watchedState is the queue without the elapsed and estimated ready times,
+// which WatchQueue does not send changes of.
+func watchedState(queue *pb.ProductionOrderQueue) *pb.ProductionOrderQueue {
+	state := proto.Clone(queue).(*pb.ProductionOrderQueue)
+	for _, order := range state.Orders {
+		order.EstimatedReadyAt = nil
+		if order.Sla != nil {
+			order.Sla.ElapsedSeconds = 0
+		}
+	}
+
+	return state
+}
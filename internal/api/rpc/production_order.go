@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/rpc/pb"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProductionOrderServer serves the production queue over gRPC on top of the
+// same use cases as the REST handlers.
+type ProductionOrderServer struct {
+	pb.UnimplementedProductionOrderServiceServer
+
+	productionOrderUseCases usecase.ProductionOrderUseCases
+	defaultStoreId          string
+	watchInterval           time.Duration
+}
+
+func NewProductionOrderServer(usecase usecase.ProductionOrderUseCases, defaultStoreId string, watchInterval time.Duration) *ProductionOrderServer {
+	return &ProductionOrderServer{
+		productionOrderUseCases: usecase,
+		defaultStoreId:          defaultStoreId,
+		watchInterval:           watchInterval,
+	}
+}
+
+func (s *ProductionOrderServer) SendOrderToProduction(ctx context.Context, req *pb.SendOrderToProductionRequest) (*pb.ProductionOrder, error) {
+	if req.GetOrderId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	order, err := s.productionOrderUseCases.SendOrderToProduction(s.storeId(req.GetStoreId()), entities.ProductionOrder{
+		OrderId:  req.GetOrderId(),
+		Priority: req.GetPriority(),
+		Items:    fromItems(req.GetItems()),
+	})
+
+	if err != nil {
+		return nil, statusFrom(err)
+	}
+
+	return toOrder(order), nil
+}
+
+func (s *ProductionOrderServer) UpdateStatus(ctx context.Context, req *pb.UpdateStatusRequest) (*pb.ProductionOrder, error) {
+	if req.GetStatus() == "" {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	order, err := s.productionOrderUseCases.UpdateProductionOrderStatus(s.storeId(req.GetStoreId()), req.GetOrderId(), req.GetStatus(), actorFrom(ctx))
+
+	if err != nil {
+		return nil, statusFrom(err)
+	}
+
+	return toOrder(order), nil
+}
+
+func (s *ProductionOrderServer) GetQueue(ctx context.Context, req *pb.GetQueueRequest) (*pb.ProductionOrderQueue, error) {
+	queue, err := s.productionOrderUseCases.GetProductionOrderQueue(s.storeId(req.GetStoreId()))
+
+	if err != nil {
+		return nil, statusFrom(err)
+	}
+
+	return toQueue(queue), nil
+}
+
+func (s *ProductionOrderServer) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.ProductionOrder, error) {
+	order, err := s.productionOrderUseCases.GetProductionOrder(s.storeId(req.GetStoreId()), req.GetOrderId())
+
+	if err != nil {
+		return nil, statusFrom(err)
+	}
+
+	return toOrder(order), nil
+}
+
+// WatchQueue sends the queue when the call starts and checks it every watch
+// interval, sending it again when an order entered, left or moved in the queue
+// or changed status, priority or lateness. The elapsed time and the estimated
+// ready time alone, which change on every check, are not sent.
+func (s *ProductionOrderServer) WatchQueue(req *pb.GetQueueRequest, stream grpc.ServerStreamingServer[pb.ProductionOrderQueue]) error {
+	storeId := s.storeId(req.GetStoreId())
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	var sent *pb.ProductionOrderQueue
+	for {
+		queue, err := s.productionOrderUseCases.GetProductionOrderQueue(storeId)
+
+		if err != nil {
+			return statusFrom(err)
+		}
+
+		current := toQueue(queue)
+		if sent == nil || !proto.Equal(watchedState(sent), watchedState(current)) {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+
+			sent = current
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// storeId resolves the store of a request, the default store serving the
+// requests without one like the routes without a storeId do.
+func (s *ProductionOrderServer) storeId(storeId string) string {
+	if storeId == "" {
+		return s.defaultStoreId
+	}
+
+	return storeId
+}
+
+// actorFrom identifies who made the call for the audit trail: the subject of
+// the bearer token. The x-actor metadata is only trusted when the call was not
+// authenticated, as auth is disabled.
+func actorFrom(ctx context.Context) entities.Actor {
+	name, authenticated := subjectFrom(ctx)
+	if !authenticated {
+		name = metadataValue(ctx, "x-actor")
+	}
+
+	return entities.Actor{
+		Name:      name,
+		Station:   metadataValue(ctx, "x-station"),
+		RequestId: metadataValue(ctx, "x-request-id"),
+	}
+}
+
+// statusFrom maps the errors of the use cases to gRPC status codes, as the
+// handlers map them to HTTP status codes.
+func statusFrom(err error) error {
+	var badRequest *custom_errors.BadRequestError
+	if errors.As(err, &badRequest) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var validationError *custom_errors.ValidationError
+	if errors.As(err, &validationError) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	var databaseError *custom_errors.DatabaseError
+	if errors.As(err, &databaseError) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
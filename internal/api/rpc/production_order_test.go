@@ -0,0 +1,305 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/middlewares"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/rpc/pb"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_usecase "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase/mock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	defaultStore = "loja-1"
+	hmacSecret   = "rpc-secret"
+)
+
+// dial serves the use cases on an in-memory listener and returns a client
+// connection to it.
+func dial(t *testing.T, useCase *mock_usecase.MockProductionOrderUseCases) *grpc.ClientConn {
+	return dialWith(t, useCase, external.LimitsConfig{}, func() bool { return true })
+}
+
+// dialWith is dial with the server limited by limits and ready while ready
+// tells it is.
+func dialWith(t *testing.T, useCase *mock_usecase.MockProductionOrderUseCases, limits external.LimitsConfig, ready func() bool) *grpc.ClientConn {
+	authorizer, err := middlewares.NewAuthorizer(external.AuthConfig{Enabled: true, HMACSecret: hmacSecret})
+	assert.NoError(t, err)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(NewProductionOrderServer(useCase, defaultStore, 10*time.Millisecond), authorizer, limits, ready)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// as authenticates the calls made with the context as the role.
+func as(t *testing.T, role string) context.Context {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "rpc-" + role,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{role},
+	}).SignedString([]byte(hmacSecret))
+	assert.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestProductionOrderServer_SendOrderToProduction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+	client := pb.NewProductionOrderServiceClient(dial(t, useCase))
+
+	items := []entities.ProductionOrderItem{{Name: "X-Burger", Category: "LANCHE", Quantity: 2}}
+	useCase.EXPECT().SendOrderToProduction("loja-2", entities.ProductionOrder{
+		OrderId:  1,
+		Priority: entities.RUSH_PRIORITY,
+		Items:    items,
+	}).Return(&entities.ProductionOrder{
+		StoreId:  "loja-2",
+		OrderId:  1,
+		Status:   entities.RECEIVED_STATUS,
+		Priority: entities.RUSH_PRIORITY,
+		Items:    items,
+	}, nil)
+
+	order, err := client.SendOrderToProduction(as(t, middlewares.SERVICE_ROLE), &pb.SendOrderToProductionRequest{
+		StoreId:  "loja-2",
+		OrderId:  1,
+		Priority: entities.RUSH_PRIORITY,
+		Items:    []*pb.ProductionOrderItem{{Name: "X-Burger", Category: "LANCHE", Quantity: 2}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "loja-2", order.GetStoreId())
+	assert.Equal(t, entities.RECEIVED_STATUS, order.GetStatus())
+	assert.Len(t, order.GetItems(), 1)
+
+	_, err = client.SendOrderToProduction(as(t, middlewares.SERVICE_ROLE), &pb.SendOrderToProductionRequest{})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestProductionOrderServer_UpdateStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+	client := pb.NewProductionOrderServiceClient(dial(t, useCase))
+
+	actor := entities.Actor{Name: "rpc-kitchen", Station: "chapa"}
+	useCase.EXPECT().UpdateProductionOrderStatus(defaultStore, uint32(1), entities.IN_PREPARATION_STATUS, actor).
+		Return(&entities.ProductionOrder{StoreId: defaultStore, OrderId: 1, Status: entities.IN_PREPARATION_STATUS}, nil)
+	useCase.EXPECT().UpdateProductionOrderStatus(defaultStore, uint32(2), "QUEIMADO", actor).
+		Return(nil, &custom_errors.BadRequestError{Message: "invalid status"})
+	useCase.EXPECT().UpdateProductionOrderStatus(defaultStore, uint32(3), entities.IN_PREPARATION_STATUS, actor).
+		Return(nil, &custom_errors.DatabaseError{Message: "database unavailable"})
+
+	ctx := metadata.AppendToOutgoingContext(as(t, middlewares.KITCHEN_ROLE), "x-station", "chapa")
+
+	order, err := client.UpdateStatus(ctx, &pb.UpdateStatusRequest{OrderId: 1, Status: entities.IN_PREPARATION_STATUS})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.IN_PREPARATION_STATUS, order.GetStatus())
+
+	_, err = client.UpdateStatus(ctx, &pb.UpdateStatusRequest{OrderId: 2, Status: "QUEIMADO"})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.UpdateStatus(ctx, &pb.UpdateStatusRequest{OrderId: 3, Status: entities.IN_PREPARATION_STATUS})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestProductionOrderServer_GetOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+	client := pb.NewProductionOrderServiceClient(dial(t, useCase))
+
+	receivedAt := time.Date(2024, 10, 10, 12, 0, 0, 0, time.UTC)
+	useCase.EXPECT().GetProductionOrder(defaultStore, uint32(1)).Return(&entities.ProductionOrder{
+		StoreId: defaultStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		History: []entities.StatusTransition{{Status: entities.RECEIVED_STATUS, At: receivedAt}},
+	}, nil)
+	useCase.EXPECT().GetProductionOrder(defaultStore, uint32(2)).Return(nil, errors.New("mock error"))
+
+	order, err := client.GetOrder(as(t, middlewares.MANAGER_ROLE), &pb.GetOrderRequest{OrderId: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, receivedAt, order.GetHistory()[0].GetAt().AsTime())
+
+	_, err = client.GetOrder(as(t, middlewares.MANAGER_ROLE), &pb.GetOrderRequest{OrderId: 2})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestProductionOrderServer_WatchQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+	client := pb.NewProductionOrderServiceClient(dial(t, useCase))
+
+	queue := func(elapsed int64, statuses ...string) *entities.ProductionOrderQueue {
+		orders := []entities.ProductionOrder{}
+		for i, status := range statuses {
+			orders = append(orders, entities.ProductionOrder{
+				StoreId: defaultStore,
+				OrderId: uint32(i + 1),
+				Status:  status,
+				SLA:     &entities.OrderSLA{ElapsedSeconds: elapsed, TargetSeconds: 600},
+			})
+		}
+
+		return &entities.ProductionOrderQueue{Orders: orders}
+	}
+
+	gomock.InOrder(
+		useCase.EXPECT().GetProductionOrderQueue(defaultStore).Return(queue(1, entities.RECEIVED_STATUS), nil),
+		useCase.EXPECT().GetProductionOrderQueue(defaultStore).Return(queue(2, entities.RECEIVED_STATUS), nil),
+		useCase.EXPECT().GetProductionOrderQueue(defaultStore).Return(queue(3, entities.IN_PREPARATION_STATUS, entities.RECEIVED_STATUS), nil),
+		useCase.EXPECT().GetProductionOrderQueue(defaultStore).Return(queue(4, entities.IN_PREPARATION_STATUS, entities.RECEIVED_STATUS), nil).AnyTimes(),
+	)
+
+	ctx, cancel := context.WithCancel(as(t, middlewares.KITCHEN_ROLE))
+	defer cancel()
+
+	stream, err := client.WatchQueue(ctx, &pb.GetQueueRequest{})
+	assert.NoError(t, err)
+
+	first, err := stream.Recv()
+
+	assert.NoError(t, err)
+	assert.Len(t, first.GetOrders(), 1)
+
+	changed, err := stream.Recv()
+
+	assert.NoError(t, err)
+	assert.Len(t, changed.GetOrders(), 2)
+	assert.Equal(t, entities.IN_PREPARATION_STATUS, changed.GetOrders()[0].GetStatus())
+	assert.Equal(t, int64(3), changed.GetOrders()[0].GetSla().GetElapsedSeconds())
+}
+
+func TestProductionOrderServer_Auth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+	conn := dial(t, useCase)
+	client := pb.NewProductionOrderServiceClient(conn)
+
+	_, err := client.GetQueue(context.Background(), &pb.GetQueueRequest{})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.SendOrderToProduction(as(t, middlewares.KITCHEN_ROLE), &pb.SendOrderToProductionRequest{OrderId: 1})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	stream, err := client.WatchQueue(context.Background(), &pb.GetQueueRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	health, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{
+		Service: pb.ProductionOrderService_ServiceDesc.ServiceName,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, health.GetStatus())
+}
+
+func TestProductionOrderServer_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+	conn := dialWith(t, useCase, external.LimitsConfig{Rate: 1, Burst: 2, RequestTimeout: 20 * time.Millisecond}, func() bool { return true })
+	client := pb.NewProductionOrderServiceClient(conn)
+
+	useCase.EXPECT().GetProductionOrder(defaultStore, uint32(1)).Return(&entities.ProductionOrder{StoreId: defaultStore, OrderId: 1}, nil)
+	useCase.EXPECT().GetProductionOrder(defaultStore, uint32(2)).DoAndReturn(func(storeId string, orderId uint32) (*entities.ProductionOrder, error) {
+		time.Sleep(100 * time.Millisecond)
+		return &entities.ProductionOrder{StoreId: defaultStore, OrderId: 2}, nil
+	})
+
+	_, err := client.GetOrder(as(t, middlewares.MANAGER_ROLE), &pb.GetOrderRequest{OrderId: 1})
+
+	assert.NoError(t, err)
+
+	_, err = client.GetOrder(as(t, middlewares.MANAGER_ROLE), &pb.GetOrderRequest{OrderId: 2})
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	_, err = client.GetOrder(as(t, middlewares.MANAGER_ROLE), &pb.GetOrderRequest{OrderId: 3})
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	health, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, health.GetStatus())
+}
+
+func TestProductionOrderServer_HealthFollowsReadiness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	useCase := mock_usecase.NewMockProductionOrderUseCases(ctrl)
+
+	var ready atomic.Bool
+	conn := dialWith(t, useCase, external.LimitsConfig{}, ready.Load)
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+	request := &grpc_health_v1.HealthCheckRequest{Service: pb.ProductionOrderService_ServiceDesc.ServiceName}
+
+	health, err := healthClient.Check(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, health.GetStatus())
+
+	readinessInterval = 10 * time.Millisecond
+	t.Cleanup(func() { readinessInterval = time.Second })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watch, err := healthClient.Watch(ctx, request)
+	assert.NoError(t, err)
+
+	health, err = watch.Recv()
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, health.GetStatus())
+
+	ready.Store(true)
+	health, err = watch.Recv()
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, health.GetStatus())
+
+	health, err = healthClient.Check(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, health.GetStatus())
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/middlewares"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// readinessInterval is how often the health of watched services is checked.
+var readinessInterval = time.Second
+
+// NewServer builds the gRPC server of the production queue, limited like the
+// REST API by limits, with the standard health checking service, reporting the
+// production order service as serving while ready tells it is, and server
+// reflection for tools such as grpcurl.
+func NewServer(productionOrders *ProductionOrderServer, authorizer *middlewares.Authorizer, limits external.LimitsConfig, ready func() bool) *grpc.Server {
+	auth := authenticator{authorizer: authorizer}
+	limit := limiter{
+		rateLimiter: middlewares.NewRateLimiter(limits, authorizer),
+		timeout:     limits.RequestTimeout,
+	}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(limit.unary(), auth.unary()),
+		grpc.ChainStreamInterceptor(limit.stream(), auth.stream()),
+	)
+
+	pb.RegisterProductionOrderServiceServer(server, productionOrders)
+
+	healthServer := readinessHealth{Server: health.NewServer(), ready: ready}
+	healthServer.refresh()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
+
+// readinessHealth reports the server and the production order service as
+// serving only while ready tells the repositories are, the way GET /ready does.
+type readinessHealth struct {
+	*health.Server
+	ready func() bool
+}
+
+func (h readinessHealth) Check(ctx context.Context, request *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	h.refresh()
+
+	return h.Server.Check(ctx, request)
+}
+
+// Watch sends the status again whenever it changes, checking ready every
+// readinessInterval while the client watches.
+func (h readinessHealth) Watch(request *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	h.refresh()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(readinessInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				h.refresh()
+			}
+		}
+	}()
+
+	return h.Server.Watch(request, stream)
+}
+
+func (h readinessHealth) refresh() {
+	status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	if h.ready() {
+		status = grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	h.Server.SetServingStatus("", status)
+	h.Server.SetServingStatus(pb.ProductionOrderService_ServiceDesc.ServiceName, status)
+}
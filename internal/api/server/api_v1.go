@@ -0,0 +1,46 @@
+package server
+
+import "net/http"
+
+// @title Fastfood Order Production API
+// @version 1.0
+// @description Production queue of the Fastfood App orders.
+
+// @contact.name API Support
+// @contact.url http://www.swagger.io/support
+// @contact.email support@swagger.io
+
+// @license.name Apache 2.0
+// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+
+// @host localhost:8000
+// @BasePath /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+// v1Routes are the routes of the first version of the API, also served
+// without a version prefix while the legacy routes are enabled.
+func (api productionAPI) v1Routes() []route {
+	return []route{
+		{http.MethodGet, "/queue", api.orders.GetProductionOrderQueue, api.viewers},
+		{http.MethodGet, "/order/:orderId", api.orders.GetProductionOrder, api.viewers},
+		{http.MethodGet, "/orders", api.orders.ListProductionOrders, api.viewers},
+		{http.MethodGet, "/order/:orderId/audit", api.audit.GetProductionOrderAudit, api.managers},
+		{http.MethodPost, "/order/send", api.orders.SendOrderToProduction, api.services},
+		{http.MethodPut, "/order/:orderId/status", api.orders.UpdateProductionOrderStatus, api.kitchen},
+		{http.MethodPost, "/orders/status", api.orders.UpdateProductionOrderStatuses, api.kitchen},
+		{http.MethodPost, "/order/:orderId/cancel", api.orders.CancelProductionOrder, api.managers},
+		{http.MethodPut, "/order/:orderId/priority", api.orders.UpdateProductionOrderPriority, api.managers},
+		{http.MethodPut, "/order/:orderId/stations/:station/status", api.orders.UpdateStationTicketStatus, api.kitchen},
+		{http.MethodGet, "/stations/:station/queue", api.orders.GetStationQueue, api.viewers},
+		{http.MethodGet, "/reports/summary", api.reports.GetSummary, api.managers},
+		{http.MethodGet, "/export", api.exports.ExportProductionOrders, api.managers},
+		{http.MethodPost, "/webhooks", api.webhooks.Subscribe, api.managers},
+		{http.MethodGet, "/webhooks", api.webhooks.GetSubscriptions, api.managers},
+		{http.MethodDelete, "/webhooks/:subscriptionId", api.webhooks.Unsubscribe, api.managers},
+		{http.MethodPost, "/webhooks/:subscriptionId/enable", api.webhooks.EnableSubscription, api.managers},
+		{http.MethodGet, "/webhooks/:subscriptionId/deliveries", api.webhooks.GetDeliveries, api.managers},
+	}
+}
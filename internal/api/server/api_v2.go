@@ -0,0 +1,30 @@
+package server
+
+import "net/http"
+
+// @title Fastfood Order Production API
+// @version 2.0
+// @description Production queue of the Fastfood App orders, where the orders carry their items.
+
+// @contact.name API Support
+// @contact.url http://www.swagger.io/support
+// @contact.email support@swagger.io
+
+// @license.name Apache 2.0
+// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+
+// @host localhost:8000
+// @BasePath /v2
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+// v2Routes are the routes of the second version of the API: the ones of the
+// first version, but orders sent to production must carry their items.
+func (api productionAPI) v2Routes() []route {
+	return replaceRoutes(
+		api.v1Routes(),
+		route{http.MethodPost, "/order/send", api.orders.SendOrderToProductionV2, api.services},
+	)
+}
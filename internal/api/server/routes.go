@@ -0,0 +1,82 @@
+package server
+
+import (
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/handlers"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/middlewares"
+	"github.com/labstack/echo/v4"
+)
+
+// route is an operation of a version of the API, registered under the
+// production prefixes of the version.
+type route struct {
+	method     string
+	path       string
+	handler    echo.HandlerFunc
+	middleware echo.MiddlewareFunc
+}
+
+// apiVersion is a version of the API served under "/" + name, with the
+// Swagger docs generated for it under the same instance name. A deprecated
+// version tells its clients so on every response.
+type apiVersion struct {
+	name        string
+	deprecation *middlewares.Deprecation
+	routes      []route
+}
+
+// productionAPI holds the handlers and the role checks the routes of every
+// version are built from.
+type productionAPI struct {
+	orders   handlers.ProductionOrderHandler
+	reports  handlers.ProductionReportHandler
+	audit    handlers.ProductionAuditHandler
+	exports  handlers.ProductionExportHandler
+	webhooks handlers.WebhookHandler
+	kitchen  echo.MiddlewareFunc
+	viewers  echo.MiddlewareFunc
+	managers echo.MiddlewareFunc
+	services echo.MiddlewareFunc
+}
+
+// replaceRoutes returns the routes with the ones of the same method and path
+// replaced by the overrides, so that a new version only lists the operations
+// it changes. Overrides matching no route are added.
+func replaceRoutes(routes []route, overrides ...route) []route {
+	replaced := append([]route{}, routes...)
+	for _, override := range overrides {
+		found := false
+		for i, route := range replaced {
+			if route.method == override.method && route.path == override.path {
+				replaced[i] = override
+				found = true
+			}
+		}
+
+		if !found {
+			replaced = append(replaced, override)
+		}
+	}
+
+	return replaced
+}
+
+// registerRoutes registers the routes under the production prefixes of the
+// given version prefix, both for the default store and for a given store.
+func registerRoutes(app *echo.Echo, prefix string, routes []route, middleware ...echo.MiddlewareFunc) {
+	for _, production := range []string{"/production", "/stores/:storeId/production"} {
+		group := app.Group(prefix+production, middleware...)
+		for _, route := range routes {
+			group.Add(route.method, route.path, route.handler, route.middleware)
+		}
+	}
+}
+
+// versions are the versions of the API served side by side. Deprecating a
+// version is setting its deprecation, the successor being the version
+// replacing it, such as "/v2".
+func (api productionAPI) versions() []apiVersion {
+	return []apiVersion{
+		{name: "v1", routes: api.v1Routes()},
+		{name: "v2", routes: api.v2Routes()},
+	}
+}
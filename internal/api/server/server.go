@@ -2,17 +2,31 @@ package server
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-playground/validator"
 
 	_ "github.com/8soat-grupo35/fastfood-order-production/docs"
 	"github.com/8soat-grupo35/fastfood-order-production/external"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/api/handlers"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/middlewares"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/api/rpc"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/gateways"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/usecases"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -20,47 +34,320 @@ func Start() {
 	cfg := external.GetConfig()
 	fmt.Println(cfg)
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	fmt.Println(context.Background(), fmt.Sprintf("Starting a server at http://%s", cfg.ServerHost))
-	app := newApp(cfg)
-	app.Logger.Fatal(app.Start(cfg.ServerHost))
+	repositories := gateways.NewRepositories(cfg)
+	webhooks := NewWebhookDispatcher(cfg, repositories)
+	for i := 0; i < cfg.WebhookConfig.Workers; i++ {
+		go webhooks.Dispatch()
+	}
+	StartBackgroundJobs(ctx, cfg, repositories)
+
+	productionOrders := NewProductionOrderUseCase(cfg, repositories, webhooks)
+	app := NewApp(cfg, repositories, productionOrders)
+	if cfg.GRPCConfig.Enabled {
+		go serveGRPC(cfg, repositories, productionOrders)
+	}
+
+	server := &http.Server{
+		Addr:        cfg.ServerHost,
+		Handler:     middlewares.RequestTimeout(cfg.LimitsConfig.RequestTimeout, isStreamed)(app),
+		ReadTimeout: cfg.LimitsConfig.ReadTimeout,
+		IdleTimeout: cfg.LimitsConfig.IdleTimeout,
+	}
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.LimitsConfig.RequestTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	err := server.ListenAndServe()
+
+	if !errors.Is(err, http.ErrServerClosed) {
+		app.Logger.Fatal(err)
+	}
 }
 
-// @title Swagger Fastfood App API
-// @version 1.0
-// @description This is a sample API from Fastfood App.
-// @termsOfService http://swagger.io/terms/
+// StartBackgroundJobs starts the SLA check and, on a store node, the sync with
+// the central database, both running until ctx is done.
+func StartBackgroundJobs(ctx context.Context, cfg external.Config, repositories gateways.Repositories) {
+	go watchSLA(
+		ctx,
+		usecases.NewSLAMonitorUseCase(
+			repositories.ProductionOrders,
+			queuePolicy(cfg),
+			external.NewLogSLABreachPublisher(),
+		),
+		cfg.QueueConfig.SLACheckInterval,
+	)
 
-// @contact.name API Support
-// @contact.url http://www.swagger.io/support
-// @contact.email support@swagger.io
+	if repositories.Outbox != nil {
+		go watchSync(
+			ctx,
+			usecases.NewSyncUseCase(
+				repositories.Outbox,
+				repositories.Central.ProductionOrders,
+				repositories.Central.Audit,
+				cfg.SyncConfig.BatchSize,
+			),
+			repositories.Central.CheckTables,
+			cfg.SyncConfig.Interval,
+		)
+	}
+}
 
-// @license.name Apache 2.0
-// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+// legacyDeprecatedAt is when the routes without a version prefix were
+// deprecated in favour of the same routes under /v1.
+var legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
 
-// @host localhost:8000
-// @BasePath /v1
-func newApp(cfg external.Config) *echo.Echo {
-	database := external.ConectaDB(cfg)
+// NewApp builds the service on the given repositories, serving the production
+// orders through productionOrders. Start runs it on the repositories of the
+// configured database, while tests may run it in-process on repositories of
+// their own.
+func NewApp(cfg external.Config, repositories gateways.Repositories, productionOrders usecase.ProductionOrderUseCases) *echo.Echo {
 	app := echo.New()
 	app.Validator = &external.HandlerCustomValidator{
 		Validator: validator.New(),
 	}
-	app.GET("/swagger/*", echoSwagger.WrapHandler)
+	app.IPExtractor = middlewares.IPExtractor(cfg.LimitsConfig.TrustedProxies)
+	app.Use(middleware.RequestID())
+	app.Use(middlewares.SecureHeaders(cfg.HeadersConfig))
+	app.Use(middlewares.CORS(cfg.CORSConfig))
+	if cfg.LimitsConfig.BodyLimit != "" {
+		app.Use(middleware.BodyLimit(cfg.LimitsConfig.BodyLimit))
+	}
+	app.GET("/swagger/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName("v1")))
 	app.GET("/", func(echo echo.Context) error {
 		return echo.JSON(http.StatusOK, "Alive")
 	})
+	app.GET("/ready", func(echo echo.Context) error {
+		if !repositories.Ready() {
+			return echo.JSON(http.StatusServiceUnavailable, "Database unavailable")
+		}
 
-	productionOrderGateway := gateways.NewProductionOrderGateway(
-		external.NewDynamoAdapter(database),
+		return echo.JSON(http.StatusOK, "Ready")
+	})
+
+	productionOrderGateway := repositories.ProductionOrders
+	auditGateway := repositories.Audit
+	productionOrderHandler := handlers.NewProductionOrderHandler(productionOrders)
+	productionReportHandler := handlers.NewProductionReportHandler(
+		usecases.NewProductionReportUseCase(
+			productionOrderGateway,
+		),
 	)
-	productionOrderHandler := handlers.NewProductionOrderHandler(
-		usecases.NewProductionOrderUseCase(
+	productionAuditHandler := handlers.NewProductionAuditHandler(
+		usecases.NewProductionAuditUseCase(
+			auditGateway,
+		),
+	)
+	productionExportHandler := handlers.NewProductionExportHandler(
+		usecases.NewProductionExportUseCase(
 			productionOrderGateway,
 		),
 	)
-	app.GET("/production/queue", productionOrderHandler.GetProductionOrderQueue)
-	app.POST("/production/order/send", productionOrderHandler.SendOrderToProduction)
-	app.PUT("/production/order/:orderId/status", productionOrderHandler.UpdateProductionOrderStatus)
+	webhookHandler := handlers.NewWebhookHandler(
+		usecases.NewWebhookUseCase(
+			repositories.Webhooks,
+			webhookPolicy(cfg),
+		),
+	)
+	authorizer, err := middlewares.NewAuthorizer(cfg.AuthConfig)
+
+	if err != nil {
+		log.Panic(err.Error())
+	}
+
+	api := productionAPI{
+		orders:   productionOrderHandler,
+		reports:  productionReportHandler,
+		audit:    productionAuditHandler,
+		exports:  productionExportHandler,
+		webhooks: webhookHandler,
+		kitchen:  authorizer.RequireRoles(middlewares.KITCHEN_ROLE, middlewares.MANAGER_ROLE),
+		viewers:  authorizer.RequireRoles(middlewares.KITCHEN_ROLE, middlewares.MANAGER_ROLE, middlewares.SERVICE_ROLE),
+		managers: authorizer.RequireRoles(middlewares.MANAGER_ROLE),
+		services: authorizer.RequireRoles(middlewares.SERVICE_ROLE),
+	}
+	scope := []echo.MiddlewareFunc{
+		middlewares.RateLimit(cfg.LimitsConfig, authorizer),
+		authorizer.Authenticate(),
+		handlers.StoreScope(cfg.StoreConfig.DefaultStoreId),
+	}
+
+	// the metrics expose the command line and memory stats of the process
+	app.GET("/debug/vars", echo.WrapHandler(expvar.Handler()), middlewares.RateLimit(cfg.LimitsConfig, authorizer), authorizer.Authenticate(), api.managers)
+
+	for _, version := range api.versions() {
+		prefix := "/" + version.name
+		middleware := scope
+		if version.deprecation != nil {
+			deprecation := *version.deprecation
+			deprecation.Prefix = prefix
+			middleware = append([]echo.MiddlewareFunc{middlewares.Deprecated(deprecation)}, scope...)
+		}
+
+		registerRoutes(app, prefix, version.routes, middleware...)
+		app.GET("/swagger/"+version.name+"/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName(version.name)))
+	}
+
+	if cfg.APIConfig.LegacyRoutes {
+		legacy := middlewares.Deprecated(middlewares.Deprecation{
+			At:        legacyDeprecatedAt,
+			Sunset:    cfg.APIConfig.LegacySunset,
+			Successor: "/v1",
+		})
+		registerRoutes(app, "", api.v1Routes(), append([]echo.MiddlewareFunc{legacy}, scope...)...)
+	}
 
 	return app
 }
+
+// NewWebhookDispatcher builds the dispatcher posting the status changes to the
+// webhook subscriptions stored in the repositories. It posts nothing until
+// its workers are started.
+func NewWebhookDispatcher(cfg external.Config, repositories gateways.Repositories) usecase.WebhookDispatcher {
+	return usecases.NewWebhookDispatcher(
+		repositories.Webhooks,
+		external.NewHTTPWebhookSender(cfg.WebhookConfig.Timeout, webhookPolicy(cfg)),
+		webhookPolicy(cfg),
+		cfg.WebhookConfig.QueueSize,
+	)
+}
+
+// webhookPolicy is the policy both the subscriptions and the deliveries of the
+// webhooks follow.
+func webhookPolicy(cfg external.Config) entities.WebhookPolicy {
+	return entities.WebhookPolicy{
+		MaxAttempts:       cfg.WebhookConfig.MaxAttempts,
+		RetryBaseDelay:    cfg.WebhookConfig.RetryBaseDelay,
+		RetryMaxDelay:     cfg.WebhookConfig.RetryMaxDelay,
+		DisableAfter:      cfg.WebhookConfig.DisableAfter,
+		AllowInsecureURLs: cfg.WebhookConfig.AllowInsecureURLs,
+	}
+}
+
+// queuePolicy is the policy both the queues and the SLA check follow.
+func queuePolicy(cfg external.Config) entities.QueuePolicy {
+	return entities.QueuePolicy{
+		PriorityAging: cfg.QueueConfig.PriorityAging,
+		StatusTargets: cfg.QueueConfig.SLATargets,
+	}
+}
+
+// NewProductionOrderUseCase builds the production order use cases, publishing
+// the status changes to webhooks, that both the REST and the gRPC APIs serve.
+func NewProductionOrderUseCase(cfg external.Config, repositories gateways.Repositories, webhooks usecase.WebhookDispatcher) usecase.ProductionOrderUseCases {
+	return usecases.NewProductionOrderUseCase(
+		repositories.ProductionOrders,
+		repositories.Audit,
+		entities.NewStationRouter(
+			cfg.KitchenConfig.Stations,
+			cfg.KitchenConfig.DefaultStation,
+		),
+		queuePolicy(cfg),
+		usecases.NewHistoricalReadyTimeEstimator(
+			cfg.QueueConfig.KitchenCapacity,
+			cfg.QueueConfig.DefaultPreparation,
+		),
+		webhooks,
+	)
+}
+
+// serveGRPC serves the gRPC API on its own port until the process exits.
+func serveGRPC(cfg external.Config, repositories gateways.Repositories, productionOrders usecase.ProductionOrderUseCases) {
+	authorizer, err := middlewares.NewAuthorizer(cfg.AuthConfig)
+
+	if err != nil {
+		log.Panic(err.Error())
+	}
+
+	listener, err := net.Listen("tcp", cfg.GRPCConfig.Host)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(fmt.Sprintf("Starting a gRPC server at %s", cfg.GRPCConfig.Host))
+	server := rpc.NewServer(
+		rpc.NewProductionOrderServer(
+			productionOrders,
+			cfg.StoreConfig.DefaultStoreId,
+			cfg.GRPCConfig.WatchInterval,
+		),
+		authorizer,
+		cfg.LimitsConfig,
+		repositories.Ready,
+	)
+	log.Fatal(server.Serve(listener))
+}
+
+// isStreamed tells the requests whose responses are streamed, which must not be
+// cut by the request timeout.
+func isStreamed(request *http.Request) bool {
+	return strings.HasSuffix(request.URL.Path, "/export")
+}
+
+// watchSLA checks the queue for late orders on every interval until ctx is
+// done. A zero interval disables the check.
+func watchSLA(ctx context.Context, monitor usecase.SLAMonitorUseCases, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := monitor.CheckSLA()
+
+		if err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
+
+// watchSync replays the changes queued on the store node on the central
+// database every interval, until ctx is done. A sync stopped by the central
+// database being unreachable goes on from where it stopped on the next
+// interval. Nothing is synced until the tables of the central database pass
+// checkTables.
+func watchSync(ctx context.Context, sync usecase.SyncUseCases, checkTables func() error, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := checkTables()
+
+		if err != nil {
+			log.Printf("not syncing with the central database: %s", err.Error())
+			continue
+		}
+
+		report, err := sync.Sync()
+
+		if report.Synced > 0 || report.Rejected > 0 {
+			log.Printf("synced %d changes with the central database, rejected %d", report.Synced, report.Rejected)
+		}
+
+		if err != nil {
+			log.Println(err.Error())
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/gateways"
+)
+
+// Backfill runs the backfill subcommand, copying the orders of the dynamo
+// table keyed by order id only, written before the orders were scoped by
+// store, into the orders table keyed by store and order id:
+//
+//	fastfood-order-production [--config config.yaml] backfill --store loja-1
+//
+// The legacy orders have no store, so they are copied into the given one.
+// Orders already in the new table are left as they are, which lets the
+// subcommand run again after a failure.
+func Backfill(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	store := flags.String("store", "", "store of the legacy orders (default: the configured default store)")
+
+	err := flags.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	config := external.GetConfig()
+	if *store == "" {
+		*store = config.StoreConfig.DefaultStoreId
+	}
+
+	database := external.ConectaDB(config)
+
+	legacy := external.NewDynamoAdapter[entities.ProductionOrder](database)
+	legacy.SetTable(config.DynamoConfig.LegacyProductionOrderTable())
+
+	orders, err := legacy.GetAll()
+
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", config.DynamoConfig.LegacyProductionOrderTable(), err)
+	}
+
+	repository := gateways.NewProductionOrderGateway(
+		external.NewDynamoAdapter[entities.ProductionOrder](database),
+		config.DynamoConfig.ProductionOrderTable(),
+	)
+
+	copied := 0
+	for _, order := range orders {
+		if order.StoreId == "" {
+			order.StoreId = *store
+		}
+
+		existing, err := repository.GetByOrderId(order.StoreId, order.OrderId)
+
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			continue
+		}
+
+		_, err = repository.Create(order)
+
+		if err != nil {
+			return fmt.Errorf("copying order %d: %w", order.OrderId, err)
+		}
+
+		copied++
+	}
+
+	fmt.Fprintf(stdout, "%d of %d orders copied from %s to %s\n", copied, len(orders),
+		config.DynamoConfig.LegacyProductionOrderTable(), config.DynamoConfig.ProductionOrderTable())
+
+	return nil
+}
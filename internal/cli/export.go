@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/adapters/dto"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/gateways"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/presenters"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/usecases"
+)
+
+// Export runs the export subcommand, writing the production orders received in
+// a period to a file or to stdout:
+//
+//	fastfood-order-production [--config config.yaml] export --store loja-1 --from 2024-10-01 --to 2024-10-07 --format csv --output orders.csv
+func Export(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	store := flags.String("store", "", "store to export (default: the configured default store)")
+	from := flags.String("from", "", "start of the period, RFC3339 or YYYY-MM-DD (default: 24 hours before --to)")
+	to := flags.String("to", "", "end of the period, RFC3339 or YYYY-MM-DD, dates include the whole day (default: now)")
+	format := flags.String("format", presenters.CSV_EXPORT_FORMAT, "csv or ndjson")
+	output := flags.String("output", "-", "file to write, - for stdout")
+
+	err := flags.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	periodFrom, periodTo, err := dto.PeriodDto{From: *from, To: *to}.ToTimeRange(time.Now())
+
+	if err != nil {
+		return err
+	}
+
+	writer := stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+		writer = file
+	}
+
+	exporter, err := presenters.NewProductionOrderExporter(*format, writer)
+
+	if err != nil {
+		return err
+	}
+
+	config := external.GetConfig()
+	if *store == "" {
+		*store = config.StoreConfig.DefaultStoreId
+	}
+
+	exportUseCase := usecases.NewProductionExportUseCase(
+		gateways.NewRepositories(config).ProductionOrders,
+	)
+
+	err = exportUseCase.ExportProductionOrders(*store, periodFrom, periodTo, exporter.Write)
+
+	if err != nil {
+		return err
+	}
+
+	return exporter.Flush()
+}
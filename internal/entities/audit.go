@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	STATUS_CHANGED_AUDIT_ACTION = "STATUS_CHANGED"
+	TICKET_CHANGED_AUDIT_ACTION = "TICKET_STATUS_CHANGED"
+	ORDER_CANCELED_AUDIT_ACTION = "ORDER_CANCELED"
+	UNKNOWN_ACTOR               = "unknown"
+)
+
+// auditEntryTimeLayout has a fixed width so entry ids sort by time.
+const auditEntryTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// Actor identifies who asked for a change, from where and in which request.
+type Actor struct {
+	Name      string
+	Station   string
+	RequestId string
+}
+
+// AuditEntry records one status change of an order. Entries are only ever
+// appended.
+type AuditEntry struct {
+	OrderKey       string `dynamo:",hash" json:"-"`
+	EntryId        string `dynamo:",range" json:"-"`
+	At             time.Time
+	StoreId        string
+	OrderId        uint32
+	Action         string
+	Actor          string
+	Station        string `dynamo:",omitempty"`
+	PreviousStatus string
+	NewStatus      string
+	RequestId      string `dynamo:",omitempty"`
+}
+
+// NewAuditEntry describes a change of the order from previousStatus to
+// newStatus made by actor at the given instant.
+func NewAuditEntry(order ProductionOrder, action string, previousStatus string, newStatus string, actor Actor, at time.Time) AuditEntry {
+	name := actor.Name
+	if name == "" {
+		name = UNKNOWN_ACTOR
+	}
+
+	return AuditEntry{
+		OrderKey:       AuditOrderKey(order.StoreId, order.OrderId),
+		EntryId:        fmt.Sprintf("%s#%s", at.UTC().Format(auditEntryTimeLayout), action),
+		At:             at,
+		StoreId:        order.StoreId,
+		OrderId:        order.OrderId,
+		Action:         action,
+		Actor:          name,
+		Station:        actor.Station,
+		PreviousStatus: previousStatus,
+		NewStatus:      newStatus,
+		RequestId:      actor.RequestId,
+	}
+}
+
+// AuditOrderKey groups the audit entries of an order of a store.
+func AuditOrderKey(storeId string, orderId uint32) string {
+	return fmt.Sprintf("%s#%d", storeId, orderId)
+}
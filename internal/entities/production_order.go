@@ -2,6 +2,8 @@ package entities
 
 import (
 	"fmt"
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -10,16 +12,61 @@ const (
 	IN_PREPARATION_STATUS = "EM_PREPARACAO"
 	DONE_STATUS           = "PRONTO"
 	FINISHED_STATUS       = "FINALIZADO"
+	CANCELED_STATUS       = "CANCELADO"
+)
+
+// OPEN_ORDERS_INDEX is the index of the dynamo table holding only the orders
+// still in production, keyed by OpenStoreId.
+const OPEN_ORDERS_INDEX = "OpenOrders"
+
+const (
+	NORMAL_PRIORITY = "NORMAL"
+	HIGH_PRIORITY   = "ALTA"
+	RUSH_PRIORITY   = "URGENTE"
 )
 
 type ProductionOrder struct {
-	OrderId uint32 `dynamo:"ID,hash"`
-	Status  string
+	StoreId   string `dynamo:"StoreID,hash"`
+	OrderId   uint32 `dynamo:"ID,range" index:"OpenOrders,range"`
+	Status    string
+	Priority  string                `dynamo:",omitempty"`
+	CreatedAt time.Time             `dynamo:",omitempty"`
+	Items     []ProductionOrderItem `dynamo:",omitempty"`
+	Tickets   []StationTicket       `dynamo:",omitempty"`
+	History   []StatusTransition    `dynamo:",omitempty"`
+	SLA       *OrderSLA             `dynamo:"-" json:",omitempty"`
+
+	EstimatedReadyAt *time.Time `dynamo:"-" json:",omitempty"`
+
+	// OpenStoreId repeats StoreId while the order is open and is empty once
+	// it is closed, so the OpenOrders index of the dynamo table only holds the
+	// orders still in production.
+	OpenStoreId string `dynamo:",omitempty" index:"OpenOrders,hash" json:"-"`
+
+	// Version is the revision of the order read from a versioned repository,
+	// which rejects updates made over an older revision.
+	Version int64 `dynamo:",omitempty" json:"-"`
+}
+
+// StatusTransition records when an order entered a status.
+type StatusTransition struct {
+	Status string
+	At     time.Time
+}
+
+type ProductionOrderItem struct {
+	Name     string
+	Category string
+	Quantity uint32
 }
 
 func (o *ProductionOrder) Validate() error {
 	return validation.ValidateStruct(
 		o,
+		validation.Field(
+			&o.StoreId,
+			validation.Required,
+		),
 		validation.Field(
 			&o.OrderId,
 			validation.Required,
@@ -32,15 +79,183 @@ func (o *ProductionOrder) Validate() error {
 				IN_PREPARATION_STATUS,
 				DONE_STATUS,
 				FINISHED_STATUS,
+				CANCELED_STATUS,
 			).Error(
 				fmt.Sprintf(
-					"must be between %s, %s, %s or %s",
+					"must be between %s, %s, %s, %s or %s",
 					RECEIVED_STATUS,
 					IN_PREPARATION_STATUS,
 					DONE_STATUS,
 					FINISHED_STATUS,
+					CANCELED_STATUS,
 				),
 			),
 		),
+		validation.Field(
+			&o.Priority,
+			validation.In(
+				NORMAL_PRIORITY,
+				HIGH_PRIORITY,
+				RUSH_PRIORITY,
+			).Error(
+				fmt.Sprintf(
+					"must be between %s, %s or %s",
+					NORMAL_PRIORITY,
+					HIGH_PRIORITY,
+					RUSH_PRIORITY,
+				),
+			),
+		),
+		validation.Field(
+			&o.Items,
+		),
+		validation.Field(
+			&o.Tickets,
+		),
 	)
 }
+
+func (i ProductionOrderItem) Validate() error {
+	return validation.ValidateStruct(
+		&i,
+		validation.Field(
+			&i.Name,
+			validation.Required,
+		),
+		validation.Field(
+			&i.Category,
+			validation.Required,
+		),
+		validation.Field(
+			&i.Quantity,
+			validation.Required,
+		),
+	)
+}
+
+const maxPriorityRank = 2
+
+// PriorityRank orders priorities from NORMAL (0) to URGENTE (2). Orders stored
+// before priorities existed have none and rank as NORMAL.
+func (o *ProductionOrder) PriorityRank() int {
+	switch o.Priority {
+	case RUSH_PRIORITY:
+		return maxPriorityRank
+	case HIGH_PRIORITY:
+		return 1
+	}
+
+	return 0
+}
+
+// IsClosed reports whether the order left the production queue, either
+// delivered or canceled.
+func (o *ProductionOrder) IsClosed() bool {
+	return o.Status == FINISHED_STATUS || o.Status == CANCELED_STATUS
+}
+
+// CanChangeStatus tells whether an order may move from one status to another.
+// A closed order never leaves its status.
+func CanChangeStatus(from string, to string) bool {
+	if from == to {
+		return true
+	}
+
+	return from != FINISHED_STATUS && from != CANCELED_STATUS
+}
+
+// ChangeStatus moves the order to a new status, recording the transition in its
+// history. Setting the status it already has is a no-op.
+func (o *ProductionOrder) ChangeStatus(status string, at time.Time) {
+	if o.Status == status {
+		return
+	}
+
+	o.Status = status
+	o.History = append(o.History, StatusTransition{
+		Status: status,
+		At:     at,
+	})
+}
+
+// ReceivedAt returns when the order was sent to production.
+func (o *ProductionOrder) ReceivedAt() time.Time {
+	if !o.CreatedAt.IsZero() || len(o.History) == 0 {
+		return o.CreatedAt
+	}
+
+	return o.History[0].At
+}
+
+// StatusSince returns when the order entered its current status. Orders
+// persisted before the history was kept fall back to their creation time.
+func (o *ProductionOrder) StatusSince() time.Time {
+	for i := len(o.History) - 1; i >= 0; i-- {
+		if o.History[i].Status == o.Status {
+			return o.History[i].At
+		}
+	}
+
+	return o.CreatedAt
+}
+
+// HasPendingTickets reports whether any station is still working on the order.
+func (o *ProductionOrder) HasPendingTickets() bool {
+	for _, ticket := range o.Tickets {
+		if ticket.Status != DONE_STATUS {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TicketForStation returns the ticket routed to the given station, or nil if the
+// order has nothing to be prepared there.
+func (o *ProductionOrder) TicketForStation(station string) *StationTicket {
+	for i := range o.Tickets {
+		if o.Tickets[i].Station == station {
+			return &o.Tickets[i]
+		}
+	}
+
+	return nil
+}
+
+// UpdateTicketStatus moves a station ticket to a new status and derives the order
+// status from its tickets: the order enters preparation as soon as any station
+// starts on it and is only PRONTO once every station has finished. The tickets
+// of a closed order no longer change, so a FINALIZADO order never goes back to
+// PRONTO.
+func (o *ProductionOrder) UpdateTicketStatus(station string, status string, at time.Time) error {
+	if o.IsClosed() {
+		return fmt.Errorf("order %d is %s, its tickets cant change", o.OrderId, o.Status)
+	}
+
+	ticket := o.TicketForStation(station)
+
+	if ticket == nil {
+		return fmt.Errorf("order %d has no ticket for station %s", o.OrderId, station)
+	}
+
+	ticket.Status = status
+
+	if err := ticket.Validate(); err != nil {
+		return err
+	}
+
+	if !o.HasPendingTickets() {
+		o.ChangeStatus(DONE_STATUS, at)
+		return nil
+	}
+
+	if o.Status == RECEIVED_STATUS && status != RECEIVED_STATUS {
+		o.ChangeStatus(IN_PREPARATION_STATUS, at)
+	}
+
+	if o.Status == DONE_STATUS {
+		o.ChangeStatus(IN_PREPARATION_STATUS, at)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	ORDER_ID_ASCENDING_SORT  = "order_id"
+	ORDER_ID_DESCENDING_SORT = "-order_id"
+)
+
+// ProductionOrderFilter selects orders of a store by status, priority and the
+// time they were received. Empty lists and zero times match every order; From
+// is inclusive and To exclusive.
+type ProductionOrderFilter struct {
+	Statuses   []string
+	Priorities []string
+	From       time.Time
+	To         time.Time
+}
+
+// ProductionOrderPage is a page of a listing. NextCursor reads the next page
+// and is empty on the last one.
+type ProductionOrderPage struct {
+	Orders     []ProductionOrder
+	NextCursor string
+}
+
+func (f ProductionOrderFilter) Validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return errors.New("from must be before to")
+	}
+
+	return validation.ValidateStruct(
+		&f,
+		validation.Field(
+			&f.Statuses,
+			validation.Each(validation.In(
+				RECEIVED_STATUS,
+				IN_PREPARATION_STATUS,
+				DONE_STATUS,
+				FINISHED_STATUS,
+				CANCELED_STATUS,
+			).Error(
+				fmt.Sprintf(
+					"must be between %s, %s, %s, %s or %s",
+					RECEIVED_STATUS,
+					IN_PREPARATION_STATUS,
+					DONE_STATUS,
+					FINISHED_STATUS,
+					CANCELED_STATUS,
+				),
+			)),
+		),
+		validation.Field(
+			&f.Priorities,
+			validation.Each(validation.In(
+				NORMAL_PRIORITY,
+				HIGH_PRIORITY,
+				RUSH_PRIORITY,
+			).Error(
+				fmt.Sprintf(
+					"must be between %s, %s or %s",
+					NORMAL_PRIORITY,
+					HIGH_PRIORITY,
+					RUSH_PRIORITY,
+				),
+			)),
+		),
+	)
+}
+
+// Matches tells whether the order is selected by the filter.
+func (f ProductionOrderFilter) Matches(order ProductionOrder) bool {
+	if len(f.Statuses) > 0 && !contains(f.Statuses, order.Status) {
+		return false
+	}
+
+	if len(f.Priorities) > 0 && !contains(f.Priorities, order.Priority) {
+		return false
+	}
+
+	receivedAt := order.ReceivedAt()
+
+	if !f.From.IsZero() && receivedAt.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && !receivedAt.Before(f.To) {
+		return false
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,16 +2,28 @@ package entities
 
 import (
 	"sort"
+	"time"
 )
 
 type ProductionOrderQueue struct {
 	Orders []ProductionOrder
+	Policy QueuePolicy
+}
+
+// QueuePolicy holds the tunables used to rank and track the production queue.
+// PriorityAging is how long an order waits before being bumped one priority
+// level, so NORMAL orders are not starved by a steady flow of urgent ones. Zero
+// disables aging. StatusTargets is the longest an order should stay in each
+// status before being flagged as late.
+type QueuePolicy struct {
+	PriorityAging time.Duration
+	StatusTargets map[string]time.Duration
 }
 
 func (p *ProductionOrderQueue) RemoveFinishedOrders() {
 	orders := []ProductionOrder{}
 	for _, order := range p.Orders {
-		if order.Status != FINISHED_STATUS {
+		if !order.IsClosed() {
 			orders = append(orders, order)
 		}
 	}
@@ -19,29 +31,88 @@ func (p *ProductionOrderQueue) RemoveFinishedOrders() {
 	p.Orders = orders
 }
 
+// KeepStation narrows the queue to the orders that still have work pending on
+// the given station, leaving only that station's ticket on each order.
+func (p *ProductionOrderQueue) KeepStation(station string) {
+	orders := []ProductionOrder{}
+	for _, order := range p.Orders {
+		ticket := order.TicketForStation(station)
+		if ticket == nil || ticket.Status == DONE_STATUS || order.IsClosed() {
+			continue
+		}
+
+		order.Tickets = []StationTicket{*ticket}
+		orders = append(orders, order)
+	}
+
+	p.Orders = orders
+}
+
+// TrackSLA fills the SLA of every order in the queue at the given instant.
+func (p *ProductionOrderQueue) TrackSLA(now time.Time) {
+	for i := range p.Orders {
+		sla := p.Policy.EvaluateSLA(p.Orders[i], now)
+		p.Orders[i].SLA = &sla
+	}
+}
+
+// SetEstimatedReadyTimes attaches the estimated ready time of each order in the
+// queue, keyed by order id.
+func (p *ProductionOrderQueue) SetEstimatedReadyTimes(estimates map[uint32]time.Time) {
+	for i := range p.Orders {
+		if readyAt, ok := estimates[p.Orders[i].OrderId]; ok {
+			p.Orders[i].EstimatedReadyAt = &readyAt
+		}
+	}
+}
+
 func (p *ProductionOrderQueue) Sort() {
-	sort.Slice(p.Orders, func(i, j int) bool {
-		switch p.Orders[i].Status {
-		case DONE_STATUS:
-			return true
-		case RECEIVED_STATUS:
-			if p.Orders[j].Status == DONE_STATUS {
-				return false
-			}
-
-			if p.Orders[j].Status == IN_PREPARATION_STATUS {
-				return false
-			}
-
-			return true
-		case IN_PREPARATION_STATUS:
-			if p.Orders[j].Status == DONE_STATUS {
-				return false
-			}
-
-			return true
+	p.SortAt(time.Now())
+}
+
+// SortAt ranks the queue by status (PRONTO, EM_PREPARACAO, RECEBIDO), then by
+// priority aged up to the given instant, then by arrival.
+func (p *ProductionOrderQueue) SortAt(now time.Time) {
+	sort.SliceStable(p.Orders, func(i, j int) bool {
+		statusI, statusJ := statusRank(p.Orders[i].Status), statusRank(p.Orders[j].Status)
+		if statusI != statusJ {
+			return statusI < statusJ
+		}
+
+		priorityI, priorityJ := p.agedPriority(p.Orders[i], now), p.agedPriority(p.Orders[j], now)
+		if priorityI != priorityJ {
+			return priorityI > priorityJ
 		}
 
-		return true
+		return p.Orders[i].CreatedAt.Before(p.Orders[j].CreatedAt)
 	})
 }
+
+func (p *ProductionOrderQueue) agedPriority(order ProductionOrder, now time.Time) int {
+	rank := order.PriorityRank()
+
+	if p.Policy.PriorityAging <= 0 || order.CreatedAt.IsZero() || !now.After(order.CreatedAt) {
+		return rank
+	}
+
+	rank += int(now.Sub(order.CreatedAt) / p.Policy.PriorityAging)
+
+	if rank > maxPriorityRank {
+		return maxPriorityRank
+	}
+
+	return rank
+}
+
+func statusRank(status string) int {
+	switch status {
+	case DONE_STATUS:
+		return 0
+	case IN_PREPARATION_STATUS:
+		return 1
+	case RECEIVED_STATUS:
+		return 2
+	}
+
+	return 3
+}
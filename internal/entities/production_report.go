@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// ProductionReport aggregates the orders received in a period.
+type ProductionReport struct {
+	From time.Time
+	To   time.Time
+
+	TotalOrders      int
+	CountsByStatus   map[string]int
+	ThroughputByHour []HourlyThroughput
+	TimeInStatus     map[string]StatusDurationStats
+	CancellationRate float64
+}
+
+// HourlyThroughput counts the orders that became PRONTO within an hour.
+type HourlyThroughput struct {
+	Hour  time.Time
+	Count int
+}
+
+// StatusDurationStats summarizes how long orders stayed in a status, in seconds.
+type StatusDurationStats struct {
+	Count          int
+	AverageSeconds float64
+	P50Seconds     float64
+	P90Seconds     float64
+	P95Seconds     float64
+}
+
+// NewProductionReport builds the report of the orders received between from
+// (inclusive) and to (exclusive), using their transition history.
+func NewProductionReport(orders []ProductionOrder, from time.Time, to time.Time) ProductionReport {
+	report := ProductionReport{
+		From:           from,
+		To:             to,
+		CountsByStatus: map[string]int{},
+		TimeInStatus:   map[string]StatusDurationStats{},
+	}
+
+	throughput := map[time.Time]int{}
+	durations := map[string][]time.Duration{}
+
+	for _, order := range orders {
+		receivedAt := order.ReceivedAt()
+		if receivedAt.Before(from) || !receivedAt.Before(to) {
+			continue
+		}
+
+		report.TotalOrders++
+		report.CountsByStatus[order.Status]++
+
+		for i, transition := range order.History {
+			if transition.Status == DONE_STATUS {
+				throughput[transition.At.UTC().Truncate(time.Hour)]++
+			}
+
+			if i+1 < len(order.History) {
+				durations[transition.Status] = append(durations[transition.Status], order.History[i+1].At.Sub(transition.At))
+			}
+		}
+	}
+
+	for hour, count := range throughput {
+		report.ThroughputByHour = append(report.ThroughputByHour, HourlyThroughput{
+			Hour:  hour,
+			Count: count,
+		})
+	}
+
+	sort.Slice(report.ThroughputByHour, func(i, j int) bool {
+		return report.ThroughputByHour[i].Hour.Before(report.ThroughputByHour[j].Hour)
+	})
+
+	for status, statusDurations := range durations {
+		report.TimeInStatus[status] = newStatusDurationStats(statusDurations)
+	}
+
+	if report.TotalOrders > 0 {
+		report.CancellationRate = float64(report.CountsByStatus[CANCELED_STATUS]) / float64(report.TotalOrders)
+	}
+
+	return report
+}
+
+func newStatusDurationStats(durations []time.Duration) StatusDurationStats {
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+
+	return StatusDurationStats{
+		Count:          len(durations),
+		AverageSeconds: (total / time.Duration(len(durations))).Seconds(),
+		P50Seconds:     percentile(durations, 0.50).Seconds(),
+		P90Seconds:     percentile(durations, 0.90).Seconds(),
+		P95Seconds:     percentile(durations, 0.95).Seconds(),
+	}
+}
+
+// percentile uses the nearest-rank method over durations sorted ascending.
+func percentile(durations []time.Duration, rank float64) time.Duration {
+	index := int(math.Ceil(rank*float64(len(durations)))) - 1
+	if index < 0 {
+		index = 0
+	}
+
+	return durations[index]
+}
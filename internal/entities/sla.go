@@ -0,0 +1,62 @@
+package entities
+
+import "time"
+
+// OrderSLA tells the kitchen how long an order has been in its current status
+// and whether it went past the target for that status.
+type OrderSLA struct {
+	ElapsedSeconds int64
+	TargetSeconds  int64
+	Late           bool
+}
+
+// SLABreach is raised once per order and status when the order stays in that
+// status longer than its target.
+type SLABreach struct {
+	StoreId string
+	OrderId uint32
+	Status  string
+	Since   time.Time
+	Elapsed time.Duration
+	Target  time.Duration
+}
+
+// EvaluateSLA measures the order against the target duration configured for its
+// status. Statuses without a target are never late.
+func (q QueuePolicy) EvaluateSLA(order ProductionOrder, now time.Time) OrderSLA {
+	since := order.StatusSince()
+
+	if since.IsZero() || now.Before(since) {
+		return OrderSLA{}
+	}
+
+	elapsed := now.Sub(since)
+	target := q.StatusTargets[order.Status]
+
+	return OrderSLA{
+		ElapsedSeconds: int64(elapsed / time.Second),
+		TargetSeconds:  int64(target / time.Second),
+		Late:           target > 0 && elapsed > target,
+	}
+}
+
+// Breach returns the SLA breach of the order at the given instant, or nil if
+// the order is on time.
+func (q QueuePolicy) Breach(order ProductionOrder, now time.Time) *SLABreach {
+	sla := q.EvaluateSLA(order, now)
+
+	if !sla.Late {
+		return nil
+	}
+
+	since := order.StatusSince()
+
+	return &SLABreach{
+		StoreId: order.StoreId,
+		OrderId: order.OrderId,
+		Status:  order.Status,
+		Since:   since,
+		Elapsed: now.Sub(since),
+		Target:  q.StatusTargets[order.Status],
+	}
+}
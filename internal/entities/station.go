@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type StationTicket struct {
+	Station string
+	Status  string
+	Items   []ProductionOrderItem
+}
+
+func (t StationTicket) Validate() error {
+	return validation.ValidateStruct(
+		&t,
+		validation.Field(
+			&t.Station,
+			validation.Required,
+		),
+		validation.Field(
+			&t.Status,
+			validation.Required,
+			validation.In(
+				RECEIVED_STATUS,
+				IN_PREPARATION_STATUS,
+				DONE_STATUS,
+			).Error(
+				fmt.Sprintf(
+					"must be between %s, %s or %s",
+					RECEIVED_STATUS,
+					IN_PREPARATION_STATUS,
+					DONE_STATUS,
+				),
+			),
+		),
+	)
+}
+
+// StationRouter splits the items of an order between the kitchen stations.
+// Rules map an item category to a station; categories without a rule go to
+// DefaultStation.
+type StationRouter struct {
+	Rules          map[string]string
+	DefaultStation string
+}
+
+func NewStationRouter(rules map[string]string, defaultStation string) StationRouter {
+	normalizedRules := map[string]string{}
+	for category, station := range rules {
+		normalizedRules[strings.ToUpper(category)] = station
+	}
+
+	return StationRouter{
+		Rules:          normalizedRules,
+		DefaultStation: defaultStation,
+	}
+}
+
+func (r StationRouter) StationFor(category string) string {
+	if station, ok := r.Rules[strings.ToUpper(category)]; ok {
+		return station
+	}
+
+	return r.DefaultStation
+}
+
+// Route builds one ticket per station, keeping the stations in the order their
+// first item appears on the order.
+func (r StationRouter) Route(items []ProductionOrderItem) []StationTicket {
+	tickets := []StationTicket{}
+	ticketIndex := map[string]int{}
+
+	for _, item := range items {
+		station := r.StationFor(item.Category)
+
+		index, ok := ticketIndex[station]
+		if !ok {
+			index = len(tickets)
+			ticketIndex[station] = index
+			tickets = append(tickets, StationTicket{
+				Station: station,
+				Status:  RECEIVED_STATUS,
+			})
+		}
+
+		tickets[index].Items = append(tickets[index].Items, item)
+	}
+
+	if len(tickets) == 0 {
+		return nil
+	}
+
+	return tickets
+}
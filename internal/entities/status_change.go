@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatusChange asks for an order to move to a status, as one of the changes of
+// a bulk status update.
+type StatusChange struct {
+	OrderId uint32
+	Status  string
+}
+
+// StatusChangeResult tells what became of a change of a bulk status update:
+// the order as stored once the change was applied, or why it was not.
+type StatusChangeResult struct {
+	OrderId uint32
+	Status  string
+	Applied bool
+	Order   *ProductionOrder `json:",omitempty"`
+	Error   string           `json:",omitempty"`
+}
+
+// StatusChangeEvent tells that an order moved from a status to another. It is
+// the payload posted to the webhook subscriptions of the store.
+type StatusChangeEvent struct {
+	EventId        string
+	Event          string
+	StoreId        string
+	OrderId        uint32
+	PreviousStatus string
+	Status         string
+	Priority       string
+	OccurredAt     time.Time
+}
+
+// NewStatusChangeEvent describes the change of the order from previousStatus to
+// its current status at the given instant. The event id is the same every time
+// the change is described, so subscribers can drop the events delivered twice.
+func NewStatusChangeEvent(order ProductionOrder, previousStatus string, at time.Time) StatusChangeEvent {
+	return StatusChangeEvent{
+		EventId:        fmt.Sprintf("%s#%s", AuditOrderKey(order.StoreId, order.OrderId), at.UTC().Format(auditEntryTimeLayout)),
+		Event:          StatusEvent(order.Status),
+		StoreId:        order.StoreId,
+		OrderId:        order.OrderId,
+		PreviousStatus: previousStatus,
+		Status:         order.Status,
+		Priority:       order.Priority,
+		OccurredAt:     at,
+	}
+}
@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	ORDER_SYNC_CHANGE = "ORDER"
+	AUDIT_SYNC_CHANGE = "AUDIT"
+)
+
+// SyncChange is a change made on a store node, queued until it reaches the
+// central database. Order changes carry the whole order as it was stored, so
+// replaying a change again is harmless.
+type SyncChange struct {
+	Id       int64
+	Kind     string
+	QueuedAt time.Time
+	Order    *ProductionOrder `json:",omitempty"`
+	Audit    *AuditEntry      `json:",omitempty"`
+}
+
+// SyncReport counts what a sync did with the queued changes.
+type SyncReport struct {
+	Synced   int
+	Rejected int
+}
+
+// MergeProductionOrder reconciles an order changed on a store node with its copy
+// on the central database. The status histories are merged in the order of
+// their timestamps and the latest transition sets the status, so the change
+// made last wins whichever side is synced first. The priority and the tickets
+// come from the copy holding the latest transition. A merged history moving the
+// order out of a closed status is rejected.
+func MergeProductionOrder(central ProductionOrder, local ProductionOrder) (ProductionOrder, error) {
+	merged := central
+	if !lastTransitionAt(local).Before(lastTransitionAt(central)) {
+		merged.Priority = local.Priority
+		merged.Tickets = local.Tickets
+	}
+
+	if merged.CreatedAt.IsZero() {
+		merged.CreatedAt = local.CreatedAt
+	}
+
+	if len(merged.Items) == 0 {
+		merged.Items = local.Items
+	}
+
+	history := append([]StatusTransition{}, central.History...)
+	for _, transition := range local.History {
+		if !hasTransition(central.History, transition) {
+			history = append(history, transition)
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].At.Before(history[j].At)
+	})
+
+	merged.History = make([]StatusTransition, 0, len(history))
+	for _, transition := range history {
+		if len(merged.History) == 0 {
+			merged.History = append(merged.History, transition)
+			continue
+		}
+
+		previous := merged.History[len(merged.History)-1]
+		if previous.Status == transition.Status {
+			continue
+		}
+
+		if !CanChangeStatus(previous.Status, transition.Status) {
+			return ProductionOrder{}, fmt.Errorf(
+				"order %d of store %s cant move from %s to %s at %s",
+				local.OrderId, local.StoreId, previous.Status, transition.Status, transition.At.UTC().Format(time.RFC3339),
+			)
+		}
+
+		merged.History = append(merged.History, transition)
+	}
+
+	if len(merged.History) > 0 {
+		merged.Status = merged.History[len(merged.History)-1].Status
+	} else if merged.Status == "" {
+		merged.Status = local.Status
+	}
+
+	return merged, nil
+}
+
+func lastTransitionAt(order ProductionOrder) time.Time {
+	if len(order.History) == 0 {
+		return time.Time{}
+	}
+
+	return order.History[len(order.History)-1].At
+}
+
+func hasTransition(history []StatusTransition, transition StatusTransition) bool {
+	for _, recorded := range history {
+		if recorded.Status == transition.Status && recorded.At.Equal(transition.At) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,212 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	ORDER_STATUS_CHANGED_EVENT = "order.status_changed"
+	ORDER_RECEIVED_EVENT       = "order.received"
+	ORDER_IN_PREPARATION_EVENT = "order.in_preparation"
+	ORDER_READY_EVENT          = "order.ready"
+	ORDER_FINISHED_EVENT       = "order.finished"
+	ORDER_CANCELED_EVENT       = "order.canceled"
+)
+
+// statusEvents are the events raised when an order enters each status.
+var statusEvents = map[string]string{
+	RECEIVED_STATUS:       ORDER_RECEIVED_EVENT,
+	IN_PREPARATION_STATUS: ORDER_IN_PREPARATION_EVENT,
+	DONE_STATUS:           ORDER_READY_EVENT,
+	FINISHED_STATUS:       ORDER_FINISHED_EVENT,
+	CANCELED_STATUS:       ORDER_CANCELED_EVENT,
+}
+
+// StatusEvent returns the event raised when an order enters the status.
+func StatusEvent(status string) string {
+	if event, ok := statusEvents[status]; ok {
+		return event
+	}
+
+	return ORDER_STATUS_CHANGED_EVENT
+}
+
+// WebhookSubscription asks for the events of a store to be posted to URL,
+// signed with Secret. A subscription whose deliveries keep failing is disabled
+// until it is enabled again.
+type WebhookSubscription struct {
+	StoreId             string `dynamo:",hash"`
+	SubscriptionId      string `dynamo:",range"`
+	URL                 string
+	Events              []string
+	Secret              string `json:"-"`
+	Enabled             bool
+	ConsecutiveFailures int
+	DisabledAt          *time.Time `dynamo:",omitempty" json:",omitempty"`
+	CreatedAt           time.Time
+}
+
+// Validate checks the subscription, and its URL against the policy.
+func (s WebhookSubscription) Validate(policy WebhookPolicy) error {
+	return validation.ValidateStruct(
+		&s,
+		validation.Field(
+			&s.URL,
+			validation.Required,
+			validation.By(policy.callbackURL),
+		),
+		validation.Field(
+			&s.Events,
+			validation.Required,
+			validation.Each(validation.In(
+				ORDER_STATUS_CHANGED_EVENT,
+				ORDER_RECEIVED_EVENT,
+				ORDER_IN_PREPARATION_EVENT,
+				ORDER_READY_EVENT,
+				ORDER_FINISHED_EVENT,
+				ORDER_CANCELED_EVENT,
+			).Error(
+				fmt.Sprintf(
+					"must be between %s, %s, %s, %s, %s or %s",
+					ORDER_STATUS_CHANGED_EVENT,
+					ORDER_RECEIVED_EVENT,
+					ORDER_IN_PREPARATION_EVENT,
+					ORDER_READY_EVENT,
+					ORDER_FINISHED_EVENT,
+					ORDER_CANCELED_EVENT,
+				),
+			)),
+		),
+		validation.Field(
+			&s.Secret,
+			validation.Required,
+			validation.Length(16, 256),
+		),
+	)
+}
+
+// Matches tells whether the event is posted to the subscription: it must be
+// enabled and subscribed to the event or to every status change.
+func (s WebhookSubscription) Matches(event StatusChangeEvent) bool {
+	return s.Enabled && (contains(s.Events, ORDER_STATUS_CHANGED_EVENT) || contains(s.Events, event.Event))
+}
+
+// WebhookPolicy tells how often an event is posted to a subscription before
+// giving up, how long to wait between the attempts, and after how many events
+// in a row given up on the subscription is disabled. A zero DisableAfter never
+// disables subscriptions. Callbacks must be https URLs outside the network of
+// the service unless AllowInsecureURLs, meant for development and tests only.
+type WebhookPolicy struct {
+	MaxAttempts       int
+	RetryBaseDelay    time.Duration
+	RetryMaxDelay     time.Duration
+	DisableAfter      int
+	AllowInsecureURLs bool
+}
+
+// callbackURL checks the URL of a subscription. A host name is only checked
+// here when it names the machine itself, the addresses it resolves to are
+// checked again by AllowsAddress when the events are posted.
+func (p WebhookPolicy) callbackURL(value interface{}) error {
+	callback, err := url.Parse(value.(string))
+
+	if err != nil || (callback.Scheme != "http" && callback.Scheme != "https") || callback.Host == "" {
+		return errors.New("must be an http or https url")
+	}
+
+	if p.AllowInsecureURLs {
+		return nil
+	}
+
+	if callback.Scheme != "https" {
+		return errors.New("must be an https url")
+	}
+
+	host := strings.ToLower(callback.Hostname())
+	ip := net.ParseIP(host)
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || (ip != nil && !p.AllowsAddress(ip)) {
+		return errors.New("must not point to a loopback, link-local or private address")
+	}
+
+	return nil
+}
+
+// AllowsAddress tells whether events may be posted to the address: loopback,
+// link-local, private and unspecified addresses are refused, so a
+// subscription cannot reach services inside the network of the service.
+func (p WebhookPolicy) AllowsAddress(ip net.IP) bool {
+	if p.AllowInsecureURLs {
+		return true
+	}
+
+	return !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsPrivate() && !ip.IsUnspecified()
+}
+
+// RetryDelay is the wait before the attempt following the given one, doubling
+// on every attempt up to RetryMaxDelay.
+func (p WebhookPolicy) RetryDelay(attempt int) time.Duration {
+	delay := p.RetryBaseDelay
+	for i := 1; i < attempt && delay < p.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > p.RetryMaxDelay {
+		return p.RetryMaxDelay
+	}
+
+	return delay
+}
+
+// WebhookDelivery records one attempt to post an event to a subscription, as
+// shown in the delivery log of the subscription. Deliveries are only ever
+// appended.
+type WebhookDelivery struct {
+	SubscriptionKey string `dynamo:",hash" json:"-"`
+	DeliveryId      string `dynamo:",range" json:"-"`
+	StoreId         string
+	SubscriptionId  string
+	EventId         string
+	Event           string
+	Attempt         int
+	At              time.Time
+	StatusCode      int    `dynamo:",omitempty" json:",omitempty"`
+	Error           string `dynamo:",omitempty" json:",omitempty"`
+	Delivered       bool
+}
+
+// NewWebhookDelivery describes the attempt to post the event to the
+// subscription made at the given instant, answered with statusCode or failed
+// with err.
+func NewWebhookDelivery(subscription WebhookSubscription, event StatusChangeEvent, attempt int, at time.Time, statusCode int, err error) WebhookDelivery {
+	delivery := WebhookDelivery{
+		SubscriptionKey: WebhookSubscriptionKey(subscription.StoreId, subscription.SubscriptionId),
+		DeliveryId:      fmt.Sprintf("%s#%d", at.UTC().Format(auditEntryTimeLayout), attempt),
+		StoreId:         subscription.StoreId,
+		SubscriptionId:  subscription.SubscriptionId,
+		EventId:         event.EventId,
+		Event:           event.Event,
+		Attempt:         attempt,
+		At:              at,
+		StatusCode:      statusCode,
+		Delivered:       err == nil,
+	}
+
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	return delivery
+}
+
+// WebhookSubscriptionKey groups the deliveries of a subscription of a store.
+func WebhookSubscriptionKey(storeId string, subscriptionId string) string {
+	return fmt.Sprintf("%s#%s", storeId, subscriptionId)
+}
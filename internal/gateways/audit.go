@@ -0,0 +1,45 @@
+package gateways
+
+import (
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/guregu/dynamo/v2"
+)
+
+type auditGateway struct {
+	dynamo external.DynamoAdapter[entities.AuditEntry]
+}
+
+// Append stores the entry unless it was already stored, so an entry synced
+// again from a store node is not an error.
+func (a auditGateway) Append(entry entities.AuditEntry) error {
+	err := a.dynamo.CreateIfNotExists(entry, "OrderKey")
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (a auditGateway) GetByOrderId(storeId string, orderId uint32) (entries []entities.AuditEntry, err error) {
+	entries, err = a.dynamo.GetAllByKey("OrderKey", entities.AuditOrderKey(storeId, orderId))
+
+	if err != nil {
+		return []entities.AuditEntry{}, err
+	}
+
+	if entries == nil {
+		entries = []entities.AuditEntry{}
+	}
+
+	return entries, nil
+}
+
+func NewAuditGateway(orm external.DynamoAdapter[entities.AuditEntry], table string) repository.AuditRepository {
+	orm.SetTable(table)
+	return &auditGateway{
+		dynamo: orm,
+	}
+}
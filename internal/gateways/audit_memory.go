@@ -0,0 +1,55 @@
+package gateways
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+// auditMemoryGateway keeps the audit trail in memory, for tests and demos.
+type auditMemoryGateway struct {
+	mu      sync.RWMutex
+	entries map[string]map[string]entities.AuditEntry
+}
+
+// Append stores the entry unless an entry with the same id was already stored
+// for the order.
+func (a *auditMemoryGateway) Append(entry entities.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	orderKey := entities.AuditOrderKey(entry.StoreId, entry.OrderId)
+	if a.entries[orderKey] == nil {
+		a.entries[orderKey] = map[string]entities.AuditEntry{}
+	}
+
+	if _, ok := a.entries[orderKey][entry.EntryId]; !ok {
+		a.entries[orderKey][entry.EntryId] = entry
+	}
+
+	return nil
+}
+
+func (a *auditMemoryGateway) GetByOrderId(storeId string, orderId uint32) ([]entities.AuditEntry, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	entries := []entities.AuditEntry{}
+	for _, entry := range a.entries[entities.AuditOrderKey(storeId, orderId)] {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].EntryId < entries[j].EntryId
+	})
+
+	return entries, nil
+}
+
+func NewAuditMemoryGateway() repository.AuditRepository {
+	return &auditMemoryGateway{
+		entries: map[string]map[string]entities.AuditEntry{},
+	}
+}
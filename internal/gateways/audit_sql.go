@@ -0,0 +1,78 @@
+package gateways
+
+import (
+	"database/sql"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+type auditSQLGateway struct {
+	db   *sql.DB
+	bind func(query string) string
+}
+
+// Append stores the entry unless an entry with the same id was already stored
+// for the order.
+func (a auditSQLGateway) Append(entry entities.AuditEntry) error {
+	_, err := a.db.Exec(a.bind(`INSERT INTO production_order_audit
+			(store_id, order_id, entry_id, at, action, actor, station, previous_status, new_status, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (store_id, order_id, entry_id) DO NOTHING`),
+		entry.StoreId, entry.OrderId, entry.EntryId, entry.At, entry.Action, entry.Actor,
+		entry.Station, entry.PreviousStatus, entry.NewStatus, entry.RequestId,
+	)
+
+	return err
+}
+
+func (a auditSQLGateway) GetByOrderId(storeId string, orderId uint32) ([]entities.AuditEntry, error) {
+	rows, err := a.db.Query(a.bind(`SELECT entry_id, at, store_id, order_id, action, actor, station, previous_status, new_status, request_id
+		FROM production_order_audit
+		WHERE store_id = $1 AND order_id = $2
+		ORDER BY entry_id`),
+		storeId, orderId,
+	)
+
+	if err != nil {
+		return []entities.AuditEntry{}, err
+	}
+
+	defer rows.Close()
+
+	entries := []entities.AuditEntry{}
+	for rows.Next() {
+		entry := entities.AuditEntry{}
+		err = rows.Scan(
+			&entry.EntryId, &entry.At, &entry.StoreId, &entry.OrderId, &entry.Action, &entry.Actor,
+			&entry.Station, &entry.PreviousStatus, &entry.NewStatus, &entry.RequestId,
+		)
+
+		if err != nil {
+			return []entities.AuditEntry{}, err
+		}
+
+		entry.OrderKey = entities.AuditOrderKey(entry.StoreId, entry.OrderId)
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []entities.AuditEntry{}, err
+	}
+
+	return entries, nil
+}
+
+func NewAuditPostgresGateway(db *sql.DB) repository.AuditRepository {
+	return &auditSQLGateway{
+		db:   db,
+		bind: postgresPlaceholders,
+	}
+}
+
+func NewAuditSQLiteGateway(db *sql.DB) repository.AuditRepository {
+	return &auditSQLGateway{
+		db:   db,
+		bind: sqlitePlaceholders,
+	}
+}
@@ -0,0 +1,48 @@
+package gateways
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditPostgresGateway(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	entry := entities.AuditEntry{
+		OrderKey:       "loja-1#1",
+		EntryId:        "2024-10-10T12:00:00.000000000Z#STATUS_CHANGED",
+		At:             time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+		StoreId:        "loja-1",
+		OrderId:        1,
+		Action:         "STATUS_CHANGED",
+		Actor:          "cozinheiro",
+		Station:        "grill",
+		PreviousStatus: "RECEBIDO",
+		NewStatus:      "EM_PREPARACAO",
+		RequestId:      "req-1",
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO production_order_audit")).
+		WithArgs("loja-1", int64(1), entry.EntryId, entry.At, "STATUS_CHANGED", "cozinheiro", "grill", "RECEBIDO", "EM_PREPARACAO", "req-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, NewAuditPostgresGateway(db).Append(entry))
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM production_order_audit")).
+		WithArgs("loja-1", int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"entry_id", "at", "store_id", "order_id", "action", "actor", "station", "previous_status", "new_status", "request_id"}).
+			AddRow(entry.EntryId, entry.At, "loja-1", int64(1), "STATUS_CHANGED", "cozinheiro", "grill", "RECEBIDO", "EM_PREPARACAO", "req-1"))
+
+	got, err := NewAuditPostgresGateway(db).GetByOrderId("loja-1", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.AuditEntry{entry}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
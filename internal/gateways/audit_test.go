@@ -0,0 +1,85 @@
+package gateways
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	mock_external "github.com/8soat-grupo35/fastfood-order-production/external/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditGateway_Append(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.AuditEntry](ctrl)
+	mockAdapter.EXPECT().SetTable("production_order_audit").Times(1)
+
+	entry := entities.AuditEntry{OrderKey: "loja-1#1", StoreId: "loja-1", OrderId: 1}
+	mockAdapter.EXPECT().CreateIfNotExists(entry, "OrderKey").Return(nil).Times(1)
+
+	gateway := NewAuditGateway(mockAdapter, "production_order_audit")
+
+	assert.NoError(t, gateway.Append(entry))
+
+	mockAdapter.EXPECT().CreateIfNotExists(entry, "OrderKey").
+		Return(&smithy.GenericAPIError{Code: "ConditionalCheckFailedException"}).Times(1)
+
+	assert.NoError(t, gateway.Append(entry))
+
+	mockAdapter.EXPECT().CreateIfNotExists(entry, "OrderKey").Return(errors.New("teste")).Times(1)
+
+	assert.EqualError(t, gateway.Append(entry), "teste")
+}
+
+func TestAuditGateway_GetByOrderId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.AuditEntry](ctrl)
+	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
+
+	response := []entities.AuditEntry{
+		{
+			OrderKey:       "loja-1#1",
+			EntryId:        "2024-10-10T12:00:00.000000000Z#STATUS_CHANGED",
+			At:             time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+			StoreId:        "loja-1",
+			OrderId:        1,
+			Action:         "STATUS_CHANGED",
+			Actor:          "cozinheiro",
+			Station:        "grill",
+			PreviousStatus: "RECEBIDO",
+			NewStatus:      "EM_PREPARACAO",
+			RequestId:      "req-1",
+		},
+	}
+	mockAdapter.EXPECT().GetAllByKey("OrderKey", "loja-1#1").Return(response, nil).Times(1)
+
+	got, err := NewAuditGateway(mockAdapter, "production_order_audit").GetByOrderId("loja-1", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response, got)
+
+	mockAdapter.EXPECT().GetAllByKey("OrderKey", "loja-1#1").Return(nil, errors.New("teste")).Times(1)
+
+	got, err = NewAuditGateway(mockAdapter, "production_order_audit").GetByOrderId("loja-1", 1)
+
+	assert.Error(t, err)
+	assert.Equal(t, []entities.AuditEntry{}, got)
+}
+
+func TestAuditGateway_GetByOrderIdWithoutEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.AuditEntry](ctrl)
+	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
+	mockAdapter.EXPECT().GetAllByKey("OrderKey", "loja-1#2").Return(nil, nil).Times(1)
+
+	got, err := NewAuditGateway(mockAdapter, "production_order_audit").GetByOrderId("loja-1", 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.AuditEntry{}, got)
+}
@@ -7,31 +7,57 @@ import (
 	"github.com/8soat-grupo35/fastfood-order-production/external"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/guregu/dynamo/v2"
 )
 
 type productionOrderGateway struct {
-	dynamo external.DynamoAdapter
+	dynamo external.DynamoAdapter[entities.ProductionOrder]
 }
 
-func (p productionOrderGateway) GetAll() (orders []entities.ProductionOrder, err error) {
-	value, err := p.dynamo.GetAll()
+// GetOpen reads the sparse OpenOrders index, which holds only the orders
+// whose OpenStoreId is set.
+func (p productionOrderGateway) GetOpen() (orders []entities.ProductionOrder, err error) {
+	orders, err = p.dynamo.GetAllByIndex(entities.OPEN_ORDERS_INDEX)
 
 	if err != nil {
 		return []entities.ProductionOrder{}, err
 	}
 
-	for _, item := range value {
-		orders = append(orders, entities.ProductionOrder{
-			OrderId: uint32(item["ID"].(float64)),
-			Status:  item["Status"].(string),
-		})
+	return orders, nil
+}
+
+func (p productionOrderGateway) GetAllByStore(storeId string) (orders []entities.ProductionOrder, err error) {
+	orders, err = p.dynamo.GetAllByKey("StoreID", storeId)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, err
 	}
 
 	return orders, nil
 }
 
-func (p productionOrderGateway) GetByOrderId(orderId uint32) (order *entities.ProductionOrder, err error) {
-	value, err := p.dynamo.GetOneByKey("ID", orderId)
+func (p productionOrderGateway) GetPage(storeId string, limit int, cursor string) (orders []entities.ProductionOrder, nextCursor string, err error) {
+	orders, nextCursor, err = p.dynamo.GetPageByKey("StoreID", storeId, limit, cursor)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	return orders, nextCursor, nil
+}
+
+func (p productionOrderGateway) GetPageDescending(storeId string, limit int, cursor string) (orders []entities.ProductionOrder, nextCursor string, err error) {
+	orders, nextCursor, err = p.dynamo.GetPageByKeyDescending("StoreID", storeId, limit, cursor)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	return orders, nextCursor, nil
+}
+
+func (p productionOrderGateway) GetByOrderId(storeId string, orderId uint32) (order *entities.ProductionOrder, err error) {
+	value, err := p.dynamo.GetOneByKeys("StoreID", storeId, "ID", orderId)
 
 	if err != nil {
 
@@ -42,13 +68,19 @@ func (p productionOrderGateway) GetByOrderId(orderId uint32) (order *entities.Pr
 		return order, err
 	}
 
-	order = p.convertDynamoToEntity(value)
-
-	return order, nil
+	return &value, nil
 }
 
+// Create stores the order as its first version, failing when the store
+// already has an order with the same id.
 func (p productionOrderGateway) Create(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
-	err := p.dynamo.Create(order)
+	order.OpenStoreId = openStoreId(order)
+	order.Version = 1
+	err := p.dynamo.CreateIfNotExists(order, "StoreID")
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil, productionOrderExistsError(order)
+	}
 
 	if err != nil {
 		return nil, err
@@ -57,28 +89,138 @@ func (p productionOrderGateway) Create(order entities.ProductionOrder) (*entitie
 	return &order, nil
 }
 
+// Update only applies while the stored order still has the version the order
+// was read with, when it was read with one.
 func (p productionOrderGateway) Update(order entities.ProductionOrder) (updatedProductionOrder *entities.ProductionOrder, err error) {
-	fmt.Println(order)
-	value, err := p.dynamo.UpdateValue("ID", order.OrderId, "Status", order.Status)
+	value, err := p.dynamo.UpdateByKeys("StoreID", "ID", keyedUpdate(order))
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil, p.updateMissError(order)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+// UpdateAll updates the orders in a single transaction, which returns nothing:
+// the orders are returned as given. The transaction does not tell which order
+// is missing or changed when one is, so the error names the store only.
+func (p productionOrderGateway) UpdateAll(orders []entities.ProductionOrder) ([]entities.ProductionOrder, error) {
+	if len(orders) == 0 {
+		return []entities.ProductionOrder{}, nil
+	}
+
+	updates := []external.KeyedUpdate{}
+	for _, order := range orders {
+		updates = append(updates, keyedUpdate(order))
+	}
+
+	err := p.dynamo.UpdateAllByKeys("StoreID", "ID", updates)
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil, productionOrdersNotFoundError(orders)
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	updatedProductionOrder = p.convertDynamoToEntity(value)
+	return orders, nil
+}
+
+// updateMissError tells whether an update matched no order because the order
+// does not exist or because it was changed since it was read.
+func (p productionOrderGateway) updateMissError(order entities.ProductionOrder) error {
+	current, err := p.GetByOrderId(order.StoreId, order.OrderId)
+
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return productionOrderNotFoundError(order)
+	}
+
+	return productionOrderChangedError(order)
+}
+
+func keyedUpdate(order entities.ProductionOrder) external.KeyedUpdate {
+	return external.KeyedUpdate{
+		ValueKey:        order.StoreId,
+		ValueRangeKey:   order.OrderId,
+		ValuesToUpdate:  valuesToUpdate(order),
+		Version:         "Version",
+		ExpectedVersion: order.Version,
+	}
+}
+
+// valuesToUpdate leaves out what is empty in the order, keeping the stored
+// values.
+func valuesToUpdate(order entities.ProductionOrder) map[string]interface{} {
+	values := map[string]interface{}{
+		"Status":      order.Status,
+		"OpenStoreId": openStoreId(order),
+	}
+
+	if order.Priority != "" {
+		values["Priority"] = order.Priority
+	}
 
-	return updatedProductionOrder, nil
+	if len(order.Tickets) > 0 {
+		values["Tickets"] = order.Tickets
+	}
+
+	if len(order.History) > 0 {
+		values["History"] = order.History
+	}
+
+	return values
 }
 
-func (p productionOrderGateway) convertDynamoToEntity(item map[string]interface{}) *entities.ProductionOrder {
-	return &entities.ProductionOrder{
-		OrderId: uint32(item["ID"].(float64)),
-		Status:  item["Status"].(string),
+// openStoreId is the OpenStoreId of the order, left empty once it is closed
+// so that updating it removes the order from the OpenOrders index.
+func openStoreId(order entities.ProductionOrder) string {
+	if order.IsClosed() {
+		return ""
 	}
+
+	return order.StoreId
+}
+
+// productionOrderNotFoundError is returned by every repository updating an
+// order it does not have.
+func productionOrderNotFoundError(order entities.ProductionOrder) error {
+	return fmt.Errorf("production order %d not found in store %s", order.OrderId, order.StoreId)
+}
+
+// productionOrderExistsError is returned by every repository creating an
+// order the store already has.
+func productionOrderExistsError(order entities.ProductionOrder) error {
+	return fmt.Errorf("production order %d already exists in store %s", order.OrderId, order.StoreId)
+}
+
+// productionOrdersNotFoundError is returned by repositories updating orders in
+// a transaction when one of them is missing.
+func productionOrdersNotFoundError(orders []entities.ProductionOrder) error {
+	storeId := ""
+	if len(orders) > 0 {
+		storeId = orders[0].StoreId
+	}
+
+	return fmt.Errorf("some of the production orders were not found in store %s", storeId)
+}
+
+// productionOrderChangedError is returned by versioned repositories updating an
+// order changed since it was read.
+func productionOrderChangedError(order entities.ProductionOrder) error {
+	return fmt.Errorf("production order %d of store %s was changed by another request, read it again", order.OrderId, order.StoreId)
 }
 
-func NewProductionOrderGateway(orm external.DynamoAdapter) repository.ProductionOrderRepository {
-	orm.SetTable("production_order")
+func NewProductionOrderGateway(orm external.DynamoAdapter[entities.ProductionOrder], table string) repository.ProductionOrderRepository {
+	orm.SetTable(table)
 	return &productionOrderGateway{
 		dynamo: orm,
 	}
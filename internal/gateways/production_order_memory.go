@@ -0,0 +1,249 @@
+package gateways
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+type productionOrderKey struct {
+	storeId string
+	orderId uint32
+}
+
+// productionOrderMemoryGateway keeps the production orders in memory, with the
+// semantics of the SQL gateways. Orders are lost when the process exits, so it
+// is meant for tests and demos.
+type productionOrderMemoryGateway struct {
+	mu     sync.RWMutex
+	orders map[productionOrderKey]entities.ProductionOrder
+}
+
+func (p *productionOrderMemoryGateway) GetOpen() ([]entities.ProductionOrder, error) {
+	return p.filter(func(order entities.ProductionOrder) bool {
+		return !order.IsClosed()
+	}), nil
+}
+
+func (p *productionOrderMemoryGateway) GetAllByStore(storeId string) ([]entities.ProductionOrder, error) {
+	return p.filter(func(order entities.ProductionOrder) bool {
+		return order.StoreId == storeId
+	}), nil
+}
+
+// GetPage reads the orders of a store by order id. The cursor is the last order
+// id of the previous page, as in the SQL gateways.
+func (p *productionOrderMemoryGateway) GetPage(storeId string, limit int, cursor string) ([]entities.ProductionOrder, string, error) {
+	afterOrderId, err := decodeOrderIdCursor(cursor)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	orders := p.filter(func(order entities.ProductionOrder) bool {
+		return order.StoreId == storeId && order.OrderId > afterOrderId
+	})
+
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+
+	nextCursor := ""
+	if len(orders) == limit {
+		nextCursor = encodeOrderIdCursor(orders[len(orders)-1].OrderId)
+	}
+
+	return orders, nextCursor, nil
+}
+
+// GetPageDescending reads the orders of a store from the highest order id down,
+// as in the SQL gateways.
+func (p *productionOrderMemoryGateway) GetPageDescending(storeId string, limit int, cursor string) ([]entities.ProductionOrder, string, error) {
+	beforeOrderId, err := decodeOrderIdCursorBefore(cursor)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	orders := p.filter(func(order entities.ProductionOrder) bool {
+		return order.StoreId == storeId && int64(order.OrderId) < beforeOrderId
+	})
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].OrderId > orders[j].OrderId
+	})
+
+	if len(orders) > limit {
+		orders = orders[:limit]
+	}
+
+	nextCursor := ""
+	if len(orders) == limit {
+		nextCursor = encodeOrderIdCursor(orders[len(orders)-1].OrderId)
+	}
+
+	return orders, nextCursor, nil
+}
+
+func (p *productionOrderMemoryGateway) GetByOrderId(storeId string, orderId uint32) (*entities.ProductionOrder, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	order, ok := p.orders[productionOrderKey{storeId, orderId}]
+
+	if !ok {
+		return nil, nil
+	}
+
+	order = copyProductionOrder(order)
+	return &order, nil
+}
+
+// Create stores the order as its first version, failing like the other
+// gateways when the store already has an order with the same id.
+func (p *productionOrderMemoryGateway) Create(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := productionOrderKey{order.StoreId, order.OrderId}
+	if _, exists := p.orders[key]; exists {
+		return nil, productionOrderExistsError(order)
+	}
+
+	order = copyProductionOrder(order)
+	order.Version = 1
+	p.orders[key] = order
+
+	created := copyProductionOrder(order)
+	return &created, nil
+}
+
+// Update changes the status, the priority, the tickets and the history of the
+// order, keeping the stored ones when they are empty. An order read with a
+// version is only updated while it still has that version.
+func (p *productionOrderMemoryGateway) Update(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	err := p.check(order)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updated := p.update(order)
+	return &updated, nil
+}
+
+// UpdateAll checks every order before updating any, holding the lock
+// throughout.
+func (p *productionOrderMemoryGateway) UpdateAll(orders []entities.ProductionOrder) ([]entities.ProductionOrder, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, order := range orders {
+		err := p.check(order)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updatedOrders := []entities.ProductionOrder{}
+	for _, order := range orders {
+		updatedOrders = append(updatedOrders, p.update(order))
+	}
+
+	return updatedOrders, nil
+}
+
+// check tells whether the order can be updated. The lock must be held.
+func (p *productionOrderMemoryGateway) check(order entities.ProductionOrder) error {
+	stored, ok := p.orders[productionOrderKey{order.StoreId, order.OrderId}]
+
+	if !ok {
+		return productionOrderNotFoundError(order)
+	}
+
+	if order.Version != 0 && order.Version != stored.Version {
+		return productionOrderChangedError(order)
+	}
+
+	return nil
+}
+
+// update stores a checked order. The lock must be held.
+func (p *productionOrderMemoryGateway) update(order entities.ProductionOrder) entities.ProductionOrder {
+	key := productionOrderKey{order.StoreId, order.OrderId}
+	stored := p.orders[key]
+
+	order = copyProductionOrder(order)
+	stored.Status = order.Status
+
+	if order.Priority != "" {
+		stored.Priority = order.Priority
+	}
+
+	if len(order.Tickets) > 0 {
+		stored.Tickets = order.Tickets
+	}
+
+	if len(order.History) > 0 {
+		stored.History = order.History
+	}
+
+	stored.Version++
+	p.orders[key] = stored
+
+	return copyProductionOrder(stored)
+}
+
+// filter returns copies of the orders kept by keep, sorted by store and order
+// id like the SQL gateways.
+func (p *productionOrderMemoryGateway) filter(keep func(entities.ProductionOrder) bool) []entities.ProductionOrder {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	orders := []entities.ProductionOrder{}
+	for _, order := range p.orders {
+		if keep(order) {
+			orders = append(orders, copyProductionOrder(order))
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].StoreId != orders[j].StoreId {
+			return orders[i].StoreId < orders[j].StoreId
+		}
+
+		return orders[i].OrderId < orders[j].OrderId
+	})
+
+	return orders
+}
+
+// copyProductionOrder copies the lists of the order, so callers changing the
+// order they got do not change the stored one.
+func copyProductionOrder(order entities.ProductionOrder) entities.ProductionOrder {
+	order.Items = append([]entities.ProductionOrderItem(nil), order.Items...)
+	order.History = append([]entities.StatusTransition(nil), order.History...)
+
+	tickets := make([]entities.StationTicket, 0, len(order.Tickets))
+	for _, ticket := range order.Tickets {
+		ticket.Items = append([]entities.ProductionOrderItem(nil), ticket.Items...)
+		tickets = append(tickets, ticket)
+	}
+
+	if order.Tickets != nil {
+		order.Tickets = tickets
+	}
+
+	return order
+}
+
+func NewProductionOrderMemoryGateway() repository.ProductionOrderRepository {
+	return &productionOrderMemoryGateway{
+		orders: map[productionOrderKey]entities.ProductionOrder{},
+	}
+}
@@ -0,0 +1,451 @@
+package gateways
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+const productionOrderColumns = "store_id, order_id, status, priority, created_at, items, tickets, history, version"
+
+// productionOrderSQLGateway keeps the production orders in a SQL database. The
+// queries are written with $1 placeholders, which bind rewrites for databases
+// using another style. With an outbox, every order stored is queued in it in
+// the same transaction.
+type productionOrderSQLGateway struct {
+	db     *sql.DB
+	bind   func(query string) string
+	outbox *syncOutboxSQLGateway
+}
+
+// GetOpen reads the open orders through the production_orders_open partial
+// index. The closed statuses are written in the query, as the index is only
+// used when its condition is.
+func (p productionOrderSQLGateway) GetOpen() ([]entities.ProductionOrder, error) {
+	return p.query("SELECT " + productionOrderColumns + " FROM production_orders WHERE status NOT IN ('" +
+		entities.FINISHED_STATUS + "', '" + entities.CANCELED_STATUS + "') ORDER BY store_id, order_id")
+}
+
+func (p productionOrderSQLGateway) GetAllByStore(storeId string) ([]entities.ProductionOrder, error) {
+	return p.query("SELECT "+productionOrderColumns+" FROM production_orders WHERE store_id = $1 ORDER BY order_id", storeId)
+}
+
+// GetPage reads the orders of a store by order id. The cursor is the last order
+// id of the previous page.
+func (p productionOrderSQLGateway) GetPage(storeId string, limit int, cursor string) ([]entities.ProductionOrder, string, error) {
+	afterOrderId, err := decodeOrderIdCursor(cursor)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	orders, err := p.query(
+		"SELECT "+productionOrderColumns+" FROM production_orders WHERE store_id = $1 AND order_id > $2 ORDER BY order_id LIMIT $3",
+		storeId, afterOrderId, limit,
+	)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	nextCursor := ""
+	if len(orders) == limit {
+		nextCursor = encodeOrderIdCursor(orders[len(orders)-1].OrderId)
+	}
+
+	return orders, nextCursor, nil
+}
+
+// GetPageDescending reads the orders of a store from the highest order id down.
+// The cursor is the last order id of the previous page.
+func (p productionOrderSQLGateway) GetPageDescending(storeId string, limit int, cursor string) ([]entities.ProductionOrder, string, error) {
+	beforeOrderId, err := decodeOrderIdCursorBefore(cursor)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	orders, err := p.query(
+		"SELECT "+productionOrderColumns+" FROM production_orders WHERE store_id = $1 AND order_id < $2 ORDER BY order_id DESC LIMIT $3",
+		storeId, beforeOrderId, limit,
+	)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, "", err
+	}
+
+	nextCursor := ""
+	if len(orders) == limit {
+		nextCursor = encodeOrderIdCursor(orders[len(orders)-1].OrderId)
+	}
+
+	return orders, nextCursor, nil
+}
+
+func (p productionOrderSQLGateway) GetByOrderId(storeId string, orderId uint32) (*entities.ProductionOrder, error) {
+	row := p.db.QueryRow(p.bind("SELECT "+productionOrderColumns+" FROM production_orders WHERE store_id = $1 AND order_id = $2"), storeId, orderId)
+	order, err := scanProductionOrder(row)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+// Create stores the order as its first version, failing like the other
+// gateways when the store already has an order with the same id.
+func (p productionOrderSQLGateway) Create(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+	var created entities.ProductionOrder
+
+	err := p.write(func(db sqlWriter) (err error) {
+		created, err = p.create(db, order)
+
+		if err != nil {
+			return err
+		}
+
+		return p.enqueue(db, created)
+	})
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, productionOrderExistsError(order)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// create returns sql.ErrNoRows when the order already exists.
+func (p productionOrderSQLGateway) create(db rowQuerier, order entities.ProductionOrder) (entities.ProductionOrder, error) {
+	items, tickets, history, err := marshalOrderLists(order)
+
+	if err != nil {
+		return entities.ProductionOrder{}, err
+	}
+
+	err = db.QueryRow(p.bind(`INSERT INTO production_orders (store_id, order_id, status, priority, created_at, items, tickets, history)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (store_id, order_id) DO NOTHING
+		RETURNING version`),
+		order.StoreId, order.OrderId, order.Status, order.Priority, order.CreatedAt, items, tickets, history,
+	).Scan(&order.Version)
+
+	return order, err
+}
+
+// Update changes the status, the priority, the tickets and the history of the
+// order, keeping the stored ones when they are empty like the dynamo gateway.
+// An order read with a version is only updated while it still has that
+// version, so a concurrent change is never overwritten.
+func (p productionOrderSQLGateway) Update(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+	var updated entities.ProductionOrder
+
+	err := p.write(func(db sqlWriter) (err error) {
+		updated, err = p.update(db, order)
+
+		if err != nil {
+			return err
+		}
+
+		return p.enqueue(db, updated)
+	})
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, p.updateMissError(order)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// UpdateAll updates the orders in a single transaction, rolled back as soon as
+// one of them cannot be updated.
+func (p productionOrderSQLGateway) UpdateAll(orders []entities.ProductionOrder) ([]entities.ProductionOrder, error) {
+	tx, err := p.db.Begin()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	updatedOrders := []entities.ProductionOrder{}
+	for _, order := range orders {
+		updated, err := p.update(tx, order)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			tx.Rollback()
+			return nil, p.updateMissError(order)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.enqueue(tx, updated)
+
+		if err != nil {
+			return nil, err
+		}
+
+		updatedOrders = append(updatedOrders, updated)
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedOrders, nil
+}
+
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// sqlWriter is the database or the transaction a write runs in.
+type sqlWriter interface {
+	rowQuerier
+	execer
+}
+
+// write runs a write straight on the database, or in a transaction when the
+// orders it stores are queued in the outbox, so both are kept or neither is.
+func (p productionOrderSQLGateway) write(writes func(db sqlWriter) error) error {
+	if p.outbox == nil {
+		return writes(p.db)
+	}
+
+	tx, err := p.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = writes(tx)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// enqueue queues the stored order in the outbox, when there is one.
+func (p productionOrderSQLGateway) enqueue(db execer, order entities.ProductionOrder) error {
+	if p.outbox == nil {
+		return nil
+	}
+
+	return p.outbox.enqueue(db, orderSyncChange(order))
+}
+
+func (p productionOrderSQLGateway) update(db rowQuerier, order entities.ProductionOrder) (entities.ProductionOrder, error) {
+	tickets, err := marshalOptionalList(order.Tickets)
+
+	if err != nil {
+		return entities.ProductionOrder{}, err
+	}
+
+	history, err := marshalOptionalList(order.History)
+
+	if err != nil {
+		return entities.ProductionOrder{}, err
+	}
+
+	row := db.QueryRow(p.bind(`UPDATE production_orders SET
+			status = $3,
+			priority = COALESCE(NULLIF($4, ''), priority),
+			tickets = COALESCE($5, tickets),
+			history = COALESCE($6, history),
+			version = version + 1
+		WHERE store_id = $1 AND order_id = $2 AND (CAST($7 AS BIGINT) = 0 OR version = CAST($7 AS BIGINT))
+		RETURNING `+productionOrderColumns),
+		order.StoreId, order.OrderId, order.Status, order.Priority, tickets, history, order.Version,
+	)
+
+	return scanProductionOrder(row)
+}
+
+// updateMissError tells whether an update matched no order because the order
+// does not exist or because it was changed since it was read.
+func (p productionOrderSQLGateway) updateMissError(order entities.ProductionOrder) error {
+	current, err := p.GetByOrderId(order.StoreId, order.OrderId)
+
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return productionOrderNotFoundError(order)
+	}
+
+	return productionOrderChangedError(order)
+}
+
+func (p productionOrderSQLGateway) query(query string, args ...interface{}) ([]entities.ProductionOrder, error) {
+	rows, err := p.db.Query(p.bind(query), args...)
+
+	if err != nil {
+		return []entities.ProductionOrder{}, err
+	}
+
+	defer rows.Close()
+
+	orders := []entities.ProductionOrder{}
+	for rows.Next() {
+		order, err := scanProductionOrder(rows)
+
+		if err != nil {
+			return []entities.ProductionOrder{}, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []entities.ProductionOrder{}, err
+	}
+
+	return orders, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProductionOrder(row rowScanner) (order entities.ProductionOrder, err error) {
+	var createdAt sql.NullTime
+	var items, tickets, history []byte
+
+	err = row.Scan(&order.StoreId, &order.OrderId, &order.Status, &order.Priority, &createdAt, &items, &tickets, &history, &order.Version)
+
+	if err != nil {
+		return order, err
+	}
+
+	order.CreatedAt = createdAt.Time
+
+	for column, list := range map[string]struct {
+		data  []byte
+		value interface{}
+	}{
+		"items":   {items, &order.Items},
+		"tickets": {tickets, &order.Tickets},
+		"history": {history, &order.History},
+	} {
+		err = json.Unmarshal(list.data, list.value)
+
+		if err != nil {
+			return order, fmt.Errorf("invalid %s of production order %d: %w", column, order.OrderId, err)
+		}
+	}
+
+	return order, nil
+}
+
+func marshalOrderLists(order entities.ProductionOrder) (items []byte, tickets []byte, history []byte, err error) {
+	items, err = json.Marshal(emptyIfNil(order.Items))
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tickets, err = json.Marshal(emptyIfNil(order.Tickets))
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	history, err = json.Marshal(emptyIfNil(order.History))
+
+	return items, tickets, history, err
+}
+
+// marshalOptionalList returns NULL for an empty list, which keeps the stored
+// list on update.
+func marshalOptionalList[T any](list []T) (interface{}, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(list)
+}
+
+func emptyIfNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+
+	return list
+}
+
+func encodeOrderIdCursor(orderId uint32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(orderId), 10)))
+}
+
+// decodeOrderIdCursorBefore returns the order id of a cursor for pages going down,
+// which start above every order id without a cursor.
+func decodeOrderIdCursorBefore(cursor string) (int64, error) {
+	if cursor == "" {
+		return math.MaxUint32 + 1, nil
+	}
+
+	orderId, err := decodeOrderIdCursor(cursor)
+	return int64(orderId), err
+}
+
+func decodeOrderIdCursor(cursor string) (uint32, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+
+	if err != nil {
+		return 0, repository.ErrInvalidCursor
+	}
+
+	orderId, err := strconv.ParseUint(string(decoded), 10, 32)
+
+	if err != nil {
+		return 0, repository.ErrInvalidCursor
+	}
+
+	return uint32(orderId), nil
+}
+
+func NewProductionOrderPostgresGateway(db *sql.DB) repository.ProductionOrderRepository {
+	return &productionOrderSQLGateway{
+		db:   db,
+		bind: postgresPlaceholders,
+	}
+}
+
+func NewProductionOrderSQLiteGateway(db *sql.DB) repository.ProductionOrderRepository {
+	return &productionOrderSQLGateway{
+		db:   db,
+		bind: sqlitePlaceholders,
+	}
+}
@@ -0,0 +1,238 @@
+package gateways
+
+import (
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var productionOrderRowColumns = []string{"store_id", "order_id", "status", "priority", "created_at", "items", "tickets", "history", "version"}
+
+func productionOrderRow(storeId string, orderId uint32, status string, version int64) []driver.Value {
+	return []driver.Value{
+		storeId, int64(orderId), status, "NORMAL", time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+		[]byte(`[{"Name":"X-Burguer","Category":"LANCHE","Quantity":2}]`),
+		[]byte(`[{"Station":"grill","Status":"RECEBIDO","Items":[{"Name":"X-Burguer","Category":"LANCHE","Quantity":2}]}]`),
+		[]byte(`[{"Status":"RECEBIDO","At":"2024-10-10T12:00:00Z"}]`),
+		version,
+	}
+}
+
+func postgresOrder(storeId string, orderId uint32, status string, version int64) entities.ProductionOrder {
+	burger := entities.ProductionOrderItem{Name: "X-Burguer", Category: "LANCHE", Quantity: 2}
+
+	return entities.ProductionOrder{
+		StoreId:   storeId,
+		OrderId:   orderId,
+		Status:    status,
+		Priority:  "NORMAL",
+		CreatedAt: time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+		Items:     []entities.ProductionOrderItem{burger},
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: "RECEBIDO", Items: []entities.ProductionOrderItem{burger}},
+		},
+		History: []entities.StatusTransition{
+			{Status: "RECEBIDO", At: time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC)},
+		},
+		Version: version,
+	}
+}
+
+func TestProductionOrderPostgresGateway_GetAllByStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM production_orders WHERE store_id = $1 ORDER BY order_id")).
+		WithArgs("loja-1").
+		WillReturnRows(sqlmock.NewRows(productionOrderRowColumns).
+			AddRow(productionOrderRow("loja-1", 1, "RECEBIDO", 1)...).
+			AddRow(productionOrderRow("loja-1", 2, "PRONTO", 3)...))
+
+	got, err := NewProductionOrderPostgresGateway(db).GetAllByStore("loja-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{
+		postgresOrder("loja-1", 1, "RECEBIDO", 1),
+		postgresOrder("loja-1", 2, "PRONTO", 3),
+	}, got)
+
+	mock.ExpectQuery("FROM production_orders").WillReturnError(errors.New("teste"))
+
+	got, err = NewProductionOrderPostgresGateway(db).GetOpen()
+
+	assert.Error(t, err)
+	assert.Equal(t, []entities.ProductionOrder{}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductionOrderPostgresGateway_GetOpenWithMalformedRow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	row := productionOrderRow("loja-1", 1, "RECEBIDO", 1)
+	row[5] = []byte(`{"not":"a list"}`)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM production_orders WHERE status NOT IN ('FINALIZADO', 'CANCELADO') ORDER BY store_id, order_id")).
+		WillReturnRows(sqlmock.NewRows(productionOrderRowColumns).AddRow(row...))
+
+	got, err := NewProductionOrderPostgresGateway(db).GetOpen()
+
+	assert.ErrorContains(t, err, "invalid items of production order 1")
+	assert.Equal(t, []entities.ProductionOrder{}, got)
+}
+
+func TestProductionOrderPostgresGateway_GetPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE store_id = $1 AND order_id > $2 ORDER BY order_id LIMIT $3")).
+		WithArgs("loja-1", int64(0), int64(2)).
+		WillReturnRows(sqlmock.NewRows(productionOrderRowColumns).
+			AddRow(productionOrderRow("loja-1", 1, "RECEBIDO", 1)...).
+			AddRow(productionOrderRow("loja-1", 2, "RECEBIDO", 1)...))
+
+	got, next, err := NewProductionOrderPostgresGateway(db).GetPage("loja-1", 2, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, encodeOrderIdCursor(2), next)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE store_id = $1 AND order_id > $2 ORDER BY order_id LIMIT $3")).
+		WithArgs("loja-1", int64(2), int64(2)).
+		WillReturnRows(sqlmock.NewRows(productionOrderRowColumns).
+			AddRow(productionOrderRow("loja-1", 3, "RECEBIDO", 1)...))
+
+	got, next, err = NewProductionOrderPostgresGateway(db).GetPage("loja-1", 2, next)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{postgresOrder("loja-1", 3, "RECEBIDO", 1)}, got)
+	assert.Equal(t, "", next)
+
+	_, _, err = NewProductionOrderPostgresGateway(db).GetPage("loja-1", 2, "not a cursor")
+
+	assert.EqualError(t, err, "invalid cursor")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductionOrderPostgresGateway_GetByOrderId(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE store_id = $1 AND order_id = $2")).
+		WithArgs("loja-1", int64(1)).
+		WillReturnRows(sqlmock.NewRows(productionOrderRowColumns).AddRow(productionOrderRow("loja-1", 1, "RECEBIDO", 4)...))
+
+	got, err := NewProductionOrderPostgresGateway(db).GetByOrderId("loja-1", 1)
+
+	assert.NoError(t, err)
+	order := postgresOrder("loja-1", 1, "RECEBIDO", 4)
+	assert.Equal(t, &order, got)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE store_id = $1 AND order_id = $2")).
+		WithArgs("loja-1", int64(9)).
+		WillReturnRows(sqlmock.NewRows(productionOrderRowColumns))
+
+	got, err = NewProductionOrderPostgresGateway(db).GetByOrderId("loja-1", 9)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductionOrderPostgresGateway_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	order := postgresOrder("loja-1", 1, "RECEBIDO", 0)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO production_orders")).
+		WithArgs(
+			"loja-1", int64(1), "RECEBIDO", "NORMAL", order.CreatedAt,
+			[]byte(`[{"Name":"X-Burguer","Category":"LANCHE","Quantity":2}]`),
+			[]byte(`[{"Station":"grill","Status":"RECEBIDO","Items":[{"Name":"X-Burguer","Category":"LANCHE","Quantity":2}]}]`),
+			[]byte(`[{"Status":"RECEBIDO","At":"2024-10-10T12:00:00Z"}]`),
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(int64(1)))
+
+	got, err := NewProductionOrderPostgresGateway(db).Create(order)
+
+	assert.NoError(t, err)
+	order.Version = 1
+	assert.Equal(t, &order, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductionOrderPostgresGateway_Update(t *testing.T) {
+	const updateQuery = "UPDATE production_orders SET"
+
+	testCases := []struct {
+		Name       string
+		SetupMocks func(mock sqlmock.Sqlmock)
+		Want       *entities.ProductionOrder
+		WantErr    string
+	}{
+		{
+			Name: "should update the order read with the current version",
+			SetupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WithArgs("loja-1", int64(1), "PRONTO", "NORMAL", sqlmock.AnyArg(), sqlmock.AnyArg(), int64(3)).
+					WillReturnRows(sqlmock.NewRows(productionOrderRowColumns).AddRow(productionOrderRow("loja-1", 1, "PRONTO", 4)...))
+			},
+			Want: func() *entities.ProductionOrder {
+				order := postgresOrder("loja-1", 1, "PRONTO", 4)
+				return &order
+			}(),
+		},
+		{
+			Name: "should return error if the order changed since it was read",
+			SetupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WillReturnRows(sqlmock.NewRows(productionOrderRowColumns))
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE store_id = $1 AND order_id = $2")).
+					WithArgs("loja-1", int64(1)).
+					WillReturnRows(sqlmock.NewRows(productionOrderRowColumns).AddRow(productionOrderRow("loja-1", 1, "PRONTO", 5)...))
+			},
+			WantErr: "production order 1 of store loja-1 was changed by another request, read it again",
+		},
+		{
+			Name: "should return error if the order is not in the store",
+			SetupMocks: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(updateQuery)).
+					WillReturnRows(sqlmock.NewRows(productionOrderRowColumns))
+				mock.ExpectQuery(regexp.QuoteMeta("WHERE store_id = $1 AND order_id = $2")).
+					WithArgs("loja-1", int64(1)).
+					WillReturnRows(sqlmock.NewRows(productionOrderRowColumns))
+			},
+			WantErr: "production order 1 not found in store loja-1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.SetupMocks(mock)
+
+			got, err := NewProductionOrderPostgresGateway(db).Update(postgresOrder("loja-1", 1, "PRONTO", 3))
+
+			assert.Equal(t, tt.Want, got)
+			if tt.WantErr != "" {
+				assert.EqualError(t, err, tt.WantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package gateways
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	db := external.ConectaSQLite(external.Config{
+		DatabaseConfig: external.DatabaseConfig{
+			Path:        filepath.Join(t.TempDir(), "production.db"),
+			AutoMigrate: true,
+		},
+	})
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestProductionOrderSQLiteGateway(t *testing.T) {
+	gateway := NewProductionOrderSQLiteGateway(openSQLite(t))
+
+	for _, orderId := range []uint32{1, 2, 3} {
+		created, err := gateway.Create(postgresOrder("loja-1", orderId, "RECEBIDO", 0))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), created.Version)
+	}
+
+	_, err := gateway.Create(postgresOrder("loja-2", 1, "RECEBIDO", 0))
+	assert.NoError(t, err)
+
+	got, err := gateway.GetByOrderId("loja-1", 2)
+
+	assert.NoError(t, err)
+	order := postgresOrder("loja-1", 2, "RECEBIDO", 1)
+	assert.Equal(t, &order, got)
+
+	got, err = gateway.GetByOrderId("loja-1", 9)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	open, err := gateway.GetOpen()
+
+	assert.NoError(t, err)
+	assert.Len(t, open, 4)
+
+	page, next, err := gateway.GetPage("loja-1", 2, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{
+		postgresOrder("loja-1", 1, "RECEBIDO", 1),
+		postgresOrder("loja-1", 2, "RECEBIDO", 1),
+	}, page)
+
+	page, next, err = gateway.GetPage("loja-1", 2, next)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{postgresOrder("loja-1", 3, "RECEBIDO", 1)}, page)
+	assert.Equal(t, "", next)
+
+	update := postgresOrder("loja-1", 2, "EM_PREPARACAO", 1)
+	update.Priority = ""
+	update.Tickets = nil
+	update.History = append(update.History, entities.StatusTransition{
+		Status: "EM_PREPARACAO",
+		At:     time.Date(2024, time.October, 10, 12, 5, 0, 0, time.UTC),
+	})
+
+	got, err = gateway.Update(update)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EM_PREPARACAO", got.Status)
+	assert.Equal(t, "NORMAL", got.Priority)
+	assert.Equal(t, postgresOrder("loja-1", 2, "RECEBIDO", 1).Tickets, got.Tickets)
+	assert.Equal(t, update.History, got.History)
+	assert.Equal(t, int64(2), got.Version)
+
+	_, err = gateway.Update(update)
+
+	assert.EqualError(t, err, "production order 2 of store loja-1 was changed by another request, read it again")
+
+	_, err = gateway.Update(postgresOrder("loja-1", 9, "PRONTO", 0))
+
+	assert.EqualError(t, err, "production order 9 not found in store loja-1")
+
+	update.Version = 0
+	update.Status = "PRONTO"
+	got, err = gateway.Update(update)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PRONTO", got.Status)
+	assert.Equal(t, int64(3), got.Version)
+
+	recreated, err := gateway.Create(postgresOrder("loja-1", 2, "RECEBIDO", 0))
+
+	assert.EqualError(t, err, "production order 2 already exists in store loja-1")
+	assert.Nil(t, recreated)
+}
+
+func TestAuditSQLiteGateway(t *testing.T) {
+	gateway := NewAuditSQLiteGateway(openSQLite(t))
+
+	entry := entities.AuditEntry{
+		OrderKey:       "loja-1#1",
+		EntryId:        "2024-10-10T12:00:00.000000000Z#STATUS_CHANGED",
+		At:             time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+		StoreId:        "loja-1",
+		OrderId:        1,
+		Action:         "STATUS_CHANGED",
+		Actor:          "cozinheiro",
+		Station:        "grill",
+		PreviousStatus: "RECEBIDO",
+		NewStatus:      "EM_PREPARACAO",
+		RequestId:      "req-1",
+	}
+
+	assert.NoError(t, gateway.Append(entry))
+	assert.NoError(t, gateway.Append(entry))
+
+	got, err := gateway.GetByOrderId("loja-1", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.AuditEntry{entry}, got)
+
+	got, err = gateway.GetByOrderId("loja-1", 2)
+
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
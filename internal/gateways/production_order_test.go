@@ -3,47 +3,43 @@ package gateways
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/8soat-grupo35/fastfood-order-production/external"
 	mock_external "github.com/8soat-grupo35/fastfood-order-production/external/mock"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/utils"
-	"github.com/golang/mock/gomock"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/guregu/dynamo/v2"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
-func TestProductionOrderGateway_GetAll(t *testing.T) {
+func TestProductionOrderGateway_GetOpen(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockAdapter := mock_external.NewMockDynamoAdapter(ctrl)
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
 	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
 
 	testCases := []utils.TestCase{
 		{
-			Name: "should return all orders",
+			Name: "should return the open orders",
 			SetupMocks: func() interface{} {
 				expectedOrders := []entities.ProductionOrder{
 					{
+						StoreId: "loja-1",
 						OrderId: 1,
 						Status:  "RECEBIDO",
 					},
 					{
+						StoreId: "loja-1",
 						OrderId: 2,
 						Status:  "EM_PREPARACAO",
 					},
 				}
 
-				response := []map[string]interface{}{
-					{
-						"ID":     float64(1),
-						"Status": "RECEBIDO",
-					},
-					{
-						"ID":     float64(2),
-						"Status": "EM_PREPARACAO",
-					},
-				}
-
-				mockAdapter.EXPECT().GetAll().Return(response, nil).Times(1)
+				mockAdapter.EXPECT().GetAllByIndex(entities.OPEN_ORDERS_INDEX).Return(expectedOrders, nil).Times(1)
 
 				return expectedOrders
 			},
@@ -54,7 +50,7 @@ func TestProductionOrderGateway_GetAll(t *testing.T) {
 			SetupMocks: func() interface{} {
 				expectedValue := []entities.ProductionOrder{}
 
-				mockAdapter.EXPECT().GetAll().Return([]map[string]interface{}{}, errors.New("teste")).Times(1)
+				mockAdapter.EXPECT().GetAllByIndex(entities.OPEN_ORDERS_INDEX).Return(nil, errors.New("teste")).Times(1)
 
 				return expectedValue
 			},
@@ -66,7 +62,7 @@ func TestProductionOrderGateway_GetAll(t *testing.T) {
 		t.Run(tt.Name, func(t *testing.T) {
 			expectedValue := tt.SetupMocks()
 
-			got, err := NewProductionOrderGateway(mockAdapter).GetAll()
+			got, err := NewProductionOrderGateway(mockAdapter, "production_order").GetOpen()
 
 			assert.Equal(t, expectedValue, got)
 
@@ -83,7 +79,7 @@ func TestProductionOrderGateway_GetAll(t *testing.T) {
 func TestProductionOrderGateway_GetByOrderId(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockAdapter := mock_external.NewMockDynamoAdapter(ctrl)
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
 	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
 
 	testCases := []utils.TestCase{
@@ -91,16 +87,12 @@ func TestProductionOrderGateway_GetByOrderId(t *testing.T) {
 			Name: "should return the order successfully",
 			SetupMocks: func() interface{} {
 				expectedOrder := &entities.ProductionOrder{
+					StoreId: "loja-1",
 					OrderId: 1,
 					Status:  "RECEBIDO",
 				}
 
-				response := map[string]interface{}{
-					"ID":     float64(1),
-					"Status": "RECEBIDO",
-				}
-
-				mockAdapter.EXPECT().GetOneByKey("ID", uint32(1)).Return(response, nil).Times(1)
+				mockAdapter.EXPECT().GetOneByKeys("StoreID", "loja-1", "ID", uint32(1)).Return(*expectedOrder, nil).Times(1)
 
 				return expectedOrder
 			},
@@ -111,7 +103,7 @@ func TestProductionOrderGateway_GetByOrderId(t *testing.T) {
 			SetupMocks: func() interface{} {
 				var expectedValue *entities.ProductionOrder = nil
 
-				mockAdapter.EXPECT().GetOneByKey("ID", uint32(1)).Return(nil, errors.New("teste")).Times(1)
+				mockAdapter.EXPECT().GetOneByKeys("StoreID", "loja-1", "ID", uint32(1)).Return(entities.ProductionOrder{}, errors.New("teste")).Times(1)
 
 				return expectedValue
 			},
@@ -122,7 +114,7 @@ func TestProductionOrderGateway_GetByOrderId(t *testing.T) {
 			SetupMocks: func() interface{} {
 				var expectedValue *entities.ProductionOrder = nil
 
-				mockAdapter.EXPECT().GetOneByKey("ID", uint32(1)).Return(nil, errors.New("no item found")).Times(1)
+				mockAdapter.EXPECT().GetOneByKeys("StoreID", "loja-1", "ID", uint32(1)).Return(entities.ProductionOrder{}, errors.New("no item found")).Times(1)
 
 				return expectedValue
 			},
@@ -134,7 +126,7 @@ func TestProductionOrderGateway_GetByOrderId(t *testing.T) {
 		t.Run(tt.Name, func(t *testing.T) {
 			expectedValue := tt.SetupMocks()
 
-			got, err := NewProductionOrderGateway(mockAdapter).GetByOrderId(1)
+			got, err := NewProductionOrderGateway(mockAdapter, "production_order").GetByOrderId("loja-1", 1)
 
 			assert.Equal(t, expectedValue, got)
 
@@ -151,22 +143,26 @@ func TestProductionOrderGateway_GetByOrderId(t *testing.T) {
 func TestProductionOrderGateway_Create(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockAdapter := mock_external.NewMockDynamoAdapter(ctrl)
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
 	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
 
 	orderToCreate := entities.ProductionOrder{
+		StoreId: "loja-1",
 		OrderId: 1,
 		Status:  "RECEBIDO",
 	}
+	storedOrder := orderToCreate
+	storedOrder.OpenStoreId = "loja-1"
+	storedOrder.Version = 1
 
 	testCases := []utils.TestCase{
 		{
 			Name: "should create the order successfully",
 			SetupMocks: func() interface{} {
 
-				mockAdapter.EXPECT().Create(orderToCreate).Return(nil).Times(1)
+				mockAdapter.EXPECT().CreateIfNotExists(storedOrder, "StoreID").Return(nil).Times(1)
 
-				return &orderToCreate
+				return &storedOrder
 			},
 			WantErr: false,
 		},
@@ -175,7 +171,18 @@ func TestProductionOrderGateway_Create(t *testing.T) {
 			SetupMocks: func() interface{} {
 				var expectedValue *entities.ProductionOrder = nil
 
-				mockAdapter.EXPECT().Create(orderToCreate).Return(errors.New("teste")).Times(1)
+				mockAdapter.EXPECT().CreateIfNotExists(storedOrder, "StoreID").Return(errors.New("teste")).Times(1)
+
+				return expectedValue
+			},
+			WantErr: true,
+		},
+		{
+			Name: "should return error if the store already has the order",
+			SetupMocks: func() interface{} {
+				var expectedValue *entities.ProductionOrder = nil
+
+				mockAdapter.EXPECT().CreateIfNotExists(storedOrder, "StoreID").Return(&types.ConditionalCheckFailedException{}).Times(1)
 
 				return expectedValue
 			},
@@ -187,7 +194,7 @@ func TestProductionOrderGateway_Create(t *testing.T) {
 		t.Run(tt.Name, func(t *testing.T) {
 			expectedValue := tt.SetupMocks()
 
-			got, err := NewProductionOrderGateway(mockAdapter).Create(orderToCreate)
+			got, err := NewProductionOrderGateway(mockAdapter, "production_order").Create(orderToCreate)
 
 			assert.Equal(t, expectedValue, got)
 
@@ -204,36 +211,112 @@ func TestProductionOrderGateway_Create(t *testing.T) {
 func TestProductionOrderGateway_UpdateValue(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
-	mockAdapter := mock_external.NewMockDynamoAdapter(ctrl)
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
 	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
 
 	orderToUpdate := entities.ProductionOrder{
+		StoreId: "loja-1",
 		OrderId: 1,
 		Status:  "RECEBIDO",
+		Version: 3,
+	}
+	update := external.KeyedUpdate{
+		ValueKey:        "loja-1",
+		ValueRangeKey:   uint32(1),
+		ValuesToUpdate:  map[string]interface{}{"Status": orderToUpdate.Status, "OpenStoreId": "loja-1"},
+		Version:         "Version",
+		ExpectedVersion: 3,
 	}
 
-	response := map[string]interface{}{
-		"ID":     float64(1),
-		"Status": "RECEBIDO",
+	testCases := []struct {
+		Name       string
+		SetupMocks func()
+		Want       *entities.ProductionOrder
+		WantErr    string
+	}{
+		{
+			Name: "should update the order successfully",
+			SetupMocks: func() {
+				mockAdapter.EXPECT().UpdateByKeys("StoreID", "ID", update).Return(orderToUpdate, nil).Times(1)
+			},
+			Want: &orderToUpdate,
+		},
+		{
+			Name: "should return error if dynamo fails",
+			SetupMocks: func() {
+				mockAdapter.EXPECT().UpdateByKeys("StoreID", "ID", update).Return(entities.ProductionOrder{}, errors.New("teste")).Times(1)
+			},
+			WantErr: "teste",
+		},
+		{
+			Name: "should return error if the order is not in the store",
+			SetupMocks: func() {
+				mockAdapter.EXPECT().UpdateByKeys("StoreID", "ID", update).Return(entities.ProductionOrder{}, &types.ConditionalCheckFailedException{}).Times(1)
+				mockAdapter.EXPECT().GetOneByKeys("StoreID", "loja-1", "ID", uint32(1)).Return(entities.ProductionOrder{}, dynamo.ErrNotFound).Times(1)
+			},
+			WantErr: "production order 1 not found in store loja-1",
+		},
+		{
+			Name: "should return error if the order changed since it was read",
+			SetupMocks: func() {
+				mockAdapter.EXPECT().UpdateByKeys("StoreID", "ID", update).Return(entities.ProductionOrder{}, &types.ConditionalCheckFailedException{}).Times(1)
+				mockAdapter.EXPECT().GetOneByKeys("StoreID", "loja-1", "ID", uint32(1)).Return(orderToUpdate, nil).Times(1)
+			},
+			WantErr: "production order 1 of store loja-1 was changed by another request, read it again",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.SetupMocks()
+
+			got, err := NewProductionOrderGateway(mockAdapter, "production_order").Update(orderToUpdate)
+
+			assert.Equal(t, tt.Want, got)
+
+			if tt.WantErr != "" {
+				assert.EqualError(t, err, tt.WantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProductionOrderGateway_UpdateAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
+	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
+
+	ordersToUpdate := []entities.ProductionOrder{
+		{StoreId: "loja-1", OrderId: 1, Status: "EM_PREPARACAO"},
+		{StoreId: "loja-1", OrderId: 2, Status: "PRONTO", Priority: "URGENTE"},
+	}
+	updates := []external.KeyedUpdate{
+		{ValueKey: "loja-1", ValueRangeKey: uint32(1), ValuesToUpdate: map[string]interface{}{"Status": "EM_PREPARACAO", "OpenStoreId": "loja-1"}, Version: "Version"},
+		{ValueKey: "loja-1", ValueRangeKey: uint32(2), ValuesToUpdate: map[string]interface{}{"Status": "PRONTO", "OpenStoreId": "loja-1", "Priority": "URGENTE"}, Version: "Version"},
 	}
 
 	testCases := []utils.TestCase{
 		{
-			Name: "should update the order successfully",
+			Name: "should update every order in a transaction",
 			SetupMocks: func() interface{} {
+				mockAdapter.EXPECT().UpdateAllByKeys("StoreID", "ID", updates).Return(nil).Times(1)
 
-				mockAdapter.EXPECT().UpdateValue("ID", orderToUpdate.OrderId, "Status", orderToUpdate.Status).Return(response, nil).Times(1)
-
-				return &orderToUpdate
+				return ordersToUpdate
 			},
 			WantErr: false,
 		},
 		{
-			Name: "should return error if dynamo fails",
+			Name: "should return error if an order is not in the store",
 			SetupMocks: func() interface{} {
-				var expectedValue *entities.ProductionOrder = nil
+				var expectedValue []entities.ProductionOrder = nil
 
-				mockAdapter.EXPECT().UpdateValue("ID", orderToUpdate.OrderId, "Status", orderToUpdate.Status).Return(nil, errors.New("teste")).Times(1)
+				mockAdapter.EXPECT().UpdateAllByKeys("StoreID", "ID", updates).Return(&types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{{Code: aws.String("None")}, {Code: aws.String("ConditionalCheckFailed")}},
+				}).Times(1)
 
 				return expectedValue
 			},
@@ -245,12 +328,12 @@ func TestProductionOrderGateway_UpdateValue(t *testing.T) {
 		t.Run(tt.Name, func(t *testing.T) {
 			expectedValue := tt.SetupMocks()
 
-			got, err := NewProductionOrderGateway(mockAdapter).Update(orderToUpdate)
+			got, err := NewProductionOrderGateway(mockAdapter, "production_order").UpdateAll(ordersToUpdate)
 
 			assert.Equal(t, expectedValue, got)
 
 			if tt.WantErr {
-				assert.Error(t, err)
+				assert.EqualError(t, err, "some of the production orders were not found in store loja-1")
 				return
 			}
 
@@ -258,3 +341,102 @@ func TestProductionOrderGateway_UpdateValue(t *testing.T) {
 		})
 	}
 }
+
+func TestProductionOrderGateway_ItemDecoding(t *testing.T) {
+	burger := entities.ProductionOrderItem{Name: "X-Burguer", Category: "LANCHE", Quantity: 2}
+	order := entities.ProductionOrder{
+		StoreId:   "loja-1",
+		OrderId:   1,
+		Status:    "EM_PREPARACAO",
+		Priority:  "URGENTE",
+		CreatedAt: time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+		Items:     []entities.ProductionOrderItem{burger},
+		Tickets: []entities.StationTicket{
+			{
+				Station: "grill",
+				Status:  "EM_PREPARACAO",
+				Items:   []entities.ProductionOrderItem{burger},
+			},
+		},
+		History: []entities.StatusTransition{
+			{Status: "RECEBIDO", At: time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC)},
+			{Status: "EM_PREPARACAO", At: time.Date(2024, time.October, 10, 12, 5, 0, 0, time.UTC)},
+		},
+	}
+
+	item, err := dynamo.MarshalItem(order)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.AttributeValueMemberS{Value: "loja-1"}, item["StoreID"])
+	assert.Equal(t, &types.AttributeValueMemberN{Value: "1"}, item["ID"])
+
+	var decoded entities.ProductionOrder
+	assert.NoError(t, dynamo.UnmarshalItem(item, &decoded))
+	assert.Equal(t, order, decoded)
+
+	item["ID"] = &types.AttributeValueMemberS{Value: "not-a-number"}
+	item["CreatedAt"] = &types.AttributeValueMemberS{Value: "yesterday"}
+
+	assert.Error(t, dynamo.UnmarshalItem(item, &decoded))
+}
+
+func TestProductionOrderGateway_GetPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
+	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
+
+	response := []entities.ProductionOrder{{StoreId: "loja-1", OrderId: 1, Status: "RECEBIDO"}}
+	mockAdapter.EXPECT().GetPageByKey("StoreID", "loja-1", 10, "cursor").Return(response, "next", nil).Times(1)
+
+	got, next, err := NewProductionOrderGateway(mockAdapter, "production_order").GetPage("loja-1", 10, "cursor")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{{StoreId: "loja-1", OrderId: 1, Status: "RECEBIDO"}}, got)
+	assert.Equal(t, "next", next)
+
+	mockAdapter.EXPECT().GetPageByKey("StoreID", "loja-1", 10, "").Return(nil, "", errors.New("teste")).Times(1)
+
+	got, next, err = NewProductionOrderGateway(mockAdapter, "production_order").GetPage("loja-1", 10, "")
+
+	assert.Error(t, err)
+	assert.Equal(t, []entities.ProductionOrder{}, got)
+	assert.Equal(t, "", next)
+}
+
+func TestProductionOrderGateway_GetPageDescending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
+	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
+
+	response := []entities.ProductionOrder{{StoreId: "loja-1", OrderId: 2, Status: "RECEBIDO"}}
+	mockAdapter.EXPECT().GetPageByKeyDescending("StoreID", "loja-1", 10, "").Return(response, "next", nil).Times(1)
+
+	got, next, err := NewProductionOrderGateway(mockAdapter, "production_order").GetPageDescending("loja-1", 10, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response, got)
+	assert.Equal(t, "next", next)
+}
+
+func TestProductionOrderGateway_GetAllByStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockAdapter := mock_external.NewMockDynamoAdapter[entities.ProductionOrder](ctrl)
+	mockAdapter.EXPECT().SetTable(gomock.Any()).AnyTimes().Return()
+
+	response := []entities.ProductionOrder{{StoreId: "loja-2", OrderId: 1, Status: "RECEBIDO"}}
+	mockAdapter.EXPECT().GetAllByKey("StoreID", "loja-2").Return(response, nil).Times(1)
+
+	got, err := NewProductionOrderGateway(mockAdapter, "production_order").GetAllByStore("loja-2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{{StoreId: "loja-2", OrderId: 1, Status: "RECEBIDO"}}, got)
+
+	mockAdapter.EXPECT().GetAllByKey("StoreID", "loja-2").Return(nil, errors.New("teste")).Times(1)
+
+	got, err = NewProductionOrderGateway(mockAdapter, "production_order").GetAllByStore("loja-2")
+
+	assert.Error(t, err)
+	assert.Equal(t, []entities.ProductionOrder{}, got)
+}
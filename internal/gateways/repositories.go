@@ -0,0 +1,173 @@
+package gateways
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+// Repositories are the repositories of the configured database, along with the
+// check telling readiness whether the database can be reached.
+type Repositories struct {
+	ProductionOrders repository.ProductionOrderRepository
+	Audit            repository.AuditRepository
+	Webhooks         repository.WebhookRepository
+	Ready            func() bool
+	// CheckTables fails when the tables of the database cannot be read or are
+	// not the ones the service expects. It is nil for databases whose schema
+	// is migrated by the service.
+	CheckTables func() error
+
+	// Outbox queues the changes of a store node syncing with the central
+	// database, whose repositories are in Central. Both are nil unless sync is
+	// enabled.
+	Outbox  repository.SyncOutboxRepository
+	Central *Repositories
+}
+
+// NewRepositories connects to the database chosen by the database driver.
+func NewRepositories(cfg external.Config) Repositories {
+	var repositories Repositories
+
+	switch cfg.DatabaseConfig.Driver {
+	case external.POSTGRES_DATABASE_DRIVER:
+		db := external.ConectaPostgres(cfg)
+
+		repositories = Repositories{
+			ProductionOrders: NewProductionOrderPostgresGateway(db),
+			Audit:            NewAuditPostgresGateway(db),
+			Webhooks:         NewWebhookPostgresGateway(db),
+			Ready:            pingReady(db),
+			Outbox:           NewSyncOutboxPostgresGateway(db),
+		}
+	case external.SQLITE_DATABASE_DRIVER:
+		db := external.ConectaSQLite(cfg)
+
+		repositories = Repositories{
+			ProductionOrders: NewProductionOrderSQLiteGateway(db),
+			Audit:            NewAuditSQLiteGateway(db),
+			Webhooks:         NewWebhookSQLiteGateway(db),
+			Ready:            pingReady(db),
+			Outbox:           NewSyncOutboxSQLiteGateway(db),
+		}
+	default:
+		repositories = newDynamoRepositories(cfg)
+		err := repositories.CheckTables()
+
+		if err != nil {
+			log.Println(err.Error())
+			log.Panic("Tabela de pedidos incompativel com o servico")
+		}
+
+		return repositories
+	}
+
+	if !cfg.SyncConfig.Enabled {
+		repositories.Outbox = nil
+		return repositories
+	}
+
+	// The central database is only checked once the node syncs with it, so a
+	// node started offline keeps running on its local database. A central
+	// table found incompatible makes the node unready until it is fixed.
+	central := newDynamoRepositories(cfg)
+	check := &tableCheck{check: central.CheckTables}
+	central.CheckTables = check.run
+	localReady := repositories.Ready
+	repositories.Ready = func() bool {
+		return localReady() && check.compatible()
+	}
+	repositories.Central = &central
+	repositories.ProductionOrders = NewOutboxProductionOrderGateway(repositories.ProductionOrders, repositories.Outbox)
+	repositories.Audit = NewOutboxAuditGateway(repositories.Audit, repositories.Outbox)
+
+	return repositories
+}
+
+// NewMemoryRepositories keeps everything in memory, which lets tests run the
+// whole service without a database.
+func NewMemoryRepositories() Repositories {
+	return Repositories{
+		ProductionOrders: NewProductionOrderMemoryGateway(),
+		Audit:            NewAuditMemoryGateway(),
+		Webhooks:         NewWebhookMemoryGateway(),
+		Ready: func() bool {
+			return true
+		},
+	}
+}
+
+func newDynamoRepositories(cfg external.Config) Repositories {
+	database := external.ConectaDB(cfg)
+	breaker := external.NewCircuitBreaker(cfg.DynamoConfig)
+
+	return Repositories{
+		ProductionOrders: NewProductionOrderGateway(
+			external.NewResilientDynamoAdapter(external.NewDynamoAdapter[entities.ProductionOrder](database), cfg.DynamoConfig, breaker),
+			cfg.DynamoConfig.ProductionOrderTable(),
+		),
+		Audit: NewAuditGateway(
+			external.NewResilientDynamoAdapter(external.NewDynamoAdapter[entities.AuditEntry](database), cfg.DynamoConfig, breaker),
+			cfg.DynamoConfig.AuditTable(),
+		),
+		Webhooks: NewWebhookGateway(
+			external.NewResilientDynamoAdapter(external.NewDynamoAdapter[entities.WebhookSubscription](database), cfg.DynamoConfig, breaker),
+			cfg.DynamoConfig.WebhookTable(),
+			external.NewResilientDynamoAdapter(external.NewDynamoAdapter[entities.WebhookDelivery](database), cfg.DynamoConfig, breaker),
+			cfg.DynamoConfig.WebhookDeliveryTable(),
+		),
+		Ready: breaker.Healthy,
+		CheckTables: func() error {
+			return external.CheckProductionOrderTable(database, cfg.DynamoConfig)
+		},
+	}
+}
+
+// tableCheck runs a check of the tables until it passes once, remembering
+// whether the last failure found an incompatible table rather than a table
+// that could not be read.
+type tableCheck struct {
+	check func() error
+
+	mu           sync.Mutex
+	passed       bool
+	incompatible bool
+}
+
+func (t *tableCheck) run() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.passed {
+		return nil
+	}
+
+	err := t.check()
+	t.passed = err == nil
+	t.incompatible = errors.Is(err, external.ErrIncompatibleTable)
+
+	return err
+}
+
+func (t *tableCheck) compatible() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return !t.incompatible
+}
+
+func pingReady(db *sql.DB) func() bool {
+	return func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		return db.PingContext(ctx) == nil
+	}
+}
@@ -0,0 +1,36 @@
+package gateways
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableCheck(t *testing.T) {
+	results := []error{
+		errors.New("no route to host"),
+		fmt.Errorf("%w: table production_order has no OpenOrders index", external.ErrIncompatibleTable),
+		nil,
+	}
+	calls := 0
+	check := &tableCheck{check: func() error {
+		err := results[calls]
+		calls++
+		return err
+	}}
+
+	assert.EqualError(t, check.run(), "no route to host")
+	assert.True(t, check.compatible(), "an unreachable table is not known to be incompatible")
+
+	assert.ErrorIs(t, check.run(), external.ErrIncompatibleTable)
+	assert.False(t, check.compatible())
+
+	assert.NoError(t, check.run())
+	assert.True(t, check.compatible())
+
+	assert.NoError(t, check.run())
+	assert.Equal(t, 3, calls, "a check that passed is not run again")
+}
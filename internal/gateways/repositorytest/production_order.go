@@ -0,0 +1,532 @@
+// Package repositorytest holds the contract every storage backend of the
+// service must honour, so the backends cannot drift apart.
+package repositorytest
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ProductionOrderContract describes a backend of
+// repository.ProductionOrderRepository to be run against the contract.
+type ProductionOrderContract struct {
+	// NewRepository returns the repository under test. Backends shared between
+	// tests, such as a database, may keep older orders: every test works on a
+	// store of its own.
+	NewRepository func(t *testing.T) repository.ProductionOrderRepository
+	// Versioned backends reject an update of an order changed since it was
+	// read.
+	Versioned bool
+}
+
+var storeSequence atomic.Int64
+
+// newStoreId returns a store no other test of the process uses.
+func newStoreId() string {
+	return fmt.Sprintf("contract-%d-%d", time.Now().UnixNano(), storeSequence.Add(1))
+}
+
+var receivedAt = time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC)
+
+func newOrder(storeId string, orderId uint32) entities.ProductionOrder {
+	burger := entities.ProductionOrderItem{Name: "X-Burguer", Category: "LANCHE", Quantity: 2}
+
+	return entities.ProductionOrder{
+		StoreId:   storeId,
+		OrderId:   orderId,
+		Status:    entities.RECEIVED_STATUS,
+		Priority:  entities.NORMAL_PRIORITY,
+		CreatedAt: receivedAt,
+		Items:     []entities.ProductionOrderItem{burger},
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: entities.RECEIVED_STATUS, Items: []entities.ProductionOrderItem{burger}},
+		},
+		History: []entities.StatusTransition{
+			{Status: entities.RECEIVED_STATUS, At: receivedAt},
+		},
+	}
+}
+
+// normalized leaves out what backends may store differently: the version, the
+// time zone of the timestamps and nil instead of empty lists.
+func normalized(order entities.ProductionOrder) entities.ProductionOrder {
+	order.Version = 0
+	order.OpenStoreId = ""
+	order.CreatedAt = order.CreatedAt.UTC()
+
+	history := []entities.StatusTransition{}
+	for _, transition := range order.History {
+		transition.At = transition.At.UTC()
+		history = append(history, transition)
+	}
+	order.History = history
+
+	if order.Items == nil {
+		order.Items = []entities.ProductionOrderItem{}
+	}
+
+	if order.Tickets == nil {
+		order.Tickets = []entities.StationTicket{}
+	}
+
+	return order
+}
+
+func normalizedList(orders []entities.ProductionOrder) []entities.ProductionOrder {
+	normalizedOrders := []entities.ProductionOrder{}
+	for _, order := range orders {
+		normalizedOrders = append(normalizedOrders, normalized(order))
+	}
+
+	return normalizedOrders
+}
+
+// Run runs every clause of the contract as a subtest.
+func (c ProductionOrderContract) Run(t *testing.T) {
+	t.Run("create stores the order", c.testCreate)
+	t.Run("create fails for an order with the same id", c.testDuplicateCreate)
+	t.Run("get returns nil without error for a missing order", c.testGetMissing)
+	t.Run("get open leaves out finished and canceled orders", c.testGetOpen)
+	t.Run("update changes the order", c.testUpdate)
+	t.Run("update keeps what is left empty", c.testUpdateKeepsEmptyFields)
+	t.Run("update fails for a missing order", c.testUpdateMissing)
+	t.Run("update all changes every order", c.testUpdateAll)
+	t.Run("update all changes nothing when an order is missing", c.testUpdateAllMissing)
+	t.Run("list returns the orders of the store by id", c.testList)
+	t.Run("pages cover the orders of the store once", c.testPages)
+	t.Run("descending pages cover the orders of the store from the last one", c.testDescendingPages)
+	t.Run("concurrent writes are all stored", c.testConcurrentWrites)
+
+	if c.Versioned {
+		t.Run("update fails for an order changed since it was read", c.testStaleUpdate)
+		t.Run("concurrent updates of an order read once store only one", c.testConcurrentSameOrderUpdates)
+	}
+}
+
+func (c ProductionOrderContract) testCreate(t *testing.T) {
+	repo := c.NewRepository(t)
+	order := newOrder(newStoreId(), 1)
+
+	created, err := repo.Create(order)
+
+	require.NoError(t, err)
+	assert.Equal(t, normalized(order), normalized(*created))
+
+	got, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, normalized(order), normalized(*got))
+}
+
+func (c ProductionOrderContract) testDuplicateCreate(t *testing.T) {
+	repo := c.NewRepository(t)
+	order := newOrder(newStoreId(), 1)
+
+	_, err := repo.Create(order)
+	require.NoError(t, err)
+
+	duplicate := order
+	duplicate.Priority = entities.RUSH_PRIORITY
+	created, err := repo.Create(duplicate)
+
+	assert.EqualError(t, err, fmt.Sprintf("production order 1 already exists in store %s", order.StoreId))
+	assert.Nil(t, created)
+
+	got, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, normalized(order), normalized(*got), "a duplicate create must not overwrite the order")
+}
+
+func (c ProductionOrderContract) testGetMissing(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId := newStoreId()
+
+	_, err := repo.Create(newOrder(storeId, 1))
+	require.NoError(t, err)
+
+	got, err := repo.GetByOrderId(storeId, 2)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = repo.GetByOrderId(newStoreId(), 1)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func (c ProductionOrderContract) testUpdate(t *testing.T) {
+	repo := c.NewRepository(t)
+	order := newOrder(newStoreId(), 1)
+
+	_, err := repo.Create(order)
+	require.NoError(t, err)
+
+	read, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+	require.NoError(t, err)
+
+	read.Priority = entities.HIGH_PRIORITY
+	read.Tickets[0].Status = entities.DONE_STATUS
+	read.ChangeStatus(entities.IN_PREPARATION_STATUS, receivedAt.Add(time.Minute))
+	read.ChangeStatus(entities.DONE_STATUS, receivedAt.Add(5*time.Minute))
+
+	updated, err := repo.Update(*read)
+
+	require.NoError(t, err)
+	assert.Equal(t, normalized(*read), normalized(*updated))
+
+	got, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+
+	require.NoError(t, err)
+	assert.Equal(t, normalized(*read), normalized(*got))
+}
+
+func (c ProductionOrderContract) testUpdateKeepsEmptyFields(t *testing.T) {
+	repo := c.NewRepository(t)
+	order := newOrder(newStoreId(), 1)
+
+	_, err := repo.Create(order)
+	require.NoError(t, err)
+
+	updated, err := repo.Update(entities.ProductionOrder{
+		StoreId: order.StoreId,
+		OrderId: order.OrderId,
+		Status:  entities.IN_PREPARATION_STATUS,
+	})
+
+	require.NoError(t, err)
+
+	want := normalized(order)
+	want.Status = entities.IN_PREPARATION_STATUS
+	assert.Equal(t, want, normalized(*updated))
+}
+
+func (c ProductionOrderContract) testUpdateMissing(t *testing.T) {
+	repo := c.NewRepository(t)
+	order := newOrder(newStoreId(), 1)
+
+	updated, err := repo.Update(order)
+
+	assert.EqualError(t, err, fmt.Sprintf("production order 1 not found in store %s", order.StoreId))
+	assert.Nil(t, updated)
+
+	got, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got, "an update must never create the order")
+}
+
+func (c ProductionOrderContract) testUpdateAll(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId := newStoreId()
+
+	orders := []entities.ProductionOrder{}
+	for _, orderId := range []uint32{1, 2} {
+		_, err := repo.Create(newOrder(storeId, orderId))
+		require.NoError(t, err)
+
+		read, err := repo.GetByOrderId(storeId, orderId)
+		require.NoError(t, err)
+
+		read.ChangeStatus(entities.IN_PREPARATION_STATUS, receivedAt.Add(time.Minute))
+		orders = append(orders, *read)
+	}
+
+	updated, err := repo.UpdateAll(orders)
+
+	require.NoError(t, err)
+	assert.Equal(t, normalizedList(orders), normalizedList(updated))
+
+	stored, err := repo.GetAllByStore(storeId)
+
+	require.NoError(t, err)
+	assert.Equal(t, normalizedList(orders), normalizedList(stored))
+}
+
+func (c ProductionOrderContract) testUpdateAllMissing(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId := newStoreId()
+	order := newOrder(storeId, 1)
+
+	_, err := repo.Create(order)
+	require.NoError(t, err)
+
+	changed := order
+	changed.ChangeStatus(entities.IN_PREPARATION_STATUS, receivedAt.Add(time.Minute))
+	missing := newOrder(storeId, 2)
+	missing.ChangeStatus(entities.IN_PREPARATION_STATUS, receivedAt.Add(time.Minute))
+
+	updated, err := repo.UpdateAll([]entities.ProductionOrder{changed, missing})
+
+	assert.Error(t, err)
+	assert.Nil(t, updated)
+
+	stored, err := repo.GetAllByStore(storeId)
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{normalized(order)}, normalizedList(stored))
+}
+
+func (c ProductionOrderContract) testList(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId, otherStoreId := newStoreId(), newStoreId()
+
+	for _, orderId := range []uint32{3, 1, 2} {
+		_, err := repo.Create(newOrder(storeId, orderId))
+		require.NoError(t, err)
+	}
+
+	_, err := repo.Create(newOrder(otherStoreId, 1))
+	require.NoError(t, err)
+
+	orders, err := repo.GetAllByStore(storeId)
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{
+		normalized(newOrder(storeId, 1)),
+		normalized(newOrder(storeId, 2)),
+		normalized(newOrder(storeId, 3)),
+	}, normalizedList(orders))
+
+	orders, err = repo.GetAllByStore(newStoreId())
+
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+
+}
+
+func (c ProductionOrderContract) testGetOpen(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId, otherStoreId := newStoreId(), newStoreId()
+
+	_, err := repo.Create(newOrder(storeId, 1))
+	require.NoError(t, err)
+
+	_, err = repo.Create(newOrder(otherStoreId, 1))
+	require.NoError(t, err)
+
+	finished := newOrder(storeId, 2)
+	finished.Status = entities.FINISHED_STATUS
+	_, err = repo.Create(finished)
+	require.NoError(t, err)
+
+	canceled, err := repo.Create(newOrder(storeId, 3))
+	require.NoError(t, err)
+	canceled.Status = entities.CANCELED_STATUS
+	_, err = repo.Update(*canceled)
+	require.NoError(t, err)
+
+	orders, err := repo.GetOpen()
+
+	require.NoError(t, err)
+	assert.Subset(t, normalizedList(orders), []entities.ProductionOrder{
+		normalized(newOrder(storeId, 1)),
+		normalized(newOrder(otherStoreId, 1)),
+	})
+
+	for _, order := range orders {
+		if order.StoreId == storeId {
+			assert.Equal(t, uint32(1), order.OrderId, "closed order %d returned as open", order.OrderId)
+		}
+	}
+}
+
+func (c ProductionOrderContract) testPages(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId := newStoreId()
+
+	for orderId := uint32(1); orderId <= 5; orderId++ {
+		_, err := repo.Create(newOrder(storeId, orderId))
+		require.NoError(t, err)
+	}
+
+	orderIds := []uint32{}
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		orders, nextCursor, err := repo.GetPage(storeId, 2, cursor)
+
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(orders), 2)
+
+		for _, order := range orders {
+			orderIds = append(orderIds, order.OrderId)
+		}
+
+		if nextCursor == "" {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	assert.Equal(t, []uint32{1, 2, 3, 4, 5}, orderIds)
+
+	_, _, err := repo.GetPage(storeId, 2, "not a cursor")
+
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+}
+
+func (c ProductionOrderContract) testDescendingPages(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId := newStoreId()
+
+	for orderId := uint32(1); orderId <= 5; orderId++ {
+		_, err := repo.Create(newOrder(storeId, orderId))
+		require.NoError(t, err)
+	}
+
+	_, err := repo.Create(newOrder(newStoreId(), 9))
+	require.NoError(t, err)
+
+	orderIds := []uint32{}
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		orders, nextCursor, err := repo.GetPageDescending(storeId, 2, cursor)
+
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(orders), 2)
+
+		for _, order := range orders {
+			orderIds = append(orderIds, order.OrderId)
+		}
+
+		if nextCursor == "" {
+			break
+		}
+
+		cursor = nextCursor
+	}
+
+	assert.Equal(t, []uint32{5, 4, 3, 2, 1}, orderIds)
+
+	_, _, err = repo.GetPageDescending(storeId, 2, "not a cursor")
+
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+}
+
+func (c ProductionOrderContract) testConcurrentWrites(t *testing.T) {
+	repo := c.NewRepository(t)
+	storeId := newStoreId()
+	const orders = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*orders)
+	for orderId := uint32(1); orderId <= orders; orderId++ {
+		wg.Add(1)
+		go func(orderId uint32) {
+			defer wg.Done()
+
+			order := newOrder(storeId, orderId)
+			_, err := repo.Create(order)
+			errs <- err
+
+			order.ChangeStatus(entities.IN_PREPARATION_STATUS, receivedAt.Add(time.Minute))
+			_, err = repo.Update(order)
+			errs <- err
+		}(orderId)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	stored, err := repo.GetAllByStore(storeId)
+
+	require.NoError(t, err)
+	assert.Len(t, stored, orders)
+
+	for _, order := range stored {
+		assert.Equal(t, entities.IN_PREPARATION_STATUS, order.Status)
+		assert.Len(t, order.History, 2)
+	}
+}
+
+func (c ProductionOrderContract) testStaleUpdate(t *testing.T) {
+	repo := c.NewRepository(t)
+	order := newOrder(newStoreId(), 1)
+
+	_, err := repo.Create(order)
+	require.NoError(t, err)
+
+	first, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+	require.NoError(t, err)
+	second, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+	require.NoError(t, err)
+
+	first.ChangeStatus(entities.IN_PREPARATION_STATUS, receivedAt.Add(time.Minute))
+	_, err = repo.Update(*first)
+	require.NoError(t, err)
+
+	second.ChangeStatus(entities.CANCELED_STATUS, receivedAt.Add(2*time.Minute))
+	_, err = repo.Update(*second)
+
+	assert.EqualError(t, err, fmt.Sprintf("production order 1 of store %s was changed by another request, read it again", order.StoreId))
+
+	got, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.IN_PREPARATION_STATUS, got.Status)
+}
+
+func (c ProductionOrderContract) testConcurrentSameOrderUpdates(t *testing.T) {
+	repo := c.NewRepository(t)
+	order := newOrder(newStoreId(), 1)
+	const writers = 10
+
+	_, err := repo.Create(order)
+	require.NoError(t, err)
+
+	read, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var applied atomic.Int64
+	errs := make(chan error, writers)
+	for writer := 0; writer < writers; writer++ {
+		wg.Add(1)
+		go func(changedAt time.Time) {
+			defer wg.Done()
+
+			change := *read
+			change.History = append([]entities.StatusTransition{}, read.History...)
+			change.ChangeStatus(entities.IN_PREPARATION_STATUS, changedAt)
+
+			_, err := repo.Update(change)
+			if err == nil {
+				applied.Add(1)
+				return
+			}
+
+			errs <- err
+		}(receivedAt.Add(time.Duration(writer+1) * time.Minute))
+	}
+
+	wg.Wait()
+	close(errs)
+
+	assert.Equal(t, int64(1), applied.Load(), "only one of the updates of the same version may be stored")
+
+	for err := range errs {
+		assert.EqualError(t, err, fmt.Sprintf("production order 1 of store %s was changed by another request, read it again", order.StoreId))
+	}
+
+	got, err := repo.GetByOrderId(order.StoreId, order.OrderId)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.IN_PREPARATION_STATUS, got.Status)
+	assert.Len(t, got.History, 2)
+}
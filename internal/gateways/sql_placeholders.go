@@ -0,0 +1,16 @@
+package gateways
+
+import "regexp"
+
+var numberedPlaceholder = regexp.MustCompile(`\$(\d+)`)
+
+// postgresPlaceholders keeps the $1 placeholders the queries are written with.
+func postgresPlaceholders(query string) string {
+	return query
+}
+
+// sqlitePlaceholders rewrites $1 placeholders as ?1, which SQLite binds by
+// number. SQLite takes $1 as a named parameter instead.
+func sqlitePlaceholders(query string) string {
+	return numberedPlaceholder.ReplaceAllString(query, "?$1")
+}
@@ -0,0 +1,136 @@
+package gateways
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+// outboxProductionOrderGateway queues every order stored on a store node to be
+// synced to the central database, for repositories that cannot queue it in the
+// transaction storing it. The change is already stored when it is queued, so a
+// failure to queue it is returned to tell the caller the order may not sync.
+type outboxProductionOrderGateway struct {
+	repository.ProductionOrderRepository
+	outbox repository.SyncOutboxRepository
+}
+
+func (o outboxProductionOrderGateway) Create(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+	created, err := o.ProductionOrderRepository.Create(order)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.enqueue(*created)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (o outboxProductionOrderGateway) Update(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+	updated, err := o.ProductionOrderRepository.Update(order)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.enqueue(*updated)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (o outboxProductionOrderGateway) UpdateAll(orders []entities.ProductionOrder) ([]entities.ProductionOrder, error) {
+	updatedOrders, err := o.ProductionOrderRepository.UpdateAll(orders)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range updatedOrders {
+		err = o.enqueue(order)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedOrders, nil
+}
+
+func (o outboxProductionOrderGateway) enqueue(order entities.ProductionOrder) error {
+	err := o.outbox.Enqueue(orderSyncChange(order))
+
+	if err != nil {
+		return fmt.Errorf("production order %d of store %s was stored but could not be queued to be synced: %w", order.OrderId, order.StoreId, err)
+	}
+
+	return nil
+}
+
+func orderSyncChange(order entities.ProductionOrder) entities.SyncChange {
+	return entities.SyncChange{
+		Kind:     entities.ORDER_SYNC_CHANGE,
+		QueuedAt: time.Now(),
+		Order:    &order,
+	}
+}
+
+// outboxAuditGateway queues every audit entry appended on a store node to be
+// synced to the central database.
+type outboxAuditGateway struct {
+	repository.AuditRepository
+	outbox repository.SyncOutboxRepository
+}
+
+func (o outboxAuditGateway) Append(entry entities.AuditEntry) error {
+	err := o.AuditRepository.Append(entry)
+
+	if err != nil {
+		return err
+	}
+
+	return o.outbox.Enqueue(entities.SyncChange{
+		Kind:     entities.AUDIT_SYNC_CHANGE,
+		QueuedAt: time.Now(),
+		Audit:    &entry,
+	})
+}
+
+// NewOutboxProductionOrderGateway decorates the repository of a store node so
+// the orders it stores are queued in the outbox. A SQL repository with the
+// outbox in the same database queues each order in the transaction storing it,
+// so an order is never stored without being queued.
+func NewOutboxProductionOrderGateway(productionOrderRepository repository.ProductionOrderRepository, outbox repository.SyncOutboxRepository) repository.ProductionOrderRepository {
+	sqlRepository, isSQL := productionOrderRepository.(*productionOrderSQLGateway)
+	sqlOutbox, isSQLOutbox := outbox.(*syncOutboxSQLGateway)
+
+	if isSQL && isSQLOutbox && sqlRepository.db == sqlOutbox.db {
+		synced := *sqlRepository
+		synced.outbox = sqlOutbox
+		return &synced
+	}
+
+	return &outboxProductionOrderGateway{
+		ProductionOrderRepository: productionOrderRepository,
+		outbox:                    outbox,
+	}
+}
+
+// NewOutboxAuditGateway decorates the audit repository of a store node so the
+// entries it appends are queued in the outbox.
+func NewOutboxAuditGateway(auditRepository repository.AuditRepository, outbox repository.SyncOutboxRepository) repository.AuditRepository {
+	return &outboxAuditGateway{
+		AuditRepository: auditRepository,
+		outbox:          outbox,
+	}
+}
@@ -0,0 +1,144 @@
+package gateways
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+// syncOutboxPayload is the change stored in the outbox. Audit entries leave
+// their keys out of their JSON, so the keys are stored beside them.
+type syncOutboxPayload struct {
+	Order         *entities.ProductionOrder `json:",omitempty"`
+	Audit         *entities.AuditEntry      `json:",omitempty"`
+	AuditOrderKey string                    `json:",omitempty"`
+	AuditEntryId  string                    `json:",omitempty"`
+}
+
+// syncOutboxSQLGateway queues the changes of a store node in the sync_outbox
+// table of its local database. Rejected changes stay in the table, with the
+// reason, for someone to look at.
+type syncOutboxSQLGateway struct {
+	db   *sql.DB
+	bind func(query string) string
+}
+
+func (s syncOutboxSQLGateway) Enqueue(change entities.SyncChange) error {
+	return s.enqueue(s.db, change)
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// enqueue queues the change through db, which is the transaction storing the
+// order when the order is queued along with it.
+func (s syncOutboxSQLGateway) enqueue(db execer, change entities.SyncChange) error {
+	payload := syncOutboxPayload{
+		Order: change.Order,
+		Audit: change.Audit,
+	}
+
+	storeId, orderId := "", uint32(0)
+	if change.Order != nil {
+		storeId, orderId = change.Order.StoreId, change.Order.OrderId
+	}
+
+	if change.Audit != nil {
+		storeId, orderId = change.Audit.StoreId, change.Audit.OrderId
+		payload.AuditOrderKey = change.Audit.OrderKey
+		payload.AuditEntryId = change.Audit.EntryId
+	}
+
+	data, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(s.bind(`INSERT INTO sync_outbox (kind, store_id, order_id, payload, queued_at)
+		VALUES ($1, $2, $3, $4, $5)`),
+		change.Kind, storeId, orderId, data, change.QueuedAt,
+	)
+
+	return err
+}
+
+// GetPending reads the changes not rejected yet, oldest first.
+func (s syncOutboxSQLGateway) GetPending(limit int) ([]entities.SyncChange, error) {
+	rows, err := s.db.Query(s.bind(`SELECT id, kind, payload, queued_at
+		FROM sync_outbox
+		WHERE rejected_reason = ''
+		ORDER BY id
+		LIMIT $1`),
+		limit,
+	)
+
+	if err != nil {
+		return []entities.SyncChange{}, err
+	}
+
+	defer rows.Close()
+
+	changes := []entities.SyncChange{}
+	for rows.Next() {
+		change := entities.SyncChange{}
+		var data []byte
+
+		err = rows.Scan(&change.Id, &change.Kind, &data, &change.QueuedAt)
+
+		if err != nil {
+			return []entities.SyncChange{}, err
+		}
+
+		payload := syncOutboxPayload{}
+		err = json.Unmarshal(data, &payload)
+
+		if err != nil {
+			return []entities.SyncChange{}, fmt.Errorf("invalid payload of sync change %d: %w", change.Id, err)
+		}
+
+		change.Order = payload.Order
+		change.Audit = payload.Audit
+
+		if change.Audit != nil {
+			change.Audit.OrderKey = payload.AuditOrderKey
+			change.Audit.EntryId = payload.AuditEntryId
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []entities.SyncChange{}, err
+	}
+
+	return changes, nil
+}
+
+func (s syncOutboxSQLGateway) Remove(id int64) error {
+	_, err := s.db.Exec(s.bind("DELETE FROM sync_outbox WHERE id = $1"), id)
+	return err
+}
+
+func (s syncOutboxSQLGateway) Reject(id int64, reason string) error {
+	_, err := s.db.Exec(s.bind("UPDATE sync_outbox SET rejected_reason = $2 WHERE id = $1"), id, reason)
+	return err
+}
+
+func NewSyncOutboxPostgresGateway(db *sql.DB) repository.SyncOutboxRepository {
+	return &syncOutboxSQLGateway{
+		db:   db,
+		bind: postgresPlaceholders,
+	}
+}
+
+func NewSyncOutboxSQLiteGateway(db *sql.DB) repository.SyncOutboxRepository {
+	return &syncOutboxSQLGateway{
+		db:   db,
+		bind: sqlitePlaceholders,
+	}
+}
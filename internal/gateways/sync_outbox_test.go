@@ -0,0 +1,110 @@
+package gateways
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxGateways(t *testing.T) {
+	db := openSQLite(t)
+	outbox := NewSyncOutboxSQLiteGateway(db)
+	orders := NewOutboxProductionOrderGateway(NewProductionOrderSQLiteGateway(db), outbox)
+	audit := NewOutboxAuditGateway(NewAuditSQLiteGateway(db), outbox)
+
+	order := postgresOrder("loja-1", 1, "RECEBIDO", 0)
+	created, err := orders.Create(order)
+	assert.NoError(t, err)
+	queued := *created
+	queued.Version = 0
+
+	created.ChangeStatus("EM_PREPARACAO", time.Date(2024, time.October, 10, 12, 5, 0, 0, time.UTC))
+	updated, err := orders.Update(*created)
+	assert.NoError(t, err)
+
+	entry := entities.NewAuditEntry(*updated, entities.STATUS_CHANGED_AUDIT_ACTION, "RECEBIDO", "EM_PREPARACAO", entities.Actor{Name: "cozinheiro"}, time.Date(2024, time.October, 10, 12, 5, 0, 0, time.UTC))
+	assert.NoError(t, audit.Append(entry))
+
+	_, err = orders.Update(postgresOrder("loja-1", 9, "PRONTO", 0))
+	assert.Error(t, err)
+
+	changes, err := outbox.GetPending(10)
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, entities.ORDER_SYNC_CHANGE, changes[0].Kind)
+	assert.Equal(t, &queued, changes[0].Order)
+	assert.Equal(t, entities.ORDER_SYNC_CHANGE, changes[1].Kind)
+	assert.Equal(t, "EM_PREPARACAO", changes[1].Order.Status)
+	assert.Equal(t, updated.History, changes[1].Order.History)
+	assert.Equal(t, entities.AUDIT_SYNC_CHANGE, changes[2].Kind)
+	assert.Equal(t, &entry, changes[2].Audit)
+
+	assert.NoError(t, outbox.Remove(changes[0].Id))
+	assert.NoError(t, outbox.Reject(changes[1].Id, "cant move from CANCELADO to EM_PREPARACAO"))
+
+	changes, err = outbox.GetPending(10)
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, entities.AUDIT_SYNC_CHANGE, changes[0].Kind)
+
+	var rejected string
+	assert.NoError(t, db.QueryRow("SELECT rejected_reason FROM sync_outbox WHERE rejected_reason <> ''").Scan(&rejected))
+	assert.Equal(t, "cant move from CANCELADO to EM_PREPARACAO", rejected)
+}
+
+func TestOutboxGateways_UpdateAll(t *testing.T) {
+	db := openSQLite(t)
+	outbox := NewSyncOutboxSQLiteGateway(db)
+	orders := NewOutboxProductionOrderGateway(NewProductionOrderSQLiteGateway(db), outbox)
+
+	_, err := orders.UpdateAll([]entities.ProductionOrder{postgresOrder("loja-1", 9, "PRONTO", 0)})
+	assert.Error(t, err)
+
+	first, err := orders.Create(postgresOrder("loja-1", 1, "RECEBIDO", 0))
+	assert.NoError(t, err)
+	second, err := orders.Create(postgresOrder("loja-1", 2, "RECEBIDO", 0))
+	assert.NoError(t, err)
+
+	first.Status, second.Status = "EM_PREPARACAO", "EM_PREPARACAO"
+	_, err = orders.UpdateAll([]entities.ProductionOrder{*first, *second})
+	assert.NoError(t, err)
+
+	changes, err := outbox.GetPending(10)
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 4)
+	assert.Equal(t, "EM_PREPARACAO", changes[2].Order.Status)
+	assert.Equal(t, uint32(1), changes[2].Order.OrderId)
+	assert.Equal(t, "EM_PREPARACAO", changes[3].Order.Status)
+	assert.Equal(t, uint32(2), changes[3].Order.OrderId)
+}
+
+func TestOutboxGateways_QueueFailureKeepsNothing(t *testing.T) {
+	db := openSQLite(t)
+	orders := NewOutboxProductionOrderGateway(NewProductionOrderSQLiteGateway(db), NewSyncOutboxSQLiteGateway(db))
+
+	created, err := orders.Create(postgresOrder("loja-1", 1, "RECEBIDO", 0))
+	assert.NoError(t, err)
+
+	_, err = db.Exec("DROP TABLE sync_outbox")
+	assert.NoError(t, err)
+
+	_, err = orders.Create(postgresOrder("loja-1", 2, "RECEBIDO", 0))
+	assert.Error(t, err)
+
+	created.Status = "EM_PREPARACAO"
+	_, err = orders.Update(*created)
+	assert.Error(t, err)
+
+	missing, err := orders.GetByOrderId("loja-1", 2)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	kept, err := orders.GetByOrderId("loja-1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "RECEBIDO", kept.Status)
+}
@@ -0,0 +1,162 @@
+package gateways
+
+import (
+	"errors"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/guregu/dynamo/v2"
+)
+
+type webhookGateway struct {
+	subscriptions external.DynamoAdapter[entities.WebhookSubscription]
+	deliveries    external.DynamoAdapter[entities.WebhookDelivery]
+}
+
+func (w webhookGateway) CreateSubscription(subscription entities.WebhookSubscription) error {
+	return w.subscriptions.Create(subscription)
+}
+
+func (w webhookGateway) GetSubscription(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	subscription, err := w.subscriptions.GetOneByKeys("StoreId", storeId, "SubscriptionId", subscriptionId)
+
+	if errors.Is(err, dynamo.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (w webhookGateway) GetSubscriptions(storeId string) ([]entities.WebhookSubscription, error) {
+	subscriptions, err := w.subscriptions.GetAllByKey("StoreId", storeId)
+
+	if err != nil {
+		return []entities.WebhookSubscription{}, err
+	}
+
+	if subscriptions == nil {
+		subscriptions = []entities.WebhookSubscription{}
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateSubscription stores whether the subscription is enabled and its
+// failures, unless it was deleted in the meantime.
+func (w webhookGateway) UpdateSubscription(subscription entities.WebhookSubscription) error {
+	_, err := w.subscriptions.UpdateValuesByKeys("StoreId", subscription.StoreId, "SubscriptionId", subscription.SubscriptionId, map[string]interface{}{
+		"Enabled":             subscription.Enabled,
+		"ConsecutiveFailures": subscription.ConsecutiveFailures,
+		"DisabledAt":          subscription.DisabledAt,
+	})
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (w webhookGateway) AddDeliveryFailure(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	subscription, err := w.subscriptions.UpdateByKeys("StoreId", "SubscriptionId", external.KeyedUpdate{
+		ValueKey:      storeId,
+		ValueRangeKey: subscriptionId,
+		ValuesToAdd:   map[string]interface{}{"ConsecutiveFailures": 1},
+	})
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (w webhookGateway) ClearDeliveryFailures(storeId string, subscriptionId string) error {
+	return w.updateState(storeId, subscriptionId, map[string]interface{}{
+		"ConsecutiveFailures": 0,
+	})
+}
+
+func (w webhookGateway) DisableSubscription(storeId string, subscriptionId string, disabledAt time.Time) error {
+	return w.updateState(storeId, subscriptionId, map[string]interface{}{
+		"Enabled":    false,
+		"DisabledAt": disabledAt,
+	})
+}
+
+// updateState sets the values of the subscription, unless it was deleted.
+func (w webhookGateway) updateState(storeId string, subscriptionId string, values map[string]interface{}) error {
+	_, err := w.subscriptions.UpdateValuesByKeys("StoreId", storeId, "SubscriptionId", subscriptionId, values)
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+// DeleteSubscription deletes the subscription along with its delivery log.
+func (w webhookGateway) DeleteSubscription(storeId string, subscriptionId string) error {
+	subscriptionKey := entities.WebhookSubscriptionKey(storeId, subscriptionId)
+	deliveries, err := w.deliveries.GetAllByKey("SubscriptionKey", subscriptionKey)
+
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		err = w.deliveries.DeleteByKeys("SubscriptionKey", subscriptionKey, "DeliveryId", delivery.DeliveryId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.subscriptions.DeleteByKeys("StoreId", storeId, "SubscriptionId", subscriptionId)
+}
+
+// AppendDelivery stores the delivery unless it was already stored.
+func (w webhookGateway) AppendDelivery(delivery entities.WebhookDelivery) error {
+	err := w.deliveries.CreateIfNotExists(delivery, "SubscriptionKey")
+
+	if dynamo.IsCondCheckFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+// GetDeliveries returns the last deliveries of the subscription, the latest
+// first.
+func (w webhookGateway) GetDeliveries(storeId string, subscriptionId string, limit int) ([]entities.WebhookDelivery, error) {
+	deliveries, _, err := w.deliveries.GetPageByKeyDescending("SubscriptionKey", entities.WebhookSubscriptionKey(storeId, subscriptionId), limit, "")
+
+	if err != nil {
+		return []entities.WebhookDelivery{}, err
+	}
+
+	if deliveries == nil {
+		deliveries = []entities.WebhookDelivery{}
+	}
+
+	return deliveries, nil
+}
+
+func NewWebhookGateway(subscriptions external.DynamoAdapter[entities.WebhookSubscription], subscriptionTable string, deliveries external.DynamoAdapter[entities.WebhookDelivery], deliveryTable string) repository.WebhookRepository {
+	subscriptions.SetTable(subscriptionTable)
+	deliveries.SetTable(deliveryTable)
+	return &webhookGateway{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+	}
+}
@@ -0,0 +1,171 @@
+package gateways
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+// webhookMemoryGateway keeps the webhook subscriptions and their deliveries in
+// memory, for tests and demos.
+type webhookMemoryGateway struct {
+	mu            sync.RWMutex
+	subscriptions map[string]entities.WebhookSubscription
+	deliveries    map[string][]entities.WebhookDelivery
+}
+
+func (w *webhookMemoryGateway) CreateSubscription(subscription entities.WebhookSubscription) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscriptions[entities.WebhookSubscriptionKey(subscription.StoreId, subscription.SubscriptionId)] = subscription
+
+	return nil
+}
+
+func (w *webhookMemoryGateway) GetSubscription(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	subscription, ok := w.subscriptions[entities.WebhookSubscriptionKey(storeId, subscriptionId)]
+
+	if !ok {
+		return nil, nil
+	}
+
+	return &subscription, nil
+}
+
+func (w *webhookMemoryGateway) GetSubscriptions(storeId string) ([]entities.WebhookSubscription, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	subscriptions := []entities.WebhookSubscription{}
+	for _, subscription := range w.subscriptions {
+		if subscription.StoreId == storeId {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].SubscriptionId < subscriptions[j].SubscriptionId
+	})
+
+	return subscriptions, nil
+}
+
+// UpdateSubscription stores whether the subscription is enabled and its
+// failures, unless it was deleted in the meantime.
+func (w *webhookMemoryGateway) UpdateSubscription(subscription entities.WebhookSubscription) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := entities.WebhookSubscriptionKey(subscription.StoreId, subscription.SubscriptionId)
+	stored, ok := w.subscriptions[key]
+
+	if !ok {
+		return nil
+	}
+
+	stored.Enabled = subscription.Enabled
+	stored.ConsecutiveFailures = subscription.ConsecutiveFailures
+	stored.DisabledAt = subscription.DisabledAt
+	w.subscriptions[key] = stored
+
+	return nil
+}
+
+func (w *webhookMemoryGateway) AddDeliveryFailure(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := entities.WebhookSubscriptionKey(storeId, subscriptionId)
+	stored, ok := w.subscriptions[key]
+
+	if !ok {
+		return nil, nil
+	}
+
+	stored.ConsecutiveFailures++
+	w.subscriptions[key] = stored
+
+	return &stored, nil
+}
+
+func (w *webhookMemoryGateway) ClearDeliveryFailures(storeId string, subscriptionId string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := entities.WebhookSubscriptionKey(storeId, subscriptionId)
+	stored, ok := w.subscriptions[key]
+
+	if ok {
+		stored.ConsecutiveFailures = 0
+		w.subscriptions[key] = stored
+	}
+
+	return nil
+}
+
+func (w *webhookMemoryGateway) DisableSubscription(storeId string, subscriptionId string, disabledAt time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := entities.WebhookSubscriptionKey(storeId, subscriptionId)
+	stored, ok := w.subscriptions[key]
+
+	if ok {
+		stored.Enabled = false
+		stored.DisabledAt = &disabledAt
+		w.subscriptions[key] = stored
+	}
+
+	return nil
+}
+
+func (w *webhookMemoryGateway) DeleteSubscription(storeId string, subscriptionId string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := entities.WebhookSubscriptionKey(storeId, subscriptionId)
+	delete(w.subscriptions, key)
+	delete(w.deliveries, key)
+
+	return nil
+}
+
+func (w *webhookMemoryGateway) AppendDelivery(delivery entities.WebhookDelivery) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.deliveries[delivery.SubscriptionKey] = append(w.deliveries[delivery.SubscriptionKey], delivery)
+
+	return nil
+}
+
+func (w *webhookMemoryGateway) GetDeliveries(storeId string, subscriptionId string, limit int) ([]entities.WebhookDelivery, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	deliveries := append([]entities.WebhookDelivery{}, w.deliveries[entities.WebhookSubscriptionKey(storeId, subscriptionId)]...)
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].DeliveryId > deliveries[j].DeliveryId
+	})
+
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+func NewWebhookMemoryGateway() repository.WebhookRepository {
+	return &webhookMemoryGateway{
+		subscriptions: map[string]entities.WebhookSubscription{},
+		deliveries:    map[string][]entities.WebhookDelivery{},
+	}
+}
@@ -0,0 +1,245 @@
+package gateways
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+)
+
+type webhookSQLGateway struct {
+	db   *sql.DB
+	bind func(query string) string
+}
+
+func (w webhookSQLGateway) CreateSubscription(subscription entities.WebhookSubscription) error {
+	events, err := json.Marshal(emptyIfNil(subscription.Events))
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.db.Exec(w.bind(`INSERT INTO webhook_subscriptions
+			(store_id, subscription_id, url, events, secret, enabled, consecutive_failures, disabled_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`),
+		subscription.StoreId, subscription.SubscriptionId, subscription.URL, events, subscription.Secret,
+		subscription.Enabled, subscription.ConsecutiveFailures, subscription.DisabledAt, subscription.CreatedAt,
+	)
+
+	return err
+}
+
+func (w webhookSQLGateway) GetSubscription(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	row := w.db.QueryRow(w.bind(`SELECT store_id, subscription_id, url, events, secret, enabled, consecutive_failures, disabled_at, created_at
+		FROM webhook_subscriptions
+		WHERE store_id = $1 AND subscription_id = $2`),
+		storeId, subscriptionId,
+	)
+
+	subscription, err := scanWebhookSubscription(row)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (w webhookSQLGateway) GetSubscriptions(storeId string) ([]entities.WebhookSubscription, error) {
+	rows, err := w.db.Query(w.bind(`SELECT store_id, subscription_id, url, events, secret, enabled, consecutive_failures, disabled_at, created_at
+		FROM webhook_subscriptions
+		WHERE store_id = $1
+		ORDER BY subscription_id`),
+		storeId,
+	)
+
+	if err != nil {
+		return []entities.WebhookSubscription{}, err
+	}
+
+	defer rows.Close()
+
+	subscriptions := []entities.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+
+		if err != nil {
+			return []entities.WebhookSubscription{}, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []entities.WebhookSubscription{}, err
+	}
+
+	return subscriptions, nil
+}
+
+// UpdateSubscription stores whether the subscription is enabled and its
+// failures. Updating a subscription that is not stored is not an error, since
+// it may have been deleted while an event was being delivered to it.
+func (w webhookSQLGateway) UpdateSubscription(subscription entities.WebhookSubscription) error {
+	_, err := w.db.Exec(w.bind(`UPDATE webhook_subscriptions
+		SET enabled = $3, consecutive_failures = $4, disabled_at = $5
+		WHERE store_id = $1 AND subscription_id = $2`),
+		subscription.StoreId, subscription.SubscriptionId,
+		subscription.Enabled, subscription.ConsecutiveFailures, subscription.DisabledAt,
+	)
+
+	return err
+}
+
+func (w webhookSQLGateway) AddDeliveryFailure(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	row := w.db.QueryRow(w.bind(`UPDATE webhook_subscriptions
+		SET consecutive_failures = consecutive_failures + 1
+		WHERE store_id = $1 AND subscription_id = $2
+		RETURNING store_id, subscription_id, url, events, secret, enabled, consecutive_failures, disabled_at, created_at`),
+		storeId, subscriptionId,
+	)
+
+	subscription, err := scanWebhookSubscription(row)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (w webhookSQLGateway) ClearDeliveryFailures(storeId string, subscriptionId string) error {
+	_, err := w.db.Exec(w.bind(`UPDATE webhook_subscriptions
+		SET consecutive_failures = 0
+		WHERE store_id = $1 AND subscription_id = $2 AND consecutive_failures <> 0`),
+		storeId, subscriptionId,
+	)
+
+	return err
+}
+
+func (w webhookSQLGateway) DisableSubscription(storeId string, subscriptionId string, disabledAt time.Time) error {
+	_, err := w.db.Exec(w.bind(`UPDATE webhook_subscriptions
+		SET enabled = $3, disabled_at = $4
+		WHERE store_id = $1 AND subscription_id = $2`),
+		storeId, subscriptionId, false, disabledAt,
+	)
+
+	return err
+}
+
+// DeleteSubscription deletes the subscription along with its delivery log.
+func (w webhookSQLGateway) DeleteSubscription(storeId string, subscriptionId string) error {
+	for _, table := range []string{"webhook_deliveries", "webhook_subscriptions"} {
+		_, err := w.db.Exec(w.bind(`DELETE FROM `+table+` WHERE store_id = $1 AND subscription_id = $2`), storeId, subscriptionId)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w webhookSQLGateway) AppendDelivery(delivery entities.WebhookDelivery) error {
+	_, err := w.db.Exec(w.bind(`INSERT INTO webhook_deliveries
+			(store_id, subscription_id, delivery_id, event_id, event, attempt, at, status_code, error, delivered)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (store_id, subscription_id, delivery_id) DO NOTHING`),
+		delivery.StoreId, delivery.SubscriptionId, delivery.DeliveryId, delivery.EventId, delivery.Event,
+		delivery.Attempt, delivery.At, delivery.StatusCode, delivery.Error, delivery.Delivered,
+	)
+
+	return err
+}
+
+// GetDeliveries returns the last deliveries of the subscription, the latest
+// first.
+func (w webhookSQLGateway) GetDeliveries(storeId string, subscriptionId string, limit int) ([]entities.WebhookDelivery, error) {
+	rows, err := w.db.Query(w.bind(`SELECT store_id, subscription_id, delivery_id, event_id, event, attempt, at, status_code, error, delivered
+		FROM webhook_deliveries
+		WHERE store_id = $1 AND subscription_id = $2
+		ORDER BY delivery_id DESC
+		LIMIT $3`),
+		storeId, subscriptionId, limit,
+	)
+
+	if err != nil {
+		return []entities.WebhookDelivery{}, err
+	}
+
+	defer rows.Close()
+
+	deliveries := []entities.WebhookDelivery{}
+	for rows.Next() {
+		delivery := entities.WebhookDelivery{}
+		err = rows.Scan(
+			&delivery.StoreId, &delivery.SubscriptionId, &delivery.DeliveryId, &delivery.EventId, &delivery.Event,
+			&delivery.Attempt, &delivery.At, &delivery.StatusCode, &delivery.Error, &delivery.Delivered,
+		)
+
+		if err != nil {
+			return []entities.WebhookDelivery{}, err
+		}
+
+		delivery.SubscriptionKey = entities.WebhookSubscriptionKey(delivery.StoreId, delivery.SubscriptionId)
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return []entities.WebhookDelivery{}, err
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhookSubscription(row rowScanner) (subscription entities.WebhookSubscription, err error) {
+	var events []byte
+	var disabledAt sql.NullTime
+
+	err = row.Scan(
+		&subscription.StoreId, &subscription.SubscriptionId, &subscription.URL, &events, &subscription.Secret,
+		&subscription.Enabled, &subscription.ConsecutiveFailures, &disabledAt, &subscription.CreatedAt,
+	)
+
+	if err != nil {
+		return subscription, err
+	}
+
+	if disabledAt.Valid {
+		subscription.DisabledAt = &disabledAt.Time
+	}
+
+	err = json.Unmarshal(events, &subscription.Events)
+
+	if err != nil {
+		return subscription, fmt.Errorf("invalid events of webhook subscription %s: %w", subscription.SubscriptionId, err)
+	}
+
+	return subscription, nil
+}
+
+func NewWebhookPostgresGateway(db *sql.DB) repository.WebhookRepository {
+	return &webhookSQLGateway{
+		db:   db,
+		bind: postgresPlaceholders,
+	}
+}
+
+func NewWebhookSQLiteGateway(db *sql.DB) repository.WebhookRepository {
+	return &webhookSQLGateway{
+		db:   db,
+		bind: sqlitePlaceholders,
+	}
+}
@@ -0,0 +1,167 @@
+package gateways
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSQLiteGateway(t *testing.T) {
+	testWebhookRepository(t, NewWebhookSQLiteGateway(openSQLite(t)))
+}
+
+func TestWebhookMemoryGateway(t *testing.T) {
+	testWebhookRepository(t, NewWebhookMemoryGateway())
+}
+
+func testWebhookRepository(t *testing.T, gateway repository.WebhookRepository) {
+	createdAt := time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC)
+	subscription := entities.WebhookSubscription{
+		StoreId:        "loja-1",
+		SubscriptionId: "sub-1",
+		URL:            "https://parceiro.example/webhook",
+		Events:         []string{entities.ORDER_READY_EVENT, entities.ORDER_CANCELED_EVENT},
+		Secret:         "0123456789abcdef",
+		Enabled:        true,
+		CreatedAt:      createdAt,
+	}
+	other := subscription
+	other.StoreId = "loja-2"
+
+	assert.NoError(t, gateway.CreateSubscription(subscription))
+	assert.NoError(t, gateway.CreateSubscription(other))
+
+	got, err := gateway.GetSubscription("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &subscription, got)
+
+	got, err = gateway.GetSubscription("loja-1", "sub-9")
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	subscriptions, err := gateway.GetSubscriptions("loja-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.WebhookSubscription{subscription}, subscriptions)
+
+	disabledAt := createdAt.Add(time.Hour)
+	disabled := subscription
+	disabled.Enabled = false
+	disabled.ConsecutiveFailures = 5
+	disabled.DisabledAt = &disabledAt
+	disabled.URL = "https://outro.example/webhook"
+
+	assert.NoError(t, gateway.UpdateSubscription(disabled))
+
+	got, err = gateway.GetSubscription("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.False(t, got.Enabled)
+	assert.Equal(t, 5, got.ConsecutiveFailures)
+	assert.True(t, disabledAt.Equal(*got.DisabledAt))
+	assert.Equal(t, subscription.URL, got.URL, "only the state of the subscription is updated")
+
+	event := entities.StatusChangeEvent{EventId: "loja-1#1#x", Event: entities.ORDER_READY_EVENT}
+	for attempt := 1; attempt <= 3; attempt++ {
+		delivery := entities.NewWebhookDelivery(subscription, event, attempt, createdAt.Add(time.Duration(attempt)*time.Second), 500, assert.AnError)
+		assert.NoError(t, gateway.AppendDelivery(delivery))
+	}
+	assert.NoError(t, gateway.AppendDelivery(entities.NewWebhookDelivery(other, event, 1, createdAt, 200, nil)))
+
+	deliveries, err := gateway.GetDeliveries("loja-1", "sub-1", 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.Equal(t, 2, deliveries[1].Attempt)
+	assert.Equal(t, 500, deliveries[0].StatusCode)
+	assert.Equal(t, assert.AnError.Error(), deliveries[0].Error)
+	assert.False(t, deliveries[0].Delivered)
+
+	assert.NoError(t, gateway.DeleteSubscription("loja-1", "sub-1"))
+
+	got, err = gateway.GetSubscription("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	deliveries, err = gateway.GetDeliveries("loja-1", "sub-1", 10)
+
+	assert.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	deliveries, err = gateway.GetDeliveries("loja-2", "sub-1", 10)
+
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.NoError(t, gateway.UpdateSubscription(subscription), "updating a deleted subscription is not an error")
+
+	got, err = gateway.AddDeliveryFailure("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.NoError(t, gateway.ClearDeliveryFailures("loja-1", "sub-1"))
+	assert.NoError(t, gateway.DisableSubscription("loja-1", "sub-1", disabledAt))
+}
+
+func TestWebhookSQLiteGatewayDeliveryFailures(t *testing.T) {
+	testWebhookDeliveryFailures(t, NewWebhookSQLiteGateway(openSQLite(t)))
+}
+
+func TestWebhookMemoryGatewayDeliveryFailures(t *testing.T) {
+	testWebhookDeliveryFailures(t, NewWebhookMemoryGateway())
+}
+
+func testWebhookDeliveryFailures(t *testing.T, gateway repository.WebhookRepository) {
+	subscription := entities.WebhookSubscription{
+		StoreId:        "loja-1",
+		SubscriptionId: "sub-1",
+		URL:            "https://parceiro.example/webhook",
+		Events:         []string{entities.ORDER_READY_EVENT},
+		Secret:         "0123456789abcdef",
+		Enabled:        true,
+		CreatedAt:      time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	assert.NoError(t, gateway.CreateSubscription(subscription))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := gateway.AddDeliveryFailure("loja-1", "sub-1")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	got, err := gateway.AddDeliveryFailure("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, got.ConsecutiveFailures, "failures recorded together are all counted")
+	assert.True(t, got.Enabled)
+
+	disabledAt := subscription.CreatedAt.Add(time.Hour)
+	assert.NoError(t, gateway.DisableSubscription("loja-1", "sub-1", disabledAt))
+
+	got, err = gateway.GetSubscription("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.False(t, got.Enabled)
+	assert.True(t, disabledAt.Equal(*got.DisabledAt))
+	assert.Equal(t, 11, got.ConsecutiveFailures, "disabling keeps the failures")
+
+	assert.NoError(t, gateway.ClearDeliveryFailures("loja-1", "sub-1"))
+
+	got, err = gateway.GetSubscription("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, got.ConsecutiveFailures)
+}
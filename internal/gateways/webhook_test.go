@@ -0,0 +1,141 @@
+package gateways
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/external"
+	mock_external "github.com/8soat-grupo35/fastfood-order-production/external/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/aws/smithy-go"
+	"github.com/guregu/dynamo/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newWebhookGatewayMocks(t *testing.T) (*mock_external.MockDynamoAdapter[entities.WebhookSubscription], *mock_external.MockDynamoAdapter[entities.WebhookDelivery]) {
+	ctrl := gomock.NewController(t)
+	subscriptions := mock_external.NewMockDynamoAdapter[entities.WebhookSubscription](ctrl)
+	subscriptions.EXPECT().SetTable("production_order_webhook").Times(1)
+	deliveries := mock_external.NewMockDynamoAdapter[entities.WebhookDelivery](ctrl)
+	deliveries.EXPECT().SetTable("production_order_webhook_delivery").Times(1)
+
+	return subscriptions, deliveries
+}
+
+func TestWebhookGateway_GetSubscription(t *testing.T) {
+	subscriptions, deliveries := newWebhookGatewayMocks(t)
+	gateway := NewWebhookGateway(subscriptions, "production_order_webhook", deliveries, "production_order_webhook_delivery")
+
+	subscription := entities.WebhookSubscription{StoreId: "loja-1", SubscriptionId: "sub-1", Enabled: true}
+	subscriptions.EXPECT().GetOneByKeys("StoreId", "loja-1", "SubscriptionId", "sub-1").Return(subscription, nil).Times(1)
+
+	got, err := gateway.GetSubscription("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &subscription, got)
+
+	subscriptions.EXPECT().GetOneByKeys("StoreId", "loja-1", "SubscriptionId", "sub-9").Return(entities.WebhookSubscription{}, dynamo.ErrNotFound).Times(1)
+
+	got, err = gateway.GetSubscription("loja-1", "sub-9")
+
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	subscriptions.EXPECT().GetOneByKeys("StoreId", "loja-1", "SubscriptionId", "sub-1").Return(entities.WebhookSubscription{}, errors.New("teste")).Times(1)
+
+	_, err = gateway.GetSubscription("loja-1", "sub-1")
+
+	assert.EqualError(t, err, "teste")
+}
+
+func TestWebhookGateway_UpdateSubscription(t *testing.T) {
+	subscriptions, deliveries := newWebhookGatewayMocks(t)
+	gateway := NewWebhookGateway(subscriptions, "production_order_webhook", deliveries, "production_order_webhook_delivery")
+
+	subscription := entities.WebhookSubscription{StoreId: "loja-1", SubscriptionId: "sub-1", ConsecutiveFailures: 2}
+	values := map[string]interface{}{
+		"Enabled":             false,
+		"ConsecutiveFailures": 2,
+		"DisabledAt":          subscription.DisabledAt,
+	}
+	subscriptions.EXPECT().UpdateValuesByKeys("StoreId", "loja-1", "SubscriptionId", "sub-1", values).Return(subscription, nil).Times(1)
+
+	assert.NoError(t, gateway.UpdateSubscription(subscription))
+
+	subscriptions.EXPECT().UpdateValuesByKeys("StoreId", "loja-1", "SubscriptionId", "sub-1", values).
+		Return(entities.WebhookSubscription{}, &smithy.GenericAPIError{Code: "ConditionalCheckFailedException"}).Times(1)
+
+	assert.NoError(t, gateway.UpdateSubscription(subscription))
+}
+
+func TestWebhookGateway_AddDeliveryFailure(t *testing.T) {
+	subscriptions, deliveries := newWebhookGatewayMocks(t)
+	gateway := NewWebhookGateway(subscriptions, "production_order_webhook", deliveries, "production_order_webhook_delivery")
+
+	update := external.KeyedUpdate{
+		ValueKey:      "loja-1",
+		ValueRangeKey: "sub-1",
+		ValuesToAdd:   map[string]interface{}{"ConsecutiveFailures": 1},
+	}
+	counted := entities.WebhookSubscription{StoreId: "loja-1", SubscriptionId: "sub-1", ConsecutiveFailures: 3}
+	subscriptions.EXPECT().UpdateByKeys("StoreId", "SubscriptionId", update).Return(counted, nil).Times(1)
+
+	got, err := gateway.AddDeliveryFailure("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &counted, got)
+
+	subscriptions.EXPECT().UpdateByKeys("StoreId", "SubscriptionId", update).
+		Return(entities.WebhookSubscription{}, &smithy.GenericAPIError{Code: "ConditionalCheckFailedException"}).Times(1)
+
+	got, err = gateway.AddDeliveryFailure("loja-1", "sub-1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, got, "a deleted subscription is not counted")
+}
+
+func TestWebhookGateway_DisableSubscription(t *testing.T) {
+	subscriptions, deliveries := newWebhookGatewayMocks(t)
+	gateway := NewWebhookGateway(subscriptions, "production_order_webhook", deliveries, "production_order_webhook_delivery")
+
+	disabledAt := time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC)
+	subscriptions.EXPECT().UpdateValuesByKeys("StoreId", "loja-1", "SubscriptionId", "sub-1", map[string]interface{}{
+		"Enabled":    false,
+		"DisabledAt": disabledAt,
+	}).Return(entities.WebhookSubscription{}, nil).Times(1)
+	subscriptions.EXPECT().UpdateValuesByKeys("StoreId", "loja-1", "SubscriptionId", "sub-1", map[string]interface{}{
+		"ConsecutiveFailures": 0,
+	}).Return(entities.WebhookSubscription{}, &smithy.GenericAPIError{Code: "ConditionalCheckFailedException"}).Times(1)
+
+	assert.NoError(t, gateway.DisableSubscription("loja-1", "sub-1", disabledAt))
+	assert.NoError(t, gateway.ClearDeliveryFailures("loja-1", "sub-1"))
+}
+
+func TestWebhookGateway_DeleteSubscription(t *testing.T) {
+	subscriptions, deliveries := newWebhookGatewayMocks(t)
+	gateway := NewWebhookGateway(subscriptions, "production_order_webhook", deliveries, "production_order_webhook_delivery")
+
+	deliveries.EXPECT().GetAllByKey("SubscriptionKey", "loja-1#sub-1").Return([]entities.WebhookDelivery{
+		{SubscriptionKey: "loja-1#sub-1", DeliveryId: "d-1"},
+		{SubscriptionKey: "loja-1#sub-1", DeliveryId: "d-2"},
+	}, nil).Times(1)
+	deliveries.EXPECT().DeleteByKeys("SubscriptionKey", "loja-1#sub-1", "DeliveryId", "d-1").Return(nil).Times(1)
+	deliveries.EXPECT().DeleteByKeys("SubscriptionKey", "loja-1#sub-1", "DeliveryId", "d-2").Return(nil).Times(1)
+	subscriptions.EXPECT().DeleteByKeys("StoreId", "loja-1", "SubscriptionId", "sub-1").Return(nil).Times(1)
+
+	assert.NoError(t, gateway.DeleteSubscription("loja-1", "sub-1"))
+}
+
+func TestWebhookGateway_GetDeliveries(t *testing.T) {
+	subscriptions, deliveries := newWebhookGatewayMocks(t)
+	gateway := NewWebhookGateway(subscriptions, "production_order_webhook", deliveries, "production_order_webhook_delivery")
+
+	deliveries.EXPECT().GetPageByKeyDescending("SubscriptionKey", "loja-1#sub-1", 10, "").Return(nil, "", nil).Times(1)
+
+	got, err := gateway.GetDeliveries("loja-1", "sub-1", 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.WebhookDelivery{}, got)
+}
@@ -0,0 +1,15 @@
+package estimator
+
+import (
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+)
+
+//go:generate mockgen -source=ready_time.go -destination=mock/ready_time.go
+type ReadyTimeEstimator interface {
+	// EstimateReadyTimes receives the queue already sorted and every known
+	// order, including finished ones, to learn from, and returns when each
+	// queued order is expected to be ready, keyed by order id.
+	EstimateReadyTimes(queue []entities.ProductionOrder, orders []entities.ProductionOrder, now time.Time) map[uint32]time.Time
+}
@@ -0,0 +1,8 @@
+package publisher
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=sla_breach.go -destination=mock/sla_breach.go
+type SLABreachPublisher interface {
+	PublishSLABreach(breach entities.SLABreach) error
+}
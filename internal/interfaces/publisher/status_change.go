@@ -0,0 +1,8 @@
+package publisher
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=status_change.go -destination=mock/status_change.go
+type StatusChangePublisher interface {
+	PublishStatusChange(event entities.StatusChangeEvent) error
+}
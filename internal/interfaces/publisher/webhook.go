@@ -0,0 +1,8 @@
+package publisher
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=webhook.go -destination=mock/webhook.go
+type WebhookSender interface {
+	Send(subscription entities.WebhookSubscription, event entities.StatusChangeEvent) (statusCode int, err error)
+}
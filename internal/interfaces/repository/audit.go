@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=audit.go -destination=mock/audit.go
+type AuditRepository interface {
+	Append(entry entities.AuditEntry) error
+	GetByOrderId(storeId string, orderId uint32) ([]entities.AuditEntry, error)
+}
@@ -1,11 +1,27 @@
 package repository
 
-import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+import (
+	"errors"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+)
+
+// ErrInvalidCursor is returned by the page reads for a cursor they did not
+// hand out.
+var ErrInvalidCursor = errors.New("invalid cursor")
 
 //go:generate mockgen -source=production_order.go -destination=mock/production_order.go
 type ProductionOrderRepository interface {
-	GetAll() ([]entities.ProductionOrder, error)
-	GetByOrderId(orderId uint32) (*entities.ProductionOrder, error)
+	// GetOpen returns the orders of every store that are neither finished nor
+	// canceled, reading only those.
+	GetOpen() ([]entities.ProductionOrder, error)
+	GetAllByStore(storeId string) ([]entities.ProductionOrder, error)
+	GetPage(storeId string, limit int, cursor string) ([]entities.ProductionOrder, string, error)
+	// GetPageDescending pages like GetPage, from the highest order id down.
+	GetPageDescending(storeId string, limit int, cursor string) ([]entities.ProductionOrder, string, error)
+	GetByOrderId(storeId string, orderId uint32) (*entities.ProductionOrder, error)
 	Create(order entities.ProductionOrder) (*entities.ProductionOrder, error)
 	Update(order entities.ProductionOrder) (*entities.ProductionOrder, error)
+	// UpdateAll updates the orders as Update does, all of them or none.
+	UpdateAll(orders []entities.ProductionOrder) ([]entities.ProductionOrder, error)
 }
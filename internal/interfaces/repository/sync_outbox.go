@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=sync_outbox.go -destination=mock/sync_outbox.go
+type SyncOutboxRepository interface {
+	Enqueue(change entities.SyncChange) error
+	GetPending(limit int) ([]entities.SyncChange, error)
+	Remove(id int64) error
+	Reject(id int64, reason string) error
+}
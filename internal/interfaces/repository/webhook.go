@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+)
+
+//go:generate mockgen -source=webhook.go -destination=mock/webhook.go
+type WebhookRepository interface {
+	CreateSubscription(subscription entities.WebhookSubscription) error
+	GetSubscription(storeId string, subscriptionId string) (*entities.WebhookSubscription, error)
+	GetSubscriptions(storeId string) ([]entities.WebhookSubscription, error)
+	UpdateSubscription(subscription entities.WebhookSubscription) error
+	// AddDeliveryFailure adds one to the consecutive failures of the
+	// subscription in a single write, so failures recorded together are all
+	// counted, and returns it as stored afterwards, or nil once deleted.
+	AddDeliveryFailure(storeId string, subscriptionId string) (*entities.WebhookSubscription, error)
+	// ClearDeliveryFailures zeroes the consecutive failures of the
+	// subscription, unless it was deleted.
+	ClearDeliveryFailures(storeId string, subscriptionId string) error
+	// DisableSubscription disables the subscription from disabledAt, leaving
+	// its failures as they are, unless it was deleted.
+	DisableSubscription(storeId string, subscriptionId string, disabledAt time.Time) error
+	DeleteSubscription(storeId string, subscriptionId string) error
+	AppendDelivery(delivery entities.WebhookDelivery) error
+	GetDeliveries(storeId string, subscriptionId string, limit int) ([]entities.WebhookDelivery, error)
+}
@@ -0,0 +1,8 @@
+package usecase
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=production_audit.go -destination=mock/production_audit.go
+type ProductionAuditUseCases interface {
+	GetProductionOrderAudit(storeId string, orderId uint32) ([]entities.AuditEntry, error)
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+)
+
+//go:generate mockgen -source=production_export.go -destination=mock/production_export.go
+type ProductionExportUseCases interface {
+	ExportProductionOrders(storeId string, from time.Time, to time.Time, write func(order entities.ProductionOrder) error) error
+}
@@ -4,7 +4,14 @@ import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
 
 //go:generate mockgen -source=production_order.go -destination=mock/production_order.go
 type ProductionOrderUseCases interface {
-	SendOrderToProduction(orderId uint32) (*entities.ProductionOrder, error)
-	UpdateProductionOrderStatus(orderId uint32, status string) (*entities.ProductionOrder, error)
-	GetProductionOrderQueue() (*entities.ProductionOrderQueue, error)
+	SendOrderToProduction(storeId string, order entities.ProductionOrder) (*entities.ProductionOrder, error)
+	UpdateProductionOrderStatus(storeId string, orderId uint32, status string, actor entities.Actor) (*entities.ProductionOrder, error)
+	UpdateProductionOrderStatuses(storeId string, changes []entities.StatusChange, atomic bool, actor entities.Actor) ([]entities.StatusChangeResult, error)
+	CancelProductionOrder(storeId string, orderId uint32, actor entities.Actor) (*entities.ProductionOrder, error)
+	UpdateProductionOrderPriority(storeId string, orderId uint32, priority string) (*entities.ProductionOrder, error)
+	UpdateStationTicketStatus(storeId string, orderId uint32, station string, status string, actor entities.Actor) (*entities.ProductionOrder, error)
+	GetProductionOrderQueue(storeId string) (*entities.ProductionOrderQueue, error)
+	GetProductionOrder(storeId string, orderId uint32) (*entities.ProductionOrder, error)
+	GetStationQueue(storeId string, station string) (*entities.ProductionOrderQueue, error)
+	ListProductionOrders(storeId string, filter entities.ProductionOrderFilter, sort string, limit int, cursor string) (*entities.ProductionOrderPage, error)
 }
@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+)
+
+//go:generate mockgen -source=production_report.go -destination=mock/production_report.go
+type ProductionReportUseCases interface {
+	GetSummary(storeId string, from time.Time, to time.Time) (*entities.ProductionReport, error)
+}
@@ -0,0 +1,8 @@
+package usecase
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=sla_monitor.go -destination=mock/sla_monitor.go
+type SLAMonitorUseCases interface {
+	CheckSLA() ([]entities.SLABreach, error)
+}
@@ -0,0 +1,8 @@
+package usecase
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=sync.go -destination=mock/sync.go
+type SyncUseCases interface {
+	Sync() (entities.SyncReport, error)
+}
@@ -0,0 +1,21 @@
+package usecase
+
+import "github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+
+//go:generate mockgen -source=webhook.go -destination=mock/webhook.go
+type WebhookUseCases interface {
+	Subscribe(storeId string, subscription entities.WebhookSubscription) (*entities.WebhookSubscription, error)
+	GetSubscriptions(storeId string) ([]entities.WebhookSubscription, error)
+	Unsubscribe(storeId string, subscriptionId string) error
+	EnableSubscription(storeId string, subscriptionId string) (*entities.WebhookSubscription, error)
+	GetDeliveries(storeId string, subscriptionId string, limit int) ([]entities.WebhookDelivery, error)
+}
+
+// WebhookDispatcher posts the status changes published to it to the webhook
+// subscriptions of their stores. Dispatch runs a worker until the process
+// exits; several may run together.
+type WebhookDispatcher interface {
+	PublishStatusChange(event entities.StatusChangeEvent) error
+	Dispatch()
+	Deliver(event entities.StatusChangeEvent)
+}
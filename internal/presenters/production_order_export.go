@@ -0,0 +1,130 @@
+package presenters
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+)
+
+const (
+	CSV_EXPORT_FORMAT    = "csv"
+	NDJSON_EXPORT_FORMAT = "ndjson"
+)
+
+var csvExportHeader = []string{
+	"store_id",
+	"order_id",
+	"priority",
+	"created_at",
+	"current_status",
+	"transition_status",
+	"transition_at",
+}
+
+// ProductionOrderExporter writes orders one at a time so exports can be
+// streamed.
+type ProductionOrderExporter interface {
+	ContentType() string
+	Write(order entities.ProductionOrder) error
+	Flush() error
+}
+
+// NewProductionOrderExporter returns the exporter for the given format: csv,
+// with one row per status transition, or ndjson, with one order per line.
+func NewProductionOrderExporter(format string, writer io.Writer) (ProductionOrderExporter, error) {
+	switch format {
+	case CSV_EXPORT_FORMAT:
+		return &csvProductionOrderExporter{
+			writer: csv.NewWriter(writer),
+		}, nil
+	case NDJSON_EXPORT_FORMAT:
+		return &ndjsonProductionOrderExporter{
+			encoder: json.NewEncoder(writer),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("format must be between %s or %s", CSV_EXPORT_FORMAT, NDJSON_EXPORT_FORMAT)
+}
+
+type csvProductionOrderExporter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvProductionOrderExporter) ContentType() string {
+	return "text/csv"
+}
+
+func (c *csvProductionOrderExporter) Write(order entities.ProductionOrder) error {
+	if !c.headerWritten {
+		if err := c.writer.Write(csvExportHeader); err != nil {
+			return err
+		}
+
+		c.headerWritten = true
+	}
+
+	orderColumns := []string{
+		order.StoreId,
+		strconv.FormatUint(uint64(order.OrderId), 10),
+		order.Priority,
+		formatExportTime(order.CreatedAt),
+		order.Status,
+	}
+
+	if len(order.History) == 0 {
+		return c.writer.Write(append(orderColumns, "", ""))
+	}
+
+	for _, transition := range order.History {
+		row := append(append([]string{}, orderColumns...), transition.Status, formatExportTime(transition.At))
+
+		if err := c.writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *csvProductionOrderExporter) Flush() error {
+	if !c.headerWritten {
+		if err := c.writer.Write(csvExportHeader); err != nil {
+			return err
+		}
+
+		c.headerWritten = true
+	}
+
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonProductionOrderExporter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonProductionOrderExporter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (n *ndjsonProductionOrderExporter) Write(order entities.ProductionOrder) error {
+	return n.encoder.Encode(order)
+}
+
+func (n *ndjsonProductionOrderExporter) Flush() error {
+	return nil
+}
+
+func formatExportTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,70 @@
+package presenters
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+var exportedOrder = entities.ProductionOrder{
+	StoreId:   "loja-1",
+	OrderId:   1,
+	Status:    entities.IN_PREPARATION_STATUS,
+	Priority:  entities.NORMAL_PRIORITY,
+	CreatedAt: time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC),
+	History: []entities.StatusTransition{
+		{Status: entities.RECEIVED_STATUS, At: time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC)},
+		{Status: entities.IN_PREPARATION_STATUS, At: time.Date(2024, time.October, 10, 12, 5, 0, 0, time.UTC)},
+	},
+}
+
+func TestCSVProductionOrderExporter(t *testing.T) {
+	output := bytes.Buffer{}
+	exporter, err := NewProductionOrderExporter(CSV_EXPORT_FORMAT, &output)
+	assert.NoError(t, err)
+
+	assert.NoError(t, exporter.Write(exportedOrder))
+	assert.NoError(t, exporter.Write(entities.ProductionOrder{OrderId: 2, Status: entities.RECEIVED_STATUS}))
+	assert.NoError(t, exporter.Flush())
+
+	assert.Equal(t, "text/csv", exporter.ContentType())
+	assert.Equal(t, ""+
+		"store_id,order_id,priority,created_at,current_status,transition_status,transition_at\n"+
+		"loja-1,1,NORMAL,2024-10-10T12:00:00Z,EM_PREPARACAO,RECEBIDO,2024-10-10T12:00:00Z\n"+
+		"loja-1,1,NORMAL,2024-10-10T12:00:00Z,EM_PREPARACAO,EM_PREPARACAO,2024-10-10T12:05:00Z\n"+
+		",2,,,RECEBIDO,,\n", output.String())
+}
+
+func TestCSVProductionOrderExporterWithoutOrders(t *testing.T) {
+	output := bytes.Buffer{}
+	exporter, err := NewProductionOrderExporter(CSV_EXPORT_FORMAT, &output)
+	assert.NoError(t, err)
+
+	assert.NoError(t, exporter.Flush())
+	assert.Equal(t, "store_id,order_id,priority,created_at,current_status,transition_status,transition_at\n", output.String())
+}
+
+func TestNDJSONProductionOrderExporter(t *testing.T) {
+	output := bytes.Buffer{}
+	exporter, err := NewProductionOrderExporter(NDJSON_EXPORT_FORMAT, &output)
+	assert.NoError(t, err)
+
+	assert.NoError(t, exporter.Write(exportedOrder))
+	assert.NoError(t, exporter.Write(entities.ProductionOrder{OrderId: 2, Status: entities.RECEIVED_STATUS}))
+	assert.NoError(t, exporter.Flush())
+
+	assert.Equal(t, "application/x-ndjson", exporter.ContentType())
+	assert.Equal(t, ""+
+		`{"StoreId":"loja-1","OrderId":1,"Status":"EM_PREPARACAO","Priority":"NORMAL","CreatedAt":"2024-10-10T12:00:00Z","Items":null,"Tickets":null,"History":[{"Status":"RECEBIDO","At":"2024-10-10T12:00:00Z"},{"Status":"EM_PREPARACAO","At":"2024-10-10T12:05:00Z"}]}`+"\n"+
+		`{"StoreId":"","OrderId":2,"Status":"RECEBIDO","Priority":"","CreatedAt":"0001-01-01T00:00:00Z","Items":null,"Tickets":null,"History":null}`+"\n", output.String())
+}
+
+func TestNewProductionOrderExporterInvalidFormat(t *testing.T) {
+	exporter, err := NewProductionOrderExporter("xml", &bytes.Buffer{})
+
+	assert.Nil(t, exporter)
+	assert.EqualError(t, err, "format must be between csv or ndjson")
+}
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+)
+
+type productionAuditService struct {
+	auditRepository repository.AuditRepository
+}
+
+func NewProductionAuditUseCase(auditRepository repository.AuditRepository) usecase.ProductionAuditUseCases {
+	return &productionAuditService{
+		auditRepository: auditRepository,
+	}
+}
+
+// GetProductionOrderAudit implements usecase.ProductionAuditUseCases. Entries
+// are returned oldest first.
+func (p *productionAuditService) GetProductionOrderAudit(storeId string, orderId uint32) ([]entities.AuditEntry, error) {
+	entries, err := p.auditRepository.GetByOrderId(storeId, orderId)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetProductionOrderAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	entries := []entities.AuditEntry{
+		entities.NewAuditEntry(
+			entities.ProductionOrder{StoreId: currentStore, OrderId: 1},
+			entities.STATUS_CHANGED_AUDIT_ACTION,
+			entities.RECEIVED_STATUS,
+			entities.IN_PREPARATION_STATUS,
+			kitchenActor,
+			fixedNow,
+		),
+	}
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(entries, nil).Times(1)
+
+	result, err := NewProductionAuditUseCase(mockAudit).GetProductionOrderAudit(currentStore, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entries, result)
+}
+
+func TestGetProductionOrderAuditError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockErr := errors.New("mock error")
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(nil, mockErr).Times(1)
+
+	result, err := NewProductionAuditUseCase(mockAudit).GetProductionOrderAudit(currentStore, 1)
+
+	assert.Nil(t, result)
+	assert.EqualError(t, err, mockErr.Error())
+}
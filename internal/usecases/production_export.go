@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"time"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+)
+
+// exportPageSize is how many orders are read from the repository at a time
+// while exporting, so the export never holds the whole history in memory.
+const exportPageSize = 100
+
+type productionExportService struct {
+	productionOrderRepository repository.ProductionOrderRepository
+}
+
+func NewProductionExportUseCase(productionOrderRepository repository.ProductionOrderRepository) usecase.ProductionExportUseCases {
+	return &productionExportService{
+		productionOrderRepository: productionOrderRepository,
+	}
+}
+
+// ExportProductionOrders implements usecase.ProductionExportUseCases. It pages
+// through every order of the store and hands the ones received between from (inclusive) and
+// to (exclusive) to write, stopping at the first error.
+func (p *productionExportService) ExportProductionOrders(storeId string, from time.Time, to time.Time, write func(order entities.ProductionOrder) error) error {
+	if !from.Before(to) {
+		return &custom_errors.BadRequestError{
+			Message: "from must be before to",
+		}
+	}
+
+	cursor := ""
+	for {
+		productionOrders, nextCursor, err := p.productionOrderRepository.GetPage(storeId, exportPageSize, cursor)
+
+		if err != nil {
+			return &custom_errors.DatabaseError{
+				Message: err.Error(),
+			}
+		}
+
+		for _, order := range productionOrders {
+			receivedAt := order.ReceivedAt()
+			if receivedAt.Before(from) || !receivedAt.Before(to) {
+				continue
+			}
+
+			err = write(order)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+
+		cursor = nextCursor
+	}
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportProductionOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := fixedNow
+	to := fixedNow.Add(24 * time.Hour)
+
+	firstPage := []entities.ProductionOrder{
+		{OrderId: 1, Status: entities.FINISHED_STATUS, CreatedAt: from.Add(time.Hour)},
+		{OrderId: 2, Status: entities.FINISHED_STATUS, CreatedAt: from.Add(-time.Hour)},
+	}
+	secondPage := []entities.ProductionOrder{
+		{OrderId: 3, Status: entities.RECEIVED_STATUS, CreatedAt: to.Add(-time.Minute)},
+		{OrderId: 4, Status: entities.RECEIVED_STATUS, CreatedAt: to},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetPage(currentStore, exportPageSize, "").Return(firstPage, "next", nil).Times(1),
+		mockRepo.EXPECT().GetPage(currentStore, exportPageSize, "next").Return(secondPage, "", nil).Times(1),
+	)
+
+	exported := []uint32{}
+	err := NewProductionExportUseCase(mockRepo).ExportProductionOrders(currentStore, from, to, func(order entities.ProductionOrder) error {
+		exported = append(exported, order.OrderId)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 3}, exported)
+}
+
+func TestExportProductionOrdersWriteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockErr := errors.New("mock write error")
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetPage(currentStore, exportPageSize, "").Return([]entities.ProductionOrder{
+		{OrderId: 1, Status: entities.FINISHED_STATUS, CreatedAt: fixedNow},
+	}, "next", nil).Times(1)
+
+	err := NewProductionExportUseCase(mockRepo).ExportProductionOrders(currentStore, fixedNow, fixedNow.Add(time.Hour), func(order entities.ProductionOrder) error {
+		return mockErr
+	})
+
+	assert.EqualError(t, err, mockErr.Error())
+}
+
+func TestExportProductionOrdersErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockErr := errors.New("mock error")
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetPage(currentStore, exportPageSize, "").Return([]entities.ProductionOrder{}, "", mockErr).Times(1)
+
+	exportUseCase := NewProductionExportUseCase(mockRepo)
+	write := func(order entities.ProductionOrder) error { return nil }
+
+	err := exportUseCase.ExportProductionOrders(currentStore, fixedNow, fixedNow.Add(time.Hour), write)
+	assert.EqualError(t, err, mockErr.Error())
+
+	err = exportUseCase.ExportProductionOrders(currentStore, fixedNow, fixedNow, write)
+	assert.EqualError(t, err, "from must be before to")
+}
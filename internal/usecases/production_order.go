@@ -1,25 +1,52 @@
 package usecases
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
 	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/estimator"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
 )
 
+// maxListLimit is the largest page of a listing.
+const maxListLimit = 100
+
+// listMaxReads bounds the repository pages read to fill a page of a listing, so
+// a filter matching few orders cannot make a request read the whole store.
+const listMaxReads = 10
+
+// now is replaced in tests to get deterministic timestamps.
+var now = time.Now
+
 type productionOrderService struct {
 	productionOrderRepository repository.ProductionOrderRepository
+	auditRepository           repository.AuditRepository
+	stationRouter             entities.StationRouter
+	queuePolicy               entities.QueuePolicy
+	readyTimeEstimator        estimator.ReadyTimeEstimator
+	statusChangePublisher     publisher.StatusChangePublisher
 }
 
-func NewProductionOrderUseCase(productionOrderRepository repository.ProductionOrderRepository) usecase.ProductionOrderUseCases {
+func NewProductionOrderUseCase(productionOrderRepository repository.ProductionOrderRepository, auditRepository repository.AuditRepository, stationRouter entities.StationRouter, queuePolicy entities.QueuePolicy, readyTimeEstimator estimator.ReadyTimeEstimator, statusChangePublisher publisher.StatusChangePublisher) usecase.ProductionOrderUseCases {
 	return &productionOrderService{
 		productionOrderRepository: productionOrderRepository,
+		auditRepository:           auditRepository,
+		stationRouter:             stationRouter,
+		queuePolicy:               queuePolicy,
+		readyTimeEstimator:        readyTimeEstimator,
+		statusChangePublisher:     statusChangePublisher,
 	}
 }
 
 // GetProductionOrderQueue implements usecase.ProductionOrderUseCases.
-func (p *productionOrderService) GetProductionOrderQueue() (*entities.ProductionOrderQueue, error) {
-	productionOrders, err := p.productionOrderRepository.GetAll()
+func (p *productionOrderService) GetProductionOrderQueue(storeId string) (*entities.ProductionOrderQueue, error) {
+	productionOrders, err := p.productionOrderRepository.GetAllByStore(storeId)
 
 	if err != nil {
 		return nil, &custom_errors.DatabaseError{
@@ -29,18 +56,143 @@ func (p *productionOrderService) GetProductionOrderQueue() (*entities.Production
 
 	productionQueue := entities.ProductionOrderQueue{
 		Orders: productionOrders,
+		Policy: p.queuePolicy,
 	}
 
+	queuedAt := now()
 	productionQueue.RemoveFinishedOrders()
-	productionQueue.Sort()
+	productionQueue.SortAt(queuedAt)
+	productionQueue.TrackSLA(queuedAt)
+	productionQueue.SetEstimatedReadyTimes(
+		p.readyTimeEstimator.EstimateReadyTimes(productionQueue.Orders, productionOrders, queuedAt),
+	)
 
 	return &productionQueue, nil
 }
 
+// GetProductionOrder implements usecase.ProductionOrderUseCases.
+func (p *productionOrderService) GetProductionOrder(storeId string, orderId uint32) (*entities.ProductionOrder, error) {
+	productionQueue, err := p.GetProductionOrderQueue(storeId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range productionQueue.Orders {
+		if order.OrderId == orderId {
+			return &order, nil
+		}
+	}
+
+	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(storeId, orderId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if foundProductionOrder == nil {
+		return nil, &custom_errors.BadRequestError{
+			Message: "Cant find production order",
+		}
+	}
+
+	return foundProductionOrder, nil
+}
+
+// ListProductionOrders implements usecase.ProductionOrderUseCases. Orders are
+// read from the repository by order id, closed ones included, and the ones the
+// filter matches fill the page. A page holds fewer orders than the limit when
+// the store has no more or when the reads allowed for a page run out, so only
+// an empty cursor ends the listing.
+func (p *productionOrderService) ListProductionOrders(storeId string, filter entities.ProductionOrderFilter, sort string, limit int, cursor string) (*entities.ProductionOrderPage, error) {
+	if limit < 1 || limit > maxListLimit {
+		return nil, &custom_errors.BadRequestError{
+			Message: fmt.Sprintf("limit must be between 1 and %d", maxListLimit),
+		}
+	}
+
+	getPage := p.productionOrderRepository.GetPage
+	switch sort {
+	case "", entities.ORDER_ID_ASCENDING_SORT:
+	case entities.ORDER_ID_DESCENDING_SORT:
+		getPage = p.productionOrderRepository.GetPageDescending
+	default:
+		return nil, &custom_errors.BadRequestError{
+			Message: fmt.Sprintf("sort must be %s or %s", entities.ORDER_ID_ASCENDING_SORT, entities.ORDER_ID_DESCENDING_SORT),
+		}
+	}
+
+	err := filter.Validate()
+
+	if err != nil {
+		return nil, &custom_errors.BadRequestError{
+			Message: err.Error(),
+		}
+	}
+
+	page := entities.ProductionOrderPage{
+		Orders:     []entities.ProductionOrder{},
+		NextCursor: cursor,
+	}
+
+	for reads := 0; reads < listMaxReads; reads++ {
+		productionOrders, nextCursor, err := getPage(storeId, limit-len(page.Orders), page.NextCursor)
+
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, &custom_errors.BadRequestError{
+				Message: err.Error(),
+			}
+		}
+
+		if err != nil {
+			return nil, &custom_errors.DatabaseError{
+				Message: err.Error(),
+			}
+		}
+
+		for _, order := range productionOrders {
+			if filter.Matches(order) {
+				page.Orders = append(page.Orders, order)
+			}
+		}
+
+		page.NextCursor = nextCursor
+
+		if nextCursor == "" || len(page.Orders) == limit {
+			break
+		}
+	}
+
+	return &page, nil
+}
+
+// GetStationQueue implements usecase.ProductionOrderUseCases.
+func (p *productionOrderService) GetStationQueue(storeId string, station string) (*entities.ProductionOrderQueue, error) {
+	productionOrders, err := p.productionOrderRepository.GetAllByStore(storeId)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	stationQueue := entities.ProductionOrderQueue{
+		Orders: productionOrders,
+		Policy: p.queuePolicy,
+	}
+
+	queuedAt := now()
+	stationQueue.KeepStation(station)
+	stationQueue.SortAt(queuedAt)
+	stationQueue.TrackSLA(queuedAt)
+
+	return &stationQueue, nil
+}
+
 // SendOrderToProduction implements usecase.ProductionOrderUseCases.
-func (p *productionOrderService) SendOrderToProduction(orderId uint32) (*entities.ProductionOrder, error) {
+func (p *productionOrderService) SendOrderToProduction(storeId string, order entities.ProductionOrder) (*entities.ProductionOrder, error) {
 
-	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(orderId)
+	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(storeId, order.OrderId)
 
 	if err != nil {
 		return nil, err
@@ -52,9 +204,25 @@ func (p *productionOrderService) SendOrderToProduction(orderId uint32) (*entitie
 		}
 	}
 
+	receivedAt := now()
 	productionOrder := entities.ProductionOrder{
-		OrderId: orderId,
-		Status:  entities.RECEIVED_STATUS,
+		StoreId:   storeId,
+		OrderId:   order.OrderId,
+		Status:    entities.RECEIVED_STATUS,
+		Priority:  order.Priority,
+		CreatedAt: receivedAt,
+		Items:     order.Items,
+		Tickets:   p.stationRouter.Route(order.Items),
+		History: []entities.StatusTransition{
+			{
+				Status: entities.RECEIVED_STATUS,
+				At:     receivedAt,
+			},
+		},
+	}
+
+	if productionOrder.Priority == "" {
+		productionOrder.Priority = entities.NORMAL_PRIORITY
 	}
 
 	err = productionOrder.Validate()
@@ -74,8 +242,224 @@ func (p *productionOrderService) SendOrderToProduction(orderId uint32) (*entitie
 	return createdProductionOrder, nil
 }
 
-func (p *productionOrderService) UpdateProductionOrderStatus(orderId uint32, status string) (*entities.ProductionOrder, error) {
-	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(orderId)
+func (p *productionOrderService) UpdateProductionOrderStatus(storeId string, orderId uint32, status string, actor entities.Actor) (*entities.ProductionOrder, error) {
+	changedAt := now()
+	foundProductionOrder, previousStatus, err := p.changeStatus(storeId, orderId, status, changedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedProductionOrder, err := p.productionOrderRepository.Update(*foundProductionOrder)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	if previousStatus != status {
+		p.recordAudit(entities.NewAuditEntry(*updatedProductionOrder, entities.STATUS_CHANGED_AUDIT_ACTION, previousStatus, status, actor, changedAt))
+		p.publishStatusChange(entities.NewStatusChangeEvent(*updatedProductionOrder, previousStatus, changedAt))
+	}
+
+	return updatedProductionOrder, nil
+}
+
+// UpdateProductionOrderStatuses applies the status changes of a bulk update in
+// their order. Each change is applied on its own unless atomic is set, when
+// every change is checked first and then all of them are stored together, or
+// none is and a BadRequestError comes back along with the results.
+func (p *productionOrderService) UpdateProductionOrderStatuses(storeId string, changes []entities.StatusChange, atomic bool, actor entities.Actor) ([]entities.StatusChangeResult, error) {
+	if atomic {
+		return p.updateProductionOrderStatusesAtomically(storeId, changes, actor)
+	}
+
+	results := []entities.StatusChangeResult{}
+	for _, change := range changes {
+		result := entities.StatusChangeResult{OrderId: change.OrderId, Status: change.Status}
+		updatedProductionOrder, err := p.UpdateProductionOrderStatus(storeId, change.OrderId, change.Status, actor)
+
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Applied = true
+			result.Order = updatedProductionOrder
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (p *productionOrderService) updateProductionOrderStatusesAtomically(storeId string, changes []entities.StatusChange, actor entities.Actor) ([]entities.StatusChangeResult, error) {
+	changedAt := now()
+	results := []entities.StatusChangeResult{}
+	changedOrders := []entities.ProductionOrder{}
+	previousStatuses := []string{}
+	failed := false
+
+	for _, change := range changes {
+		result := entities.StatusChangeResult{OrderId: change.OrderId, Status: change.Status}
+		foundProductionOrder, previousStatus, err := p.changeStatus(storeId, change.OrderId, change.Status, changedAt)
+
+		if err == nil && containsOrder(changedOrders, change.OrderId) {
+			err = fmt.Errorf("order %d is changed more than once", change.OrderId)
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+			failed = true
+		} else {
+			changedOrders = append(changedOrders, *foundProductionOrder)
+			previousStatuses = append(previousStatuses, previousStatus)
+		}
+
+		results = append(results, result)
+	}
+
+	if failed {
+		for i := range results {
+			if results[i].Error == "" {
+				results[i].Error = "not applied, another change failed"
+			}
+		}
+
+		return results, &custom_errors.BadRequestError{
+			Message: "no status was changed, some of the changes failed",
+		}
+	}
+
+	updatedProductionOrders, err := p.productionOrderRepository.UpdateAll(changedOrders)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	for i, order := range updatedProductionOrders {
+		if previousStatuses[i] != order.Status {
+			p.recordAudit(entities.NewAuditEntry(order, entities.STATUS_CHANGED_AUDIT_ACTION, previousStatuses[i], order.Status, actor, changedAt))
+			p.publishStatusChange(entities.NewStatusChangeEvent(order, previousStatuses[i], changedAt))
+		}
+
+		results[i].Applied = true
+		results[i].Order = &updatedProductionOrders[i]
+	}
+
+	return results, nil
+}
+
+// changeStatus reads an order and moves it to the status, returning it along
+// with the status it had. The order is not stored.
+func (p *productionOrderService) changeStatus(storeId string, orderId uint32, status string, changedAt time.Time) (*entities.ProductionOrder, string, error) {
+	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(storeId, orderId)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if foundProductionOrder == nil {
+		return nil, "", &custom_errors.BadRequestError{
+			Message: "Cant find production order",
+		}
+	}
+
+	if status == entities.CANCELED_STATUS {
+		return nil, "", &custom_errors.BadRequestError{
+			Message: "orders must be canceled through the cancel endpoint",
+		}
+	}
+
+	if !entities.CanChangeStatus(foundProductionOrder.Status, status) {
+		return nil, "", &custom_errors.ValidationError{
+			Message: fmt.Sprintf("cant change status of a %s order to %s", foundProductionOrder.Status, status),
+		}
+	}
+
+	if status == entities.DONE_STATUS && foundProductionOrder.HasPendingTickets() {
+		return nil, "", &custom_errors.BadRequestError{
+			Message: "order still has station tickets in production",
+		}
+	}
+
+	previousStatus := foundProductionOrder.Status
+	foundProductionOrder.ChangeStatus(status, changedAt)
+
+	err = foundProductionOrder.Validate()
+
+	if err != nil {
+		return nil, "", &custom_errors.BadRequestError{
+			Message: err.Error(),
+		}
+	}
+
+	return foundProductionOrder, previousStatus, nil
+}
+
+func containsOrder(orders []entities.ProductionOrder, orderId uint32) bool {
+	for _, order := range orders {
+		if order.OrderId == orderId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *productionOrderService) UpdateStationTicketStatus(storeId string, orderId uint32, station string, status string, actor entities.Actor) (*entities.ProductionOrder, error) {
+	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(storeId, orderId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if foundProductionOrder == nil {
+		return nil, &custom_errors.BadRequestError{
+			Message: "Cant find production order",
+		}
+	}
+
+	previousStatus := foundProductionOrder.Status
+	previousTicketStatus := ""
+	if ticket := foundProductionOrder.TicketForStation(station); ticket != nil {
+		previousTicketStatus = ticket.Status
+	}
+
+	changedAt := now()
+	err = foundProductionOrder.UpdateTicketStatus(station, status, changedAt)
+
+	if err != nil {
+		return nil, &custom_errors.BadRequestError{
+			Message: err.Error(),
+		}
+	}
+
+	updatedProductionOrder, err := p.productionOrderRepository.Update(*foundProductionOrder)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	actor.Station = station
+	if previousTicketStatus != status {
+		p.recordAudit(entities.NewAuditEntry(*updatedProductionOrder, entities.TICKET_CHANGED_AUDIT_ACTION, previousTicketStatus, status, actor, changedAt))
+	}
+
+	if updatedProductionOrder.Status != previousStatus {
+		p.recordAudit(entities.NewAuditEntry(*updatedProductionOrder, entities.STATUS_CHANGED_AUDIT_ACTION, previousStatus, updatedProductionOrder.Status, actor, changedAt))
+		p.publishStatusChange(entities.NewStatusChangeEvent(*updatedProductionOrder, previousStatus, changedAt))
+	}
+
+	return updatedProductionOrder, nil
+}
+
+func (p *productionOrderService) UpdateProductionOrderPriority(storeId string, orderId uint32, priority string) (*entities.ProductionOrder, error) {
+	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(storeId, orderId)
 
 	if err != nil {
 		return nil, err
@@ -87,7 +471,13 @@ func (p *productionOrderService) UpdateProductionOrderStatus(orderId uint32, sta
 		}
 	}
 
-	foundProductionOrder.Status = status
+	if foundProductionOrder.IsClosed() {
+		return nil, &custom_errors.BadRequestError{
+			Message: "cant change priority of a closed order",
+		}
+	}
+
+	foundProductionOrder.Priority = priority
 
 	err = foundProductionOrder.Validate()
 
@@ -107,3 +497,62 @@ func (p *productionOrderService) UpdateProductionOrderStatus(orderId uint32, sta
 
 	return updatedProductionOrder, nil
 }
+
+// CancelProductionOrder implements usecase.ProductionOrderUseCases.
+func (p *productionOrderService) CancelProductionOrder(storeId string, orderId uint32, actor entities.Actor) (*entities.ProductionOrder, error) {
+	foundProductionOrder, err := p.productionOrderRepository.GetByOrderId(storeId, orderId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if foundProductionOrder == nil {
+		return nil, &custom_errors.BadRequestError{
+			Message: "Cant find production order",
+		}
+	}
+
+	if foundProductionOrder.IsClosed() {
+		return nil, &custom_errors.BadRequestError{
+			Message: "cant cancel a closed order",
+		}
+	}
+
+	previousStatus := foundProductionOrder.Status
+	canceledAt := now()
+	foundProductionOrder.ChangeStatus(entities.CANCELED_STATUS, canceledAt)
+
+	updatedProductionOrder, err := p.productionOrderRepository.Update(*foundProductionOrder)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	p.recordAudit(entities.NewAuditEntry(*foundProductionOrder, entities.ORDER_CANCELED_AUDIT_ACTION, previousStatus, entities.CANCELED_STATUS, actor, canceledAt))
+	p.publishStatusChange(entities.NewStatusChangeEvent(*foundProductionOrder, previousStatus, canceledAt))
+
+	return updatedProductionOrder, nil
+}
+
+// recordAudit appends a change to the audit trail. The change itself is already
+// stored when this runs, so a failure is logged along with the entry instead of
+// failing the request.
+func (p *productionOrderService) recordAudit(entry entities.AuditEntry) {
+	err := p.auditRepository.Append(entry)
+
+	if err != nil {
+		log.Printf("could not record audit entry %+v: %s", entry, err.Error())
+	}
+}
+
+// publishStatusChange hands a stored status change to the webhook subscribers.
+// Like the audit trail, a failure is only logged.
+func (p *productionOrderService) publishStatusChange(event entities.StatusChangeEvent) {
+	err := p.statusChangePublisher.PublishStatusChange(event)
+
+	if err != nil {
+		log.Printf("could not publish status change %s: %s", event.EventId, err.Error())
+	}
+}
@@ -3,52 +3,125 @@ package usecases
 import (
 	"errors"
 	"testing"
+	"time"
 
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_estimator "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/estimator/mock"
+	mock_publisher "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
 	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
-	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
+var stationRouter = entities.NewStationRouter(
+	map[string]string{
+		"LANCHE": "grill",
+		"BEBIDA": "drinks",
+	},
+	"grill",
+)
+
+var queuePolicy = entities.QueuePolicy{
+	PriorityAging: 5 * time.Minute,
+}
+
+const currentStore = "loja-1"
+
+var fixedNow = time.Date(2024, time.October, 10, 12, 0, 0, 0, time.UTC)
+
+var receivedHistory = []entities.StatusTransition{
+	{
+		Status: entities.RECEIVED_STATUS,
+		At:     fixedNow,
+	},
+}
+
+// noReadyTimeEstimator keeps ready time estimates out of the tests that are not
+// about them.
+type noReadyTimeEstimator struct{}
+
+func (noReadyTimeEstimator) EstimateReadyTimes(queue []entities.ProductionOrder, orders []entities.ProductionOrder, now time.Time) map[uint32]time.Time {
+	return map[uint32]time.Time{}
+}
+
+// noStatusChangePublisher keeps the webhooks out of the tests that are not
+// about them.
+type noStatusChangePublisher struct{}
+
+func (noStatusChangePublisher) PublishStatusChange(event entities.StatusChangeEvent) error {
+	return nil
+}
+
+// noAuditRepository keeps the audit trail out of the tests that are not about
+// it.
+type noAuditRepository struct{}
+
+func (noAuditRepository) Append(entry entities.AuditEntry) error {
+	return nil
+}
+
+func (noAuditRepository) GetByOrderId(storeId string, orderId uint32) ([]entities.AuditEntry, error) {
+	return []entities.AuditEntry{}, nil
+}
+
+var kitchenActor = entities.Actor{
+	Name:      "cozinheiro",
+	Station:   "balcao",
+	RequestId: "req-1",
+}
+
+func useFixedClock(t *testing.T) {
+	now = func() time.Time { return fixedNow }
+	t.Cleanup(func() { now = time.Now })
+}
+
 func TestGetProductionOrderQueue(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	productionQueue := []entities.ProductionOrder{
 		{
+			StoreId: currentStore,
 			OrderId: 1,
 			Status:  entities.RECEIVED_STATUS,
 		},
 		{
+			StoreId: currentStore,
 			OrderId: 2,
 			Status:  entities.RECEIVED_STATUS,
 		},
 		{
+			StoreId: currentStore,
 			OrderId: 3,
 			Status:  entities.IN_PREPARATION_STATUS,
 		},
 		{
+			StoreId: currentStore,
 			OrderId: 4,
 			Status:  entities.FINISHED_STATUS,
 		},
 		{
+			StoreId: currentStore,
 			OrderId: 5,
 			Status:  entities.DONE_STATUS,
 		},
 	}
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetAll().Return(productionQueue, nil).Times(1)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return(productionQueue, nil).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
 
-	queue, err := prodOrderUseCase.GetProductionOrderQueue()
+	queue, err := prodOrderUseCase.GetProductionOrderQueue(currentStore)
 
 	oldQueue := entities.ProductionOrderQueue{
 		Orders: productionQueue,
 	}
 	oldQueue.RemoveFinishedOrders()
 	oldQueue.Sort()
+	oldQueue.TrackSLA(time.Now())
 
 	assert.NoError(t, err)
 	assert.Equal(t, oldQueue.Orders, queue.Orders)
@@ -61,11 +134,11 @@ func TestGetProductionOrderQueueError(t *testing.T) {
 	mockErr := errors.New("mock error")
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetAll().Return([]entities.ProductionOrder{}, mockErr).Times(1)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return([]entities.ProductionOrder{}, mockErr).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
 
-	queue, err := prodOrderUseCase.GetProductionOrderQueue()
+	queue, err := prodOrderUseCase.GetProductionOrderQueue(currentStore)
 
 	assert.Nil(t, queue)
 	assert.EqualError(t, err, mockErr.Error())
@@ -74,20 +147,25 @@ func TestGetProductionOrderQueueError(t *testing.T) {
 func TestSendOrderToProduction(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
+	useFixedClock(t)
 
 	productionOrder := entities.ProductionOrder{
-		OrderId: 1,
-		Status:  entities.RECEIVED_STATUS,
+		StoreId:   currentStore,
+		OrderId:   1,
+		Status:    entities.RECEIVED_STATUS,
+		Priority:  entities.NORMAL_PRIORITY,
+		CreatedAt: fixedNow,
+		History:   receivedHistory,
 	}
 	orderID := uint32(1)
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(orderID).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, orderID).Return(nil, nil).Times(1)
 	mockRepo.EXPECT().Create(productionOrder).Return(&productionOrder, nil).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
 
-	sendOrder, err := prodOrderUseCase.SendOrderToProduction(orderID)
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{StoreId: currentStore, OrderId: orderID})
 
 	assert.NoError(t, err)
 	assert.Equal(t, &productionOrder, sendOrder)
@@ -101,10 +179,10 @@ func TestSendOrderToProductionGetError(t *testing.T) {
 
 	mockErr := errors.New("mock error")
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(orderID).Return(nil, mockErr).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, orderID).Return(nil, mockErr).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
-	sendOrder, err := prodOrderUseCase.SendOrderToProduction(orderID)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{StoreId: currentStore, OrderId: orderID})
 
 	assert.EqualError(t, err, mockErr.Error())
 	assert.Nil(t, sendOrder)
@@ -117,11 +195,11 @@ func TestSendOrderToProductionValidateError(t *testing.T) {
 	orderID := uint32(0)
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(orderID).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, orderID).Return(nil, nil).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
 
-	sendOrder, err := prodOrderUseCase.SendOrderToProduction(orderID)
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{StoreId: currentStore, OrderId: orderID})
 
 	assert.EqualError(t, err, "OrderId: cannot be blank.")
 	assert.Nil(t, sendOrder)
@@ -132,16 +210,17 @@ func TestSendOrderToProductionAlreadySendError(t *testing.T) {
 	defer ctrl.Finish()
 
 	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
 		OrderId: 1,
 		Status:  entities.RECEIVED_STATUS,
 	}
 	orderID := uint32(1)
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(orderID).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, orderID).Return(&productionOrder, nil).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
-	sendOrder, err := prodOrderUseCase.SendOrderToProduction(orderID)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{StoreId: currentStore, OrderId: orderID})
 
 	assert.EqualError(t, err, "order already sended to production queue")
 	assert.Nil(t, sendOrder)
@@ -150,21 +229,26 @@ func TestSendOrderToProductionAlreadySendError(t *testing.T) {
 func TestSendOrderToProductionCreateError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
+	useFixedClock(t)
 
 	productionOrder := entities.ProductionOrder{
-		OrderId: 1,
-		Status:  entities.RECEIVED_STATUS,
+		StoreId:   currentStore,
+		OrderId:   1,
+		Status:    entities.RECEIVED_STATUS,
+		Priority:  entities.NORMAL_PRIORITY,
+		CreatedAt: fixedNow,
+		History:   receivedHistory,
 	}
 	orderID := uint32(1)
 
 	mockCreateError := errors.New("mock create error")
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(orderID).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, orderID).Return(nil, nil).Times(1)
 	mockRepo.EXPECT().Create(productionOrder).Return(nil, mockCreateError).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
 
-	sendOrder, err := prodOrderUseCase.SendOrderToProduction(orderID)
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{StoreId: currentStore, OrderId: orderID})
 
 	assert.EqualError(t, err, mockCreateError.Error())
 	assert.Nil(t, sendOrder)
@@ -175,16 +259,17 @@ func TestUpdateProductionOrderStatus(t *testing.T) {
 	defer ctrl.Finish()
 
 	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
 		OrderId: 1,
 		Status:  entities.IN_PREPARATION_STATUS,
 	}
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
 	mockRepo.EXPECT().Update(productionOrder).Return(&productionOrder, nil).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
-	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(productionOrder.OrderId, productionOrder.Status)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, productionOrder.Status, kitchenActor)
 
 	assert.NoError(t, err)
 	assert.Equal(t, &productionOrder, updatedOrder)
@@ -195,16 +280,17 @@ func TestUpdateProductionOrderStatusGetError(t *testing.T) {
 	defer ctrl.Finish()
 
 	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
 		OrderId: 1,
 		Status:  entities.IN_PREPARATION_STATUS,
 	}
 
 	mockGetError := errors.New("mock get error")
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(productionOrder.OrderId).Return(nil, mockGetError).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(nil, mockGetError).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
-	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(productionOrder.OrderId, productionOrder.Status)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, productionOrder.Status, kitchenActor)
 
 	assert.EqualError(t, err, mockGetError.Error())
 	assert.Nil(t, updatedOrder)
@@ -215,15 +301,16 @@ func TestUpdateProductionOrderStatusNotFoundtError(t *testing.T) {
 	defer ctrl.Finish()
 
 	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
 		OrderId: 1,
 		Status:  entities.IN_PREPARATION_STATUS,
 	}
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(productionOrder.OrderId).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(nil, nil).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
-	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(productionOrder.OrderId, productionOrder.Status)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, productionOrder.Status, kitchenActor)
 
 	assert.EqualError(t, err, "Cant find production order")
 	assert.Nil(t, updatedOrder)
@@ -234,17 +321,18 @@ func TestUpdateProductionOrderStatusValidateError(t *testing.T) {
 	defer ctrl.Finish()
 
 	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
 		OrderId: 1,
 		Status:  "status invalid",
 	}
 
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
-	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(productionOrder.OrderId, productionOrder.Status)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, productionOrder.Status, kitchenActor)
 
-	assert.EqualError(t, err, "Status: must be between RECEBIDO, EM_PREPARACAO, PRONTO or FINALIZADO.")
+	assert.EqualError(t, err, "Status: must be between RECEBIDO, EM_PREPARACAO, PRONTO, FINALIZADO or CANCELADO.")
 	assert.Nil(t, updatedOrder)
 }
 
@@ -253,18 +341,1203 @@ func TestUpdateProductionOrderStatusUpdateError(t *testing.T) {
 	defer ctrl.Finish()
 
 	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
 		OrderId: 1,
 		Status:  entities.IN_PREPARATION_STATUS,
 	}
 
 	mockUpdateError := errors.New("mock update error")
 	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
-	mockRepo.EXPECT().GetByOrderId(productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
 	mockRepo.EXPECT().Update(productionOrder).Return(nil, mockUpdateError).Times(1)
 
-	prodOrderUseCase := NewProductionOrderUseCase(mockRepo)
-	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(productionOrder.OrderId, productionOrder.Status)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, productionOrder.Status, kitchenActor)
 
 	assert.EqualError(t, err, mockUpdateError.Error())
 	assert.Nil(t, updatedOrder)
 }
+
+func TestSendOrderToProductionRoutesItemsToStations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	burger := entities.ProductionOrderItem{Name: "X-Burguer", Category: "Lanche", Quantity: 1}
+	soda := entities.ProductionOrderItem{Name: "Refrigerante", Category: "Bebida", Quantity: 2}
+	fries := entities.ProductionOrderItem{Name: "Batata", Category: "Acompanhamento", Quantity: 1}
+
+	productionOrder := entities.ProductionOrder{
+		StoreId:   currentStore,
+		OrderId:   1,
+		Status:    entities.RECEIVED_STATUS,
+		Priority:  entities.NORMAL_PRIORITY,
+		CreatedAt: fixedNow,
+		History:   receivedHistory,
+		Items:     []entities.ProductionOrderItem{burger, soda, fries},
+		Tickets: []entities.StationTicket{
+			{
+				Station: "grill",
+				Status:  entities.RECEIVED_STATUS,
+				Items:   []entities.ProductionOrderItem{burger, fries},
+			},
+			{
+				Station: "drinks",
+				Status:  entities.RECEIVED_STATUS,
+				Items:   []entities.ProductionOrderItem{soda},
+			},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().Create(productionOrder).Return(&productionOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Items:   productionOrder.Items,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &productionOrder, sendOrder)
+}
+
+func TestSendOrderToProductionInvalidItemError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(nil, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Items:   []entities.ProductionOrderItem{{Name: "X-Burguer", Category: "Lanche"}},
+	})
+
+	assert.EqualError(t, err, "Items: (0: (Quantity: cannot be blank.).).")
+	assert.Nil(t, sendOrder)
+}
+
+func TestUpdateProductionOrderStatusPendingTicketsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.IN_PREPARATION_STATUS,
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: entities.DONE_STATUS},
+			{Station: "drinks", Status: entities.IN_PREPARATION_STATUS},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, entities.DONE_STATUS, kitchenActor)
+
+	assert.EqualError(t, err, "order still has station tickets in production")
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateStationTicketStatus(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		OrderStatus    string
+		Tickets        []entities.StationTicket
+		Station        string
+		Status         string
+		ExpectedStatus string
+	}{
+		{
+			Name:        "order goes into preparation when the first station starts",
+			OrderStatus: entities.RECEIVED_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.RECEIVED_STATUS},
+				{Station: "drinks", Status: entities.RECEIVED_STATUS},
+			},
+			Station:        "grill",
+			Status:         entities.IN_PREPARATION_STATUS,
+			ExpectedStatus: entities.IN_PREPARATION_STATUS,
+		},
+		{
+			Name:        "order stays in preparation while a station is pending",
+			OrderStatus: entities.IN_PREPARATION_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.IN_PREPARATION_STATUS},
+				{Station: "drinks", Status: entities.RECEIVED_STATUS},
+			},
+			Station:        "grill",
+			Status:         entities.DONE_STATUS,
+			ExpectedStatus: entities.IN_PREPARATION_STATUS,
+		},
+		{
+			Name:        "order is ready when every station is done",
+			OrderStatus: entities.IN_PREPARATION_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.DONE_STATUS},
+				{Station: "drinks", Status: entities.IN_PREPARATION_STATUS},
+			},
+			Station:        "drinks",
+			Status:         entities.DONE_STATUS,
+			ExpectedStatus: entities.DONE_STATUS,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			productionOrder := entities.ProductionOrder{
+				StoreId: currentStore,
+				OrderId: 1,
+				Status:  tt.OrderStatus,
+				Tickets: tt.Tickets,
+			}
+
+			mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+			mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+			mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+				return &order, nil
+			}).Times(1)
+
+			prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+			updatedOrder, err := prodOrderUseCase.UpdateStationTicketStatus(currentStore, productionOrder.OrderId, tt.Station, tt.Status, kitchenActor)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ExpectedStatus, updatedOrder.Status)
+			assert.Equal(t, tt.Status, updatedOrder.TicketForStation(tt.Station).Status)
+		})
+	}
+}
+
+func TestUpdateStationTicketStatusNotFoundError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(nil, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateStationTicketStatus(currentStore, 1, "grill", entities.DONE_STATUS, kitchenActor)
+
+	assert.EqualError(t, err, "Cant find production order")
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateStationTicketStatusUnknownStationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: entities.RECEIVED_STATUS},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateStationTicketStatus(currentStore, productionOrder.OrderId, "dessert", entities.DONE_STATUS, kitchenActor)
+
+	assert.EqualError(t, err, "order 1 has no ticket for station dessert")
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateStationTicketStatusFinishedOrderError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.FINISHED_STATUS,
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: entities.IN_PREPARATION_STATUS},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateStationTicketStatus(currentStore, productionOrder.OrderId, "grill", entities.DONE_STATUS, kitchenActor)
+
+	assert.EqualError(t, err, "order 1 is FINALIZADO, its tickets cant change")
+	assert.Nil(t, updatedOrder)
+	assert.Equal(t, entities.FINISHED_STATUS, productionOrder.Status)
+}
+
+func TestGetStationQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productionOrders := []entities.ProductionOrder{
+		{
+			StoreId: currentStore,
+			OrderId: 1,
+			Status:  entities.IN_PREPARATION_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.DONE_STATUS},
+				{Station: "drinks", Status: entities.IN_PREPARATION_STATUS},
+			},
+		},
+		{
+			StoreId: currentStore,
+			OrderId: 2,
+			Status:  entities.RECEIVED_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.RECEIVED_STATUS},
+			},
+		},
+		{
+			StoreId: currentStore,
+			OrderId: 3,
+			Status:  entities.IN_PREPARATION_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.IN_PREPARATION_STATUS},
+				{Station: "drinks", Status: entities.RECEIVED_STATUS},
+			},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return(productionOrders, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	queue, err := prodOrderUseCase.GetStationQueue(currentStore, "grill")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.ProductionOrder{
+		{
+			StoreId: currentStore,
+			OrderId: 3,
+			Status:  entities.IN_PREPARATION_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.IN_PREPARATION_STATUS},
+			},
+			SLA: &entities.OrderSLA{},
+		},
+		{
+			StoreId: currentStore,
+			OrderId: 2,
+			Status:  entities.RECEIVED_STATUS,
+			Tickets: []entities.StationTicket{
+				{Station: "grill", Status: entities.RECEIVED_STATUS},
+			},
+			SLA: &entities.OrderSLA{},
+		},
+	}, queue.Orders)
+}
+
+func TestGetStationQueueError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockErr := errors.New("mock error")
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return([]entities.ProductionOrder{}, mockErr).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	queue, err := prodOrderUseCase.GetStationQueue(currentStore, "grill")
+
+	assert.Nil(t, queue)
+	assert.EqualError(t, err, mockErr.Error())
+}
+
+func TestGetProductionOrderQueuePriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrders := []entities.ProductionOrder{
+		{
+			StoreId:   currentStore,
+			OrderId:   1,
+			Status:    entities.RECEIVED_STATUS,
+			Priority:  entities.NORMAL_PRIORITY,
+			CreatedAt: fixedNow.Add(-2 * time.Minute),
+		},
+		{
+			StoreId:   currentStore,
+			OrderId:   2,
+			Status:    entities.RECEIVED_STATUS,
+			Priority:  entities.RUSH_PRIORITY,
+			CreatedAt: fixedNow.Add(-1 * time.Minute),
+		},
+		{
+			StoreId:   currentStore,
+			OrderId:   3,
+			Status:    entities.RECEIVED_STATUS,
+			Priority:  entities.NORMAL_PRIORITY,
+			CreatedAt: fixedNow.Add(-11 * time.Minute),
+		},
+		{
+			StoreId:   currentStore,
+			OrderId:   4,
+			Status:    entities.RECEIVED_STATUS,
+			Priority:  entities.HIGH_PRIORITY,
+			CreatedAt: fixedNow.Add(-3 * time.Minute),
+		},
+		{
+			StoreId:   currentStore,
+			OrderId:   5,
+			Status:    entities.IN_PREPARATION_STATUS,
+			Priority:  entities.NORMAL_PRIORITY,
+			CreatedAt: fixedNow.Add(-20 * time.Minute),
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return(productionOrders, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	queue, err := prodOrderUseCase.GetProductionOrderQueue(currentStore)
+
+	assert.NoError(t, err)
+
+	orderIds := []uint32{}
+	for _, order := range queue.Orders {
+		orderIds = append(orderIds, order.OrderId)
+	}
+
+	// order 3 waited two aging intervals and now competes with the rush order,
+	// winning the tie because it arrived first
+	assert.Equal(t, []uint32{5, 3, 2, 4, 1}, orderIds)
+}
+
+func TestSendOrderToProductionWithPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId:   currentStore,
+		OrderId:   1,
+		Status:    entities.RECEIVED_STATUS,
+		Priority:  entities.RUSH_PRIORITY,
+		CreatedAt: fixedNow,
+		History:   receivedHistory,
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().Create(productionOrder).Return(&productionOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	sendOrder, err := prodOrderUseCase.SendOrderToProduction(currentStore, entities.ProductionOrder{
+		StoreId:  currentStore,
+		OrderId:  1,
+		Priority: entities.RUSH_PRIORITY,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &productionOrder, sendOrder)
+}
+
+func TestUpdateProductionOrderPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productionOrder := entities.ProductionOrder{
+		StoreId:  currentStore,
+		OrderId:  1,
+		Status:   entities.RECEIVED_STATUS,
+		Priority: entities.NORMAL_PRIORITY,
+	}
+
+	expectedOrder := productionOrder
+	expectedOrder.Priority = entities.HIGH_PRIORITY
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(expectedOrder).Return(&expectedOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderPriority(currentStore, productionOrder.OrderId, entities.HIGH_PRIORITY)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedOrder, updatedOrder)
+}
+
+func TestUpdateProductionOrderPriorityErrors(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		FoundOrder  *entities.ProductionOrder
+		Priority    string
+		ExpectedErr string
+	}{
+		{
+			Name:        "order not found",
+			FoundOrder:  nil,
+			Priority:    entities.HIGH_PRIORITY,
+			ExpectedErr: "Cant find production order",
+		},
+		{
+			Name:        "order already finished",
+			FoundOrder:  &entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.FINISHED_STATUS},
+			Priority:    entities.HIGH_PRIORITY,
+			ExpectedErr: "cant change priority of a closed order",
+		},
+		{
+			Name:        "invalid priority",
+			FoundOrder:  &entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.RECEIVED_STATUS},
+			Priority:    "priority invalid",
+			ExpectedErr: "Priority: must be between NORMAL, ALTA or URGENTE.",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+			mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(tt.FoundOrder, nil).Times(1)
+
+			prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+			updatedOrder, err := prodOrderUseCase.UpdateProductionOrderPriority(currentStore, 1, tt.Priority)
+
+			assert.EqualError(t, err, tt.ExpectedErr)
+			assert.Nil(t, updatedOrder)
+		})
+	}
+}
+
+func TestGetProductionOrderQueueTracksSLA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrders := []entities.ProductionOrder{
+		{
+			StoreId: currentStore,
+			OrderId: 1,
+			Status:  entities.IN_PREPARATION_STATUS,
+			History: []entities.StatusTransition{
+				{Status: entities.RECEIVED_STATUS, At: fixedNow.Add(-30 * time.Minute)},
+				{Status: entities.IN_PREPARATION_STATUS, At: fixedNow.Add(-20 * time.Minute)},
+			},
+		},
+		{
+			StoreId:   currentStore,
+			OrderId:   2,
+			Status:    entities.RECEIVED_STATUS,
+			CreatedAt: fixedNow.Add(-2 * time.Minute),
+		},
+	}
+
+	policy := entities.QueuePolicy{
+		StatusTargets: map[string]time.Duration{
+			entities.RECEIVED_STATUS:       5 * time.Minute,
+			entities.IN_PREPARATION_STATUS: 15 * time.Minute,
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return(productionOrders, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, policy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	queue, err := prodOrderUseCase.GetProductionOrderQueue(currentStore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.OrderSLA{ElapsedSeconds: 1200, TargetSeconds: 900, Late: true}, queue.Orders[0].SLA)
+	assert.Equal(t, &entities.OrderSLA{ElapsedSeconds: 120, TargetSeconds: 300, Late: false}, queue.Orders[1].SLA)
+}
+
+func TestUpdateProductionOrderStatusRecordsTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		History: []entities.StatusTransition{
+			{Status: entities.RECEIVED_STATUS, At: fixedNow.Add(-time.Minute)},
+		},
+	}
+
+	expectedOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.IN_PREPARATION_STATUS,
+		History: []entities.StatusTransition{
+			{Status: entities.RECEIVED_STATUS, At: fixedNow.Add(-time.Minute)},
+			{Status: entities.IN_PREPARATION_STATUS, At: fixedNow},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(expectedOrder).Return(&expectedOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, entities.IN_PREPARATION_STATUS, kitchenActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedOrder, updatedOrder)
+}
+
+func TestUpdateProductionOrderStatusPublishesStatusChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId:  currentStore,
+		OrderId:  1,
+		Status:   entities.IN_PREPARATION_STATUS,
+		Priority: entities.HIGH_PRIORITY,
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(order entities.ProductionOrder) (*entities.ProductionOrder, error) {
+		return &order, nil
+	}).Times(1)
+
+	mockPublisher := mock_publisher.NewMockStatusChangePublisher(ctrl)
+	mockPublisher.EXPECT().PublishStatusChange(entities.StatusChangeEvent{
+		EventId:        "loja-1#1#" + fixedNow.UTC().Format("2006-01-02T15:04:05.000000000Z"),
+		Event:          entities.ORDER_READY_EVENT,
+		StoreId:        currentStore,
+		OrderId:        1,
+		PreviousStatus: entities.IN_PREPARATION_STATUS,
+		Status:         entities.DONE_STATUS,
+		Priority:       entities.HIGH_PRIORITY,
+		OccurredAt:     fixedNow,
+	}).Return(errors.New("webhook queue is full")).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, mockPublisher)
+	_, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, productionOrder.OrderId, entities.DONE_STATUS, kitchenActor)
+
+	assert.NoError(t, err, "a change that could not be published is still applied")
+}
+
+func TestGetProductionOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	readyAt := fixedNow.Add(10 * time.Minute)
+	queuedOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+	}
+	finishedOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 2,
+		Status:  entities.FINISHED_STATUS,
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return([]entities.ProductionOrder{queuedOrder, finishedOrder}, nil).Times(3)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(2)).Return(&finishedOrder, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(3)).Return(nil, nil).Times(1)
+
+	mockEstimator := mock_estimator.NewMockReadyTimeEstimator(ctrl)
+	mockEstimator.EXPECT().EstimateReadyTimes(gomock.Any(), gomock.Any(), fixedNow).Return(map[uint32]time.Time{1: readyAt}).Times(3)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, mockEstimator, noStatusChangePublisher{})
+
+	order, err := prodOrderUseCase.GetProductionOrder(currentStore, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &readyAt, order.EstimatedReadyAt)
+	assert.Equal(t, &entities.OrderSLA{}, order.SLA)
+
+	order, err = prodOrderUseCase.GetProductionOrder(currentStore, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &finishedOrder, order)
+
+	order, err = prodOrderUseCase.GetProductionOrder(currentStore, 3)
+	assert.EqualError(t, err, "Cant find production order")
+	assert.Nil(t, order)
+}
+
+func TestCancelProductionOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+	}
+
+	expectedOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.CANCELED_STATUS,
+		History: []entities.StatusTransition{
+			{Status: entities.CANCELED_STATUS, At: fixedNow},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, productionOrder.OrderId).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(expectedOrder).Return(&expectedOrder, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	canceledOrder, err := prodOrderUseCase.CancelProductionOrder(currentStore, productionOrder.OrderId, kitchenActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &expectedOrder, canceledOrder)
+}
+
+func TestCancelProductionOrderErrors(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		FoundOrder  *entities.ProductionOrder
+		ExpectedErr string
+	}{
+		{
+			Name:        "order not found",
+			FoundOrder:  nil,
+			ExpectedErr: "Cant find production order",
+		},
+		{
+			Name:        "order already finished",
+			FoundOrder:  &entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.FINISHED_STATUS},
+			ExpectedErr: "cant cancel a closed order",
+		},
+		{
+			Name:        "order already canceled",
+			FoundOrder:  &entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.CANCELED_STATUS},
+			ExpectedErr: "cant cancel a closed order",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+			mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(tt.FoundOrder, nil).Times(1)
+
+			prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+			canceledOrder, err := prodOrderUseCase.CancelProductionOrder(currentStore, 1, kitchenActor)
+
+			assert.EqualError(t, err, tt.ExpectedErr)
+			assert.Nil(t, canceledOrder)
+		})
+	}
+}
+
+func TestUpdateProductionOrderStatusCancelErrors(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		FoundOrder  entities.ProductionOrder
+		Status      string
+		ExpectedErr string
+	}{
+		{
+			Name:        "cancel through the status endpoint",
+			FoundOrder:  entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.RECEIVED_STATUS},
+			Status:      entities.CANCELED_STATUS,
+			ExpectedErr: "orders must be canceled through the cancel endpoint",
+		},
+		{
+			Name:        "change a canceled order",
+			FoundOrder:  entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.CANCELED_STATUS},
+			Status:      entities.IN_PREPARATION_STATUS,
+			ExpectedErr: "cant change status of a CANCELADO order to EM_PREPARACAO",
+		},
+		{
+			Name:        "reopen a finished order",
+			FoundOrder:  entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.FINISHED_STATUS},
+			Status:      entities.DONE_STATUS,
+			ExpectedErr: "cant change status of a FINALIZADO order to PRONTO",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.Name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+			mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&tt.FoundOrder, nil).Times(1)
+
+			prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+			updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, 1, tt.Status, kitchenActor)
+
+			assert.EqualError(t, err, tt.ExpectedErr)
+			assert.Nil(t, updatedOrder)
+		})
+	}
+}
+
+func TestUpdateProductionOrderStatusFinishedOrderIsValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).
+		Return(&entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.FINISHED_STATUS}, nil).Times(1)
+	mockRepo.EXPECT().Update(gomock.Any()).Times(0)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	_, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, 1, entities.RECEIVED_STATUS, kitchenActor)
+
+	var validationError *custom_errors.ValidationError
+	assert.ErrorAs(t, err, &validationError)
+}
+
+func TestUpdateProductionOrderStatusFromAnotherStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId("loja-2", uint32(1)).Return(nil, nil).Times(1)
+	mockRepo.EXPECT().Update(gomock.Any()).Times(0)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	updatedOrder, err := prodOrderUseCase.UpdateProductionOrderStatus("loja-2", 1, entities.IN_PREPARATION_STATUS, kitchenActor)
+
+	assert.EqualError(t, err, "Cant find production order")
+	assert.Nil(t, updatedOrder)
+}
+
+func TestUpdateProductionOrderStatusRecordsAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		History: receivedHistory,
+	}
+	updatedOrder := productionOrder
+	updatedOrder.ChangeStatus(entities.IN_PREPARATION_STATUS, fixedNow)
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(updatedOrder).Return(&updatedOrder, nil).Times(1)
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().Append(entities.AuditEntry{
+		OrderKey:       "loja-1#1",
+		EntryId:        "2024-10-10T12:00:00.000000000Z#STATUS_CHANGED",
+		At:             fixedNow,
+		StoreId:        currentStore,
+		OrderId:        1,
+		Action:         entities.STATUS_CHANGED_AUDIT_ACTION,
+		Actor:          "cozinheiro",
+		Station:        "balcao",
+		PreviousStatus: entities.RECEIVED_STATUS,
+		NewStatus:      entities.IN_PREPARATION_STATUS,
+		RequestId:      "req-1",
+	}).Return(nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, mockAudit, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	result, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, 1, entities.IN_PREPARATION_STATUS, kitchenActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &updatedOrder, result)
+}
+
+func TestUpdateProductionOrderStatusToTheSameStatusRecordsNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.IN_PREPARATION_STATUS,
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(productionOrder).Return(&productionOrder, nil).Times(1)
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockPublisher := mock_publisher.NewMockStatusChangePublisher(ctrl)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, mockAudit, stationRouter, queuePolicy, noReadyTimeEstimator{}, mockPublisher)
+	result, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, 1, entities.IN_PREPARATION_STATUS, kitchenActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &productionOrder, result)
+}
+
+func TestUpdateProductionOrderStatusAuditFailureKeepsUpdate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(gomock.Any()).Return(&productionOrder, nil).Times(1)
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().Append(gomock.Any()).Return(errors.New("mock audit error")).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, mockAudit, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	result, err := prodOrderUseCase.UpdateProductionOrderStatus(currentStore, 1, entities.IN_PREPARATION_STATUS, entities.Actor{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &productionOrder, result)
+}
+
+func TestUpdateStationTicketStatusRecordsAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.IN_PREPARATION_STATUS,
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: entities.IN_PREPARATION_STATUS},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(gomock.Any()).Return(&productionOrder, nil).Times(1)
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	gomock.InOrder(
+		mockAudit.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry entities.AuditEntry) error {
+			assert.Equal(t, entities.TICKET_CHANGED_AUDIT_ACTION, entry.Action)
+			assert.Equal(t, "grill", entry.Station)
+			assert.Equal(t, entities.IN_PREPARATION_STATUS, entry.PreviousStatus)
+			assert.Equal(t, entities.DONE_STATUS, entry.NewStatus)
+			return nil
+		}).Times(1),
+		mockAudit.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry entities.AuditEntry) error {
+			assert.Equal(t, entities.STATUS_CHANGED_AUDIT_ACTION, entry.Action)
+			assert.Equal(t, entities.IN_PREPARATION_STATUS, entry.PreviousStatus)
+			assert.Equal(t, entities.DONE_STATUS, entry.NewStatus)
+			return nil
+		}).Times(1),
+	)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, mockAudit, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	_, err := prodOrderUseCase.UpdateStationTicketStatus(currentStore, 1, "grill", entities.DONE_STATUS, kitchenActor)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateStationTicketStatusToTheSameStatusRecordsNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.IN_PREPARATION_STATUS,
+		Tickets: []entities.StationTicket{
+			{Station: "grill", Status: entities.IN_PREPARATION_STATUS},
+			{Station: "bar", Status: entities.RECEIVED_STATUS},
+		},
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(gomock.Any()).Return(&productionOrder, nil).Times(1)
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockPublisher := mock_publisher.NewMockStatusChangePublisher(ctrl)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, mockAudit, stationRouter, queuePolicy, noReadyTimeEstimator{}, mockPublisher)
+	_, err := prodOrderUseCase.UpdateStationTicketStatus(currentStore, 1, "grill", entities.IN_PREPARATION_STATUS, kitchenActor)
+
+	assert.NoError(t, err)
+}
+
+func TestCancelProductionOrderRecordsAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	productionOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&productionOrder, nil).Times(1)
+	mockRepo.EXPECT().Update(gomock.Any()).Return(&productionOrder, nil).Times(1)
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry entities.AuditEntry) error {
+		assert.Equal(t, entities.ORDER_CANCELED_AUDIT_ACTION, entry.Action)
+		assert.Equal(t, entities.UNKNOWN_ACTOR, entry.Actor)
+		assert.Equal(t, entities.RECEIVED_STATUS, entry.PreviousStatus)
+		assert.Equal(t, entities.CANCELED_STATUS, entry.NewStatus)
+		return nil
+	}).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, mockAudit, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	_, err := prodOrderUseCase.CancelProductionOrder(currentStore, 1, entities.Actor{})
+
+	assert.NoError(t, err)
+}
+
+func bulkOrder(orderId uint32, status string) entities.ProductionOrder {
+	return entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: orderId,
+		Status:  status,
+		History: []entities.StatusTransition{{Status: status, At: fixedNow}},
+	}
+}
+
+func TestUpdateProductionOrderStatuses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	ready := bulkOrder(1, entities.DONE_STATUS)
+	finished := bulkOrder(1, entities.DONE_STATUS)
+	finished.ChangeStatus(entities.FINISHED_STATUS, fixedNow)
+	canceled := bulkOrder(2, entities.CANCELED_STATUS)
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&ready, nil).Times(1)
+	mockRepo.EXPECT().Update(finished).Return(&finished, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(2)).Return(&canceled, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(3)).Return(nil, nil).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	results, err := prodOrderUseCase.UpdateProductionOrderStatuses(currentStore, []entities.StatusChange{
+		{OrderId: 1, Status: entities.FINISHED_STATUS},
+		{OrderId: 2, Status: entities.FINISHED_STATUS},
+		{OrderId: 3, Status: entities.FINISHED_STATUS},
+	}, false, kitchenActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.StatusChangeResult{
+		{OrderId: 1, Status: entities.FINISHED_STATUS, Applied: true, Order: &finished},
+		{OrderId: 2, Status: entities.FINISHED_STATUS, Error: "cant change status of a CANCELADO order to FINALIZADO"},
+		{OrderId: 3, Status: entities.FINISHED_STATUS, Error: "Cant find production order"},
+	}, results)
+}
+
+func TestUpdateProductionOrderStatusesAtomically(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	first := bulkOrder(1, entities.DONE_STATUS)
+	second := bulkOrder(2, entities.DONE_STATUS)
+	finishedFirst := bulkOrder(1, entities.DONE_STATUS)
+	finishedFirst.ChangeStatus(entities.FINISHED_STATUS, fixedNow)
+	finishedSecond := bulkOrder(2, entities.DONE_STATUS)
+	finishedSecond.ChangeStatus(entities.FINISHED_STATUS, fixedNow)
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&first, nil).Times(1)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(2)).Return(&second, nil).Times(1)
+	mockRepo.EXPECT().UpdateAll([]entities.ProductionOrder{finishedFirst, finishedSecond}).Return([]entities.ProductionOrder{finishedFirst, finishedSecond}, nil).Times(1)
+
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().Append(gomock.Any()).DoAndReturn(func(entry entities.AuditEntry) error {
+		assert.Equal(t, entities.DONE_STATUS, entry.PreviousStatus)
+		assert.Equal(t, entities.FINISHED_STATUS, entry.NewStatus)
+		return nil
+	}).Times(2)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, mockAudit, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	results, err := prodOrderUseCase.UpdateProductionOrderStatuses(currentStore, []entities.StatusChange{
+		{OrderId: 1, Status: entities.FINISHED_STATUS},
+		{OrderId: 2, Status: entities.FINISHED_STATUS},
+	}, true, kitchenActor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.StatusChangeResult{
+		{OrderId: 1, Status: entities.FINISHED_STATUS, Applied: true, Order: &finishedFirst},
+		{OrderId: 2, Status: entities.FINISHED_STATUS, Applied: true, Order: &finishedSecond},
+	}, results)
+}
+
+func TestUpdateProductionOrderStatusesAtomicallyChangesNothingOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	first := bulkOrder(1, entities.DONE_STATUS)
+	canceled := bulkOrder(2, entities.CANCELED_STATUS)
+	again := bulkOrder(1, entities.DONE_STATUS)
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&first, nil),
+		mockRepo.EXPECT().GetByOrderId(currentStore, uint32(2)).Return(&canceled, nil),
+		mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&again, nil),
+	)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	results, err := prodOrderUseCase.UpdateProductionOrderStatuses(currentStore, []entities.StatusChange{
+		{OrderId: 1, Status: entities.FINISHED_STATUS},
+		{OrderId: 2, Status: entities.FINISHED_STATUS},
+		{OrderId: 1, Status: entities.FINISHED_STATUS},
+	}, true, kitchenActor)
+
+	assert.EqualError(t, err, "no status was changed, some of the changes failed")
+	assert.Equal(t, []entities.StatusChangeResult{
+		{OrderId: 1, Status: entities.FINISHED_STATUS, Error: "not applied, another change failed"},
+		{OrderId: 2, Status: entities.FINISHED_STATUS, Error: "cant change status of a CANCELADO order to FINALIZADO"},
+		{OrderId: 1, Status: entities.FINISHED_STATUS, Error: "order 1 is changed more than once"},
+	}, results)
+}
+
+func TestUpdateProductionOrderStatusesAtomicallyUpdateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	first := bulkOrder(1, entities.DONE_STATUS)
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&first, nil).Times(1)
+	mockRepo.EXPECT().UpdateAll(gomock.Any()).Return(nil, errors.New("transaction canceled")).Times(1)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	results, err := prodOrderUseCase.UpdateProductionOrderStatuses(currentStore, []entities.StatusChange{
+		{OrderId: 1, Status: entities.FINISHED_STATUS},
+	}, true, kitchenActor)
+
+	assert.EqualError(t, err, "transaction canceled")
+	assert.Nil(t, results)
+}
+
+func listedOrder(orderId uint32, status string, priority string, receivedAt time.Time) entities.ProductionOrder {
+	return entities.ProductionOrder{
+		StoreId:  currentStore,
+		OrderId:  orderId,
+		Status:   status,
+		Priority: priority,
+		History:  []entities.StatusTransition{{Status: entities.RECEIVED_STATUS, At: receivedAt}},
+	}
+}
+
+func TestListProductionOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	yesterday := fixedNow.Add(-24 * time.Hour)
+	finishedYesterday := listedOrder(1, entities.FINISHED_STATUS, entities.NORMAL_PRIORITY, yesterday)
+	canceledYesterday := listedOrder(2, entities.CANCELED_STATUS, entities.NORMAL_PRIORITY, yesterday)
+	finishedToday := listedOrder(3, entities.FINISHED_STATUS, entities.NORMAL_PRIORITY, fixedNow)
+	urgentYesterday := listedOrder(4, entities.FINISHED_STATUS, entities.RUSH_PRIORITY, yesterday)
+	otherYesterday := listedOrder(5, entities.FINISHED_STATUS, entities.NORMAL_PRIORITY, yesterday)
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetPageDescending(currentStore, 2, "start").Return([]entities.ProductionOrder{otherYesterday, urgentYesterday}, "after-4", nil),
+		mockRepo.EXPECT().GetPageDescending(currentStore, 1, "after-4").Return([]entities.ProductionOrder{finishedToday}, "after-3", nil),
+		mockRepo.EXPECT().GetPageDescending(currentStore, 1, "after-3").Return([]entities.ProductionOrder{canceledYesterday}, "after-2", nil),
+		mockRepo.EXPECT().GetPageDescending(currentStore, 1, "after-2").Return([]entities.ProductionOrder{finishedYesterday}, "after-1", nil),
+	)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	page, err := prodOrderUseCase.ListProductionOrders(currentStore, entities.ProductionOrderFilter{
+		Statuses:   []string{entities.FINISHED_STATUS},
+		Priorities: []string{entities.NORMAL_PRIORITY},
+		From:       yesterday,
+		To:         fixedNow,
+	}, entities.ORDER_ID_DESCENDING_SORT, 2, "start")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.ProductionOrderPage{
+		Orders:     []entities.ProductionOrder{otherYesterday, finishedYesterday},
+		NextCursor: "after-1",
+	}, page)
+}
+
+func TestListProductionOrdersBoundsTheReadsOfAPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetPage(currentStore, 10, gomock.Any()).Return([]entities.ProductionOrder{
+		listedOrder(1, entities.RECEIVED_STATUS, entities.NORMAL_PRIORITY, fixedNow),
+	}, "next", nil).Times(listMaxReads)
+
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+	page, err := prodOrderUseCase.ListProductionOrders(currentStore, entities.ProductionOrderFilter{
+		Statuses: []string{entities.FINISHED_STATUS},
+	}, "", 10, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.ProductionOrderPage{
+		Orders:     []entities.ProductionOrder{},
+		NextCursor: "next",
+	}, page)
+}
+
+func TestListProductionOrdersErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	prodOrderUseCase := NewProductionOrderUseCase(mockRepo, noAuditRepository{}, stationRouter, queuePolicy, noReadyTimeEstimator{}, noStatusChangePublisher{})
+
+	for _, tt := range []struct {
+		name    string
+		filter  entities.ProductionOrderFilter
+		sort    string
+		limit   int
+		wantErr string
+	}{
+		{"limit too small", entities.ProductionOrderFilter{}, "", 0, "limit must be between 1 and 100"},
+		{"limit too big", entities.ProductionOrderFilter{}, "", 101, "limit must be between 1 and 100"},
+		{"unknown sort", entities.ProductionOrderFilter{}, "created_at", 10, "sort must be order_id or -order_id"},
+		{"unknown status", entities.ProductionOrderFilter{Statuses: []string{"QUEIMADO"}}, "", 10, "Statuses: (0: must be between RECEBIDO, EM_PREPARACAO, PRONTO, FINALIZADO or CANCELADO.)."},
+		{"empty period", entities.ProductionOrderFilter{From: fixedNow, To: fixedNow}, "", 10, "from must be before to"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := prodOrderUseCase.ListProductionOrders(currentStore, tt.filter, tt.sort, tt.limit, "")
+
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, page)
+		})
+	}
+
+	mockRepo.EXPECT().GetPage(currentStore, 10, "not a cursor").Return(nil, "", repository.ErrInvalidCursor).Times(1)
+
+	page, err := prodOrderUseCase.ListProductionOrders(currentStore, entities.ProductionOrderFilter{}, "", 10, "not a cursor")
+
+	assert.Equal(t, &custom_errors.BadRequestError{Message: "invalid cursor"}, err)
+	assert.Nil(t, page)
+
+	mockRepo.EXPECT().GetPage(currentStore, 10, "").Return(nil, "", errors.New("dynamo is down")).Times(1)
+
+	page, err = prodOrderUseCase.ListProductionOrders(currentStore, entities.ProductionOrderFilter{}, "", 10, "")
+
+	assert.Equal(t, &custom_errors.DatabaseError{Message: "dynamo is down"}, err)
+	assert.Nil(t, page)
+}
@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"time"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+)
+
+type productionReportService struct {
+	productionOrderRepository repository.ProductionOrderRepository
+}
+
+func NewProductionReportUseCase(productionOrderRepository repository.ProductionOrderRepository) usecase.ProductionReportUseCases {
+	return &productionReportService{
+		productionOrderRepository: productionOrderRepository,
+	}
+}
+
+// GetSummary implements usecase.ProductionReportUseCases.
+func (p *productionReportService) GetSummary(storeId string, from time.Time, to time.Time) (*entities.ProductionReport, error) {
+	if !from.Before(to) {
+		return nil, &custom_errors.BadRequestError{
+			Message: "from must be before to",
+		}
+	}
+
+	productionOrders, err := p.productionOrderRepository.GetAllByStore(storeId)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	report := entities.NewProductionReport(productionOrders, from, to)
+
+	return &report, nil
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func orderWithHistory(orderId uint32, transitions ...entities.StatusTransition) entities.ProductionOrder {
+	return entities.ProductionOrder{
+		OrderId:   orderId,
+		Status:    transitions[len(transitions)-1].Status,
+		CreatedAt: transitions[0].At,
+		History:   transitions,
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := fixedNow
+	to := fixedNow.Add(24 * time.Hour)
+	at := func(minutes int) time.Time {
+		return from.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	productionOrders := []entities.ProductionOrder{
+		orderWithHistory(1,
+			entities.StatusTransition{Status: entities.RECEIVED_STATUS, At: at(0)},
+			entities.StatusTransition{Status: entities.IN_PREPARATION_STATUS, At: at(2)},
+			entities.StatusTransition{Status: entities.DONE_STATUS, At: at(12)},
+			entities.StatusTransition{Status: entities.FINISHED_STATUS, At: at(15)},
+		),
+		orderWithHistory(2,
+			entities.StatusTransition{Status: entities.RECEIVED_STATUS, At: at(10)},
+			entities.StatusTransition{Status: entities.IN_PREPARATION_STATUS, At: at(14)},
+			entities.StatusTransition{Status: entities.DONE_STATUS, At: at(70)},
+		),
+		orderWithHistory(3,
+			entities.StatusTransition{Status: entities.RECEIVED_STATUS, At: at(20)},
+			entities.StatusTransition{Status: entities.CANCELED_STATUS, At: at(21)},
+		),
+		orderWithHistory(4,
+			entities.StatusTransition{Status: entities.RECEIVED_STATUS, At: at(30)},
+		),
+		// received before the period
+		orderWithHistory(5,
+			entities.StatusTransition{Status: entities.RECEIVED_STATUS, At: at(-60)},
+			entities.StatusTransition{Status: entities.IN_PREPARATION_STATUS, At: at(-50)},
+			entities.StatusTransition{Status: entities.DONE_STATUS, At: at(5)},
+		),
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return(productionOrders, nil).Times(1)
+
+	report, err := NewProductionReportUseCase(mockRepo).GetSummary(currentStore, from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entities.ProductionReport{
+		From:        from,
+		To:          to,
+		TotalOrders: 4,
+		CountsByStatus: map[string]int{
+			entities.FINISHED_STATUS: 1,
+			entities.DONE_STATUS:     1,
+			entities.CANCELED_STATUS: 1,
+			entities.RECEIVED_STATUS: 1,
+		},
+		ThroughputByHour: []entities.HourlyThroughput{
+			{Hour: fixedNow, Count: 1},
+			{Hour: fixedNow.Add(time.Hour), Count: 1},
+		},
+		TimeInStatus: map[string]entities.StatusDurationStats{
+			entities.RECEIVED_STATUS: {
+				Count:          3,
+				AverageSeconds: 140,
+				P50Seconds:     120,
+				P90Seconds:     240,
+				P95Seconds:     240,
+			},
+			entities.IN_PREPARATION_STATUS: {
+				Count:          2,
+				AverageSeconds: 1980,
+				P50Seconds:     600,
+				P90Seconds:     3360,
+				P95Seconds:     3360,
+			},
+			entities.DONE_STATUS: {
+				Count:          1,
+				AverageSeconds: 180,
+				P50Seconds:     180,
+				P90Seconds:     180,
+				P95Seconds:     180,
+			},
+		},
+		CancellationRate: 0.25,
+	}, report)
+}
+
+func TestGetSummaryInvalidPeriodError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+
+	report, err := NewProductionReportUseCase(mockRepo).GetSummary(currentStore, fixedNow, fixedNow)
+
+	assert.EqualError(t, err, "from must be before to")
+	assert.Nil(t, report)
+}
+
+func TestGetSummaryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockErr := errors.New("mock error")
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetAllByStore(currentStore).Return([]entities.ProductionOrder{}, mockErr).Times(1)
+
+	report, err := NewProductionReportUseCase(mockRepo).GetSummary(currentStore, fixedNow, fixedNow.Add(time.Hour))
+
+	assert.EqualError(t, err, mockErr.Error())
+	assert.Nil(t, report)
+}
@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"sort"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/estimator"
+)
+
+// historicalSampleSize is how many of the latest prepared orders are used to
+// learn the average preparation time.
+const historicalSampleSize = 100
+
+type historicalReadyTimeEstimator struct {
+	kitchenCapacity    int
+	defaultPreparation time.Duration
+}
+
+// NewHistoricalReadyTimeEstimator estimates ready times from the average time
+// recent orders spent EM_PREPARACAO, assuming the kitchen prepares up to
+// kitchenCapacity orders at once and takes received orders in queue order.
+// defaultPreparation is used until there is history to learn from.
+func NewHistoricalReadyTimeEstimator(kitchenCapacity int, defaultPreparation time.Duration) estimator.ReadyTimeEstimator {
+	if kitchenCapacity < 1 {
+		kitchenCapacity = 1
+	}
+
+	return &historicalReadyTimeEstimator{
+		kitchenCapacity:    kitchenCapacity,
+		defaultPreparation: defaultPreparation,
+	}
+}
+
+func (h *historicalReadyTimeEstimator) EstimateReadyTimes(queue []entities.ProductionOrder, orders []entities.ProductionOrder, now time.Time) map[uint32]time.Time {
+	preparation := h.averagePreparation(orders)
+	estimates := map[uint32]time.Time{}
+	slots := []time.Time{}
+
+	for _, order := range queue {
+		switch order.Status {
+		case entities.DONE_STATUS:
+			estimates[order.OrderId] = order.StatusSince()
+		case entities.IN_PREPARATION_STATUS:
+			readyAt := order.StatusSince().Add(preparation)
+			if readyAt.Before(now) {
+				readyAt = now
+			}
+
+			estimates[order.OrderId] = readyAt
+			slots = append(slots, readyAt)
+		}
+	}
+
+	for len(slots) < h.kitchenCapacity {
+		slots = append(slots, now)
+	}
+
+	for _, order := range queue {
+		if order.Status != entities.RECEIVED_STATUS {
+			continue
+		}
+
+		sort.Slice(slots, func(i, j int) bool {
+			return slots[i].Before(slots[j])
+		})
+
+		startAt := slots[0]
+		if startAt.Before(now) {
+			startAt = now
+		}
+
+		slots[0] = startAt.Add(preparation)
+		estimates[order.OrderId] = slots[0]
+	}
+
+	return estimates
+}
+
+func (h *historicalReadyTimeEstimator) averagePreparation(orders []entities.ProductionOrder) time.Duration {
+	type preparedOrder struct {
+		readyAt     time.Time
+		preparation time.Duration
+	}
+
+	prepared := []preparedOrder{}
+	for _, order := range orders {
+		startedAt, readyAt := transitionAt(order, entities.IN_PREPARATION_STATUS), transitionAt(order, entities.DONE_STATUS)
+		if startedAt.IsZero() || readyAt.IsZero() || readyAt.Before(startedAt) {
+			continue
+		}
+
+		prepared = append(prepared, preparedOrder{
+			readyAt:     readyAt,
+			preparation: readyAt.Sub(startedAt),
+		})
+	}
+
+	if len(prepared) == 0 {
+		return h.defaultPreparation
+	}
+
+	sort.Slice(prepared, func(i, j int) bool {
+		return prepared[i].readyAt.After(prepared[j].readyAt)
+	})
+
+	if len(prepared) > historicalSampleSize {
+		prepared = prepared[:historicalSampleSize]
+	}
+
+	var total time.Duration
+	for _, order := range prepared {
+		total += order.preparation
+	}
+
+	return total / time.Duration(len(prepared))
+}
+
+// transitionAt returns when the order last entered the given status, or the
+// zero time if it never did.
+func transitionAt(order entities.ProductionOrder, status string) time.Time {
+	for i := len(order.History) - 1; i >= 0; i-- {
+		if order.History[i].Status == status {
+			return order.History[i].At
+		}
+	}
+
+	return time.Time{}
+}
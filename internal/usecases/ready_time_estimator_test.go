@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func preparedOrder(orderId uint32, startedAt time.Time, preparation time.Duration) entities.ProductionOrder {
+	return entities.ProductionOrder{
+		OrderId: orderId,
+		Status:  entities.FINISHED_STATUS,
+		History: []entities.StatusTransition{
+			{Status: entities.RECEIVED_STATUS, At: startedAt.Add(-time.Minute)},
+			{Status: entities.IN_PREPARATION_STATUS, At: startedAt},
+			{Status: entities.DONE_STATUS, At: startedAt.Add(preparation)},
+			{Status: entities.FINISHED_STATUS, At: startedAt.Add(preparation + time.Minute)},
+		},
+	}
+}
+
+func TestHistoricalReadyTimeEstimator(t *testing.T) {
+	queue := []entities.ProductionOrder{
+		{
+			OrderId: 10,
+			Status:  entities.DONE_STATUS,
+			History: []entities.StatusTransition{
+				{Status: entities.DONE_STATUS, At: fixedNow.Add(-time.Minute)},
+			},
+		},
+		{
+			OrderId: 11,
+			Status:  entities.IN_PREPARATION_STATUS,
+			History: []entities.StatusTransition{
+				{Status: entities.IN_PREPARATION_STATUS, At: fixedNow.Add(-2 * time.Minute)},
+			},
+		},
+		{
+			OrderId: 12,
+			Status:  entities.IN_PREPARATION_STATUS,
+			History: []entities.StatusTransition{
+				{Status: entities.IN_PREPARATION_STATUS, At: fixedNow.Add(-10 * time.Minute)},
+			},
+		},
+		{OrderId: 13, Status: entities.RECEIVED_STATUS},
+		{OrderId: 14, Status: entities.RECEIVED_STATUS},
+	}
+
+	orders := append([]entities.ProductionOrder{
+		preparedOrder(1, fixedNow.Add(-time.Hour), 4*time.Minute),
+		preparedOrder(2, fixedNow.Add(-time.Hour), 6*time.Minute),
+	}, queue...)
+
+	estimates := NewHistoricalReadyTimeEstimator(2, 10*time.Minute).EstimateReadyTimes(queue, orders, fixedNow)
+
+	assert.Equal(t, map[uint32]time.Time{
+		// already ready
+		10: fixedNow.Add(-time.Minute),
+		// started 2 minutes ago, average preparation is 5 minutes
+		11: fixedNow.Add(3 * time.Minute),
+		// overdue orders are expected to be ready at any moment
+		12: fixedNow,
+		// both kitchen slots are busy, so received orders wait for them
+		13: fixedNow.Add(5 * time.Minute),
+		14: fixedNow.Add(8 * time.Minute),
+	}, estimates)
+}
+
+func TestHistoricalReadyTimeEstimatorWithoutHistory(t *testing.T) {
+	queue := []entities.ProductionOrder{
+		{OrderId: 1, Status: entities.RECEIVED_STATUS},
+		{OrderId: 2, Status: entities.RECEIVED_STATUS},
+	}
+
+	estimates := NewHistoricalReadyTimeEstimator(1, 10*time.Minute).EstimateReadyTimes(queue, queue, fixedNow)
+
+	assert.Equal(t, map[uint32]time.Time{
+		1: fixedNow.Add(10 * time.Minute),
+		2: fixedNow.Add(20 * time.Minute),
+	}, estimates)
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+)
+
+type slaMonitorService struct {
+	productionOrderRepository repository.ProductionOrderRepository
+	queuePolicy               entities.QueuePolicy
+	slaBreachPublisher        publisher.SLABreachPublisher
+	published                 map[string]bool
+}
+
+func NewSLAMonitorUseCase(productionOrderRepository repository.ProductionOrderRepository, queuePolicy entities.QueuePolicy, slaBreachPublisher publisher.SLABreachPublisher) usecase.SLAMonitorUseCases {
+	return &slaMonitorService{
+		productionOrderRepository: productionOrderRepository,
+		queuePolicy:               queuePolicy,
+		slaBreachPublisher:        slaBreachPublisher,
+		published:                 map[string]bool{},
+	}
+}
+
+// CheckSLA publishes the breaches found in the queue that were not published by
+// a previous check and returns them. An order breaching the same status is only
+// published once.
+func (s *slaMonitorService) CheckSLA() ([]entities.SLABreach, error) {
+	productionOrders, err := s.productionOrderRepository.GetOpen()
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	productionQueue := entities.ProductionOrderQueue{
+		Orders: productionOrders,
+	}
+	productionQueue.RemoveFinishedOrders()
+
+	checkedAt := now()
+	stillLate := map[string]bool{}
+	breaches := []entities.SLABreach{}
+
+	for _, order := range productionQueue.Orders {
+		breach := s.queuePolicy.Breach(order, checkedAt)
+		if breach == nil {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%d:%s:%d", breach.StoreId, breach.OrderId, breach.Status, breach.Since.UnixNano())
+
+		if !s.published[key] {
+			err = s.slaBreachPublisher.PublishSLABreach(*breach)
+
+			if err != nil {
+				log.Println(err.Error())
+				continue
+			}
+
+			breaches = append(breaches, *breach)
+		}
+
+		stillLate[key] = true
+	}
+
+	s.published = stillLate
+
+	return breaches, nil
+}
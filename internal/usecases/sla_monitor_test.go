@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_publisher "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher/mock"
+	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var slaPolicy = entities.QueuePolicy{
+	StatusTargets: map[string]time.Duration{
+		entities.RECEIVED_STATUS: 5 * time.Minute,
+	},
+}
+
+func TestCheckSLA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	lateOrder := entities.ProductionOrder{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		History: []entities.StatusTransition{
+			{Status: entities.RECEIVED_STATUS, At: fixedNow.Add(-10 * time.Minute)},
+		},
+	}
+	onTimeOrder := entities.ProductionOrder{
+		OrderId:   2,
+		Status:    entities.RECEIVED_STATUS,
+		CreatedAt: fixedNow.Add(-time.Minute),
+	}
+	finishedOrder := entities.ProductionOrder{
+		OrderId:   3,
+		Status:    entities.FINISHED_STATUS,
+		CreatedAt: fixedNow.Add(-time.Hour),
+	}
+
+	expectedBreach := entities.SLABreach{
+		StoreId: currentStore,
+		OrderId: 1,
+		Status:  entities.RECEIVED_STATUS,
+		Since:   fixedNow.Add(-10 * time.Minute),
+		Elapsed: 10 * time.Minute,
+		Target:  5 * time.Minute,
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetOpen().Return([]entities.ProductionOrder{lateOrder, onTimeOrder, finishedOrder}, nil).Times(2)
+
+	mockPublisher := mock_publisher.NewMockSLABreachPublisher(ctrl)
+	mockPublisher.EXPECT().PublishSLABreach(expectedBreach).Return(nil).Times(1)
+
+	monitor := NewSLAMonitorUseCase(mockRepo, slaPolicy, mockPublisher)
+
+	breaches, err := monitor.CheckSLA()
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.SLABreach{expectedBreach}, breaches)
+
+	// the same breach is not published twice
+	breaches, err = monitor.CheckSLA()
+	assert.NoError(t, err)
+	assert.Empty(t, breaches)
+}
+
+func TestCheckSLAPublishErrorIsRetried(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	lateOrder := entities.ProductionOrder{
+		OrderId:   1,
+		Status:    entities.RECEIVED_STATUS,
+		CreatedAt: fixedNow.Add(-10 * time.Minute),
+	}
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetOpen().Return([]entities.ProductionOrder{lateOrder}, nil).Times(2)
+
+	mockPublisher := mock_publisher.NewMockSLABreachPublisher(ctrl)
+	gomock.InOrder(
+		mockPublisher.EXPECT().PublishSLABreach(gomock.Any()).Return(errors.New("mock publish error")).Times(1),
+		mockPublisher.EXPECT().PublishSLABreach(gomock.Any()).Return(nil).Times(1),
+	)
+
+	monitor := NewSLAMonitorUseCase(mockRepo, slaPolicy, mockPublisher)
+
+	breaches, err := monitor.CheckSLA()
+	assert.NoError(t, err)
+	assert.Empty(t, breaches)
+
+	breaches, err = monitor.CheckSLA()
+	assert.NoError(t, err)
+	assert.Len(t, breaches, 1)
+}
+
+func TestCheckSLAError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockErr := errors.New("mock error")
+
+	mockRepo := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockRepo.EXPECT().GetOpen().Return([]entities.ProductionOrder{}, mockErr).Times(1)
+
+	monitor := NewSLAMonitorUseCase(mockRepo, slaPolicy, mock_publisher.NewMockSLABreachPublisher(ctrl))
+
+	breaches, err := monitor.CheckSLA()
+
+	assert.Nil(t, breaches)
+	assert.EqualError(t, err, mockErr.Error())
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+)
+
+// rejectedChangeError marks a queued change the central database must never
+// receive, which is set aside instead of retried.
+type rejectedChangeError struct {
+	err error
+}
+
+func (r *rejectedChangeError) Error() string {
+	return r.err.Error()
+}
+
+type syncService struct {
+	outbox                    repository.SyncOutboxRepository
+	productionOrderRepository repository.ProductionOrderRepository
+	auditRepository           repository.AuditRepository
+	batchSize                 int
+}
+
+// NewSyncUseCase replays the changes queued in the outbox of a store node on the
+// repositories of the central database.
+func NewSyncUseCase(outbox repository.SyncOutboxRepository, productionOrderRepository repository.ProductionOrderRepository, auditRepository repository.AuditRepository, batchSize int) usecase.SyncUseCases {
+	return &syncService{
+		outbox:                    outbox,
+		productionOrderRepository: productionOrderRepository,
+		auditRepository:           auditRepository,
+		batchSize:                 batchSize,
+	}
+}
+
+// Sync implements usecase.SyncUseCases. Changes are replayed in the order they
+// were queued, batch after batch until the outbox is empty. A change the central
+// database rejects is set aside and the sync goes on, while an error reaching it
+// stops the sync, keeping the change queued for the next one.
+func (s *syncService) Sync() (entities.SyncReport, error) {
+	report := entities.SyncReport{}
+
+	for {
+		changes, err := s.outbox.GetPending(s.batchSize)
+
+		if err != nil {
+			return report, &custom_errors.DatabaseError{
+				Message: err.Error(),
+			}
+		}
+
+		for _, change := range changes {
+			err = s.sync(change, &report)
+
+			if err != nil {
+				return report, err
+			}
+		}
+
+		if len(changes) < s.batchSize {
+			return report, nil
+		}
+	}
+}
+
+func (s *syncService) sync(change entities.SyncChange, report *entities.SyncReport) error {
+	err := s.replay(change)
+
+	var rejected *rejectedChangeError
+	if errors.As(err, &rejected) {
+		log.Printf("rejected sync change %d: %s", change.Id, rejected.Error())
+
+		err = s.outbox.Reject(change.Id, rejected.Error())
+
+		if err != nil {
+			return &custom_errors.DatabaseError{
+				Message: err.Error(),
+			}
+		}
+
+		report.Rejected++
+		return nil
+	}
+
+	if err != nil {
+		return &custom_errors.DatabaseError{
+			Message: fmt.Sprintf("could not sync change %d: %s", change.Id, err.Error()),
+		}
+	}
+
+	err = s.outbox.Remove(change.Id)
+
+	if err != nil {
+		return &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	report.Synced++
+	return nil
+}
+
+func (s *syncService) replay(change entities.SyncChange) error {
+	switch {
+	case change.Kind == entities.ORDER_SYNC_CHANGE && change.Order != nil:
+		return s.replayOrder(*change.Order)
+	case change.Kind == entities.AUDIT_SYNC_CHANGE && change.Audit != nil:
+		return s.auditRepository.Append(*change.Audit)
+	}
+
+	return &rejectedChangeError{
+		err: fmt.Errorf("unknown change %s", change.Kind),
+	}
+}
+
+// replayOrder merges the order stored on the store node into its central copy.
+func (s *syncService) replayOrder(order entities.ProductionOrder) error {
+	order.Version = 0
+	centralOrder, err := s.productionOrderRepository.GetByOrderId(order.StoreId, order.OrderId)
+
+	if err != nil {
+		return err
+	}
+
+	if centralOrder == nil {
+		_, err = s.productionOrderRepository.Create(order)
+		return err
+	}
+
+	merged, err := entities.MergeProductionOrder(*centralOrder, order)
+
+	if err != nil {
+		return &rejectedChangeError{
+			err: err,
+		}
+	}
+
+	_, err = s.productionOrderRepository.Update(merged)
+
+	return err
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func syncedOrder(transitions ...entities.StatusTransition) entities.ProductionOrder {
+	return entities.ProductionOrder{
+		StoreId:   currentStore,
+		OrderId:   1,
+		Status:    transitions[len(transitions)-1].Status,
+		Priority:  entities.NORMAL_PRIORITY,
+		CreatedAt: fixedNow,
+		Items:     []entities.ProductionOrderItem{{Name: "X-Burguer", Category: "LANCHE", Quantity: 1}},
+		History:   transitions,
+	}
+}
+
+func syncTransition(status string, minutes int) entities.StatusTransition {
+	return entities.StatusTransition{
+		Status: status,
+		At:     fixedNow.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+func TestSync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newOrder := syncedOrder(syncTransition(entities.RECEIVED_STATUS, 0))
+	newOrder.OrderId = 2
+	localOrder := syncedOrder(
+		syncTransition(entities.RECEIVED_STATUS, 0),
+		syncTransition(entities.IN_PREPARATION_STATUS, 5),
+		syncTransition(entities.DONE_STATUS, 15),
+	)
+	localOrder.Version = 3
+	centralOrder := syncedOrder(
+		syncTransition(entities.RECEIVED_STATUS, 0),
+		syncTransition(entities.IN_PREPARATION_STATUS, 5),
+	)
+	centralOrder.Priority = entities.RUSH_PRIORITY
+	entry := entities.NewAuditEntry(localOrder, entities.STATUS_CHANGED_AUDIT_ACTION, entities.IN_PREPARATION_STATUS, entities.DONE_STATUS, kitchenActor, fixedNow)
+
+	mockOutbox := mock_repository.NewMockSyncOutboxRepository(ctrl)
+	mockOrders := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+
+	gomock.InOrder(
+		mockOutbox.EXPECT().GetPending(3).Return([]entities.SyncChange{
+			{Id: 1, Kind: entities.ORDER_SYNC_CHANGE, Order: &newOrder},
+			{Id: 2, Kind: entities.ORDER_SYNC_CHANGE, Order: &localOrder},
+			{Id: 3, Kind: entities.AUDIT_SYNC_CHANGE, Audit: &entry},
+		}, nil),
+		mockOutbox.EXPECT().GetPending(3).Return([]entities.SyncChange{}, nil),
+	)
+
+	mockOrders.EXPECT().GetByOrderId(currentStore, uint32(2)).Return(nil, nil)
+	mockOrders.EXPECT().Create(newOrder).Return(&newOrder, nil)
+	mockOutbox.EXPECT().Remove(int64(1)).Return(nil)
+
+	merged := localOrder
+	merged.Version = 0
+	mockOrders.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&centralOrder, nil)
+	mockOrders.EXPECT().Update(merged).Return(&merged, nil)
+	mockOutbox.EXPECT().Remove(int64(2)).Return(nil)
+
+	mockAudit.EXPECT().Append(entry).Return(nil)
+	mockOutbox.EXPECT().Remove(int64(3)).Return(nil)
+
+	report, err := NewSyncUseCase(mockOutbox, mockOrders, mockAudit, 3).Sync()
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.SyncReport{Synced: 3}, report)
+}
+
+func TestSyncMergesConcurrentChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	localOrder := syncedOrder(
+		syncTransition(entities.RECEIVED_STATUS, 0),
+		syncTransition(entities.IN_PREPARATION_STATUS, 5),
+	)
+	centralOrder := syncedOrder(
+		syncTransition(entities.RECEIVED_STATUS, 0),
+		syncTransition(entities.DONE_STATUS, 10),
+	)
+	centralOrder.Priority = entities.RUSH_PRIORITY
+
+	mockOutbox := mock_repository.NewMockSyncOutboxRepository(ctrl)
+	mockOrders := mock_repository.NewMockProductionOrderRepository(ctrl)
+
+	mockOutbox.EXPECT().GetPending(10).Return([]entities.SyncChange{
+		{Id: 1, Kind: entities.ORDER_SYNC_CHANGE, Order: &localOrder},
+	}, nil)
+	mockOrders.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&centralOrder, nil)
+
+	merged := syncedOrder(
+		syncTransition(entities.RECEIVED_STATUS, 0),
+		syncTransition(entities.IN_PREPARATION_STATUS, 5),
+		syncTransition(entities.DONE_STATUS, 10),
+	)
+	merged.Priority = entities.RUSH_PRIORITY
+	mockOrders.EXPECT().Update(merged).Return(&merged, nil)
+	mockOutbox.EXPECT().Remove(int64(1)).Return(nil)
+
+	report, err := NewSyncUseCase(mockOutbox, mockOrders, nil, 10).Sync()
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.SyncReport{Synced: 1}, report)
+}
+
+func TestSyncRejectsIllegalTransitions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	localOrder := syncedOrder(
+		syncTransition(entities.RECEIVED_STATUS, 0),
+		syncTransition(entities.IN_PREPARATION_STATUS, 5),
+	)
+	centralOrder := syncedOrder(
+		syncTransition(entities.RECEIVED_STATUS, 0),
+		syncTransition(entities.CANCELED_STATUS, 2),
+	)
+
+	mockOutbox := mock_repository.NewMockSyncOutboxRepository(ctrl)
+	mockOrders := mock_repository.NewMockProductionOrderRepository(ctrl)
+
+	mockOutbox.EXPECT().GetPending(10).Return([]entities.SyncChange{
+		{Id: 1, Kind: entities.ORDER_SYNC_CHANGE, Order: &localOrder},
+		{Id: 2, Kind: "PAYMENT"},
+	}, nil)
+	mockOrders.EXPECT().GetByOrderId(currentStore, uint32(1)).Return(&centralOrder, nil)
+	mockOutbox.EXPECT().Reject(int64(1), "order 1 of store loja-1 cant move from CANCELADO to EM_PREPARACAO at 2024-10-10T12:05:00Z").Return(nil)
+	mockOutbox.EXPECT().Reject(int64(2), "unknown change PAYMENT").Return(nil)
+
+	report, err := NewSyncUseCase(mockOutbox, mockOrders, nil, 10).Sync()
+
+	assert.NoError(t, err)
+	assert.Equal(t, entities.SyncReport{Rejected: 2}, report)
+}
+
+func TestSyncStopsWhileTheCentralDatabaseIsUnreachable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	localOrder := syncedOrder(syncTransition(entities.RECEIVED_STATUS, 0))
+	entry := entities.NewAuditEntry(localOrder, entities.STATUS_CHANGED_AUDIT_ACTION, "", entities.RECEIVED_STATUS, kitchenActor, fixedNow)
+
+	mockOutbox := mock_repository.NewMockSyncOutboxRepository(ctrl)
+	mockOrders := mock_repository.NewMockProductionOrderRepository(ctrl)
+	mockAudit := mock_repository.NewMockAuditRepository(ctrl)
+
+	mockOutbox.EXPECT().GetPending(10).Return([]entities.SyncChange{
+		{Id: 1, Kind: entities.AUDIT_SYNC_CHANGE, Audit: &entry},
+		{Id: 2, Kind: entities.ORDER_SYNC_CHANGE, Order: &localOrder},
+	}, nil)
+	mockAudit.EXPECT().Append(entry).Return(errors.New("dynamodb is unavailable, circuit breaker is open"))
+
+	report, err := NewSyncUseCase(mockOutbox, mockOrders, mockAudit, 10).Sync()
+
+	assert.EqualError(t, err, "could not sync change 1: dynamodb is unavailable, circuit breaker is open")
+	assert.Equal(t, entities.SyncReport{}, report)
+}
@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+)
+
+type webhookService struct {
+	webhookRepository repository.WebhookRepository
+	policy            entities.WebhookPolicy
+}
+
+func NewWebhookUseCase(webhookRepository repository.WebhookRepository, policy entities.WebhookPolicy) usecase.WebhookUseCases {
+	return &webhookService{
+		webhookRepository: webhookRepository,
+		policy:            policy,
+	}
+}
+
+// Subscribe implements usecase.WebhookUseCases. The subscription starts
+// enabled, under a new id, once its URL is allowed by the policy.
+func (w *webhookService) Subscribe(storeId string, subscription entities.WebhookSubscription) (*entities.WebhookSubscription, error) {
+	err := subscription.Validate(w.policy)
+
+	if err != nil {
+		return nil, &custom_errors.BadRequestError{
+			Message: err.Error(),
+		}
+	}
+
+	subscriptionId, err := newSubscriptionId()
+
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.StoreId = storeId
+	subscription.SubscriptionId = subscriptionId
+	subscription.Enabled = true
+	subscription.ConsecutiveFailures = 0
+	subscription.DisabledAt = nil
+	subscription.CreatedAt = now()
+
+	err = w.webhookRepository.CreateSubscription(subscription)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	return &subscription, nil
+}
+
+// GetSubscriptions implements usecase.WebhookUseCases.
+func (w *webhookService) GetSubscriptions(storeId string) ([]entities.WebhookSubscription, error) {
+	subscriptions, err := w.webhookRepository.GetSubscriptions(storeId)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// Unsubscribe implements usecase.WebhookUseCases. The delivery log of the
+// subscription goes along with it.
+func (w *webhookService) Unsubscribe(storeId string, subscriptionId string) error {
+	_, err := w.getSubscription(storeId, subscriptionId)
+
+	if err != nil {
+		return err
+	}
+
+	err = w.webhookRepository.DeleteSubscription(storeId, subscriptionId)
+
+	if err != nil {
+		return &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// EnableSubscription implements usecase.WebhookUseCases. The failures that
+// disabled the subscription are forgotten.
+func (w *webhookService) EnableSubscription(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	subscription, err := w.getSubscription(storeId, subscriptionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.Enabled = true
+	subscription.ConsecutiveFailures = 0
+	subscription.DisabledAt = nil
+
+	err = w.webhookRepository.UpdateSubscription(*subscription)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	return subscription, nil
+}
+
+// GetDeliveries implements usecase.WebhookUseCases. Deliveries are returned
+// latest first.
+func (w *webhookService) GetDeliveries(storeId string, subscriptionId string, limit int) ([]entities.WebhookDelivery, error) {
+	if limit < 1 || limit > maxListLimit {
+		return nil, &custom_errors.BadRequestError{
+			Message: "limit must be between 1 and 100",
+		}
+	}
+
+	_, err := w.getSubscription(storeId, subscriptionId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := w.webhookRepository.GetDeliveries(storeId, subscriptionId, limit)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	return deliveries, nil
+}
+
+func (w *webhookService) getSubscription(storeId string, subscriptionId string) (*entities.WebhookSubscription, error) {
+	subscription, err := w.webhookRepository.GetSubscription(storeId, subscriptionId)
+
+	if err != nil {
+		return nil, &custom_errors.DatabaseError{
+			Message: err.Error(),
+		}
+	}
+
+	if subscription == nil {
+		return nil, &custom_errors.BadRequestError{
+			Message: "Cant find webhook subscription",
+		}
+	}
+
+	return subscription, nil
+}
+
+func newSubscriptionId() (string, error) {
+	id := make([]byte, 16)
+
+	_, err := rand.Read(id)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,156 @@
+package usecases
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+)
+
+// errWebhookQueueFull is returned when a status change is published faster
+// than the workers post them.
+var errWebhookQueueFull = errors.New("webhook queue is full")
+
+// schedule runs retry once the delay passes. It is replaced in tests to skip
+// the waits between attempts.
+var schedule = func(delay time.Duration, retry func()) {
+	time.AfterFunc(delay, retry)
+}
+
+// webhookAttempt is a post of an event to a subscription, numbered from 1.
+type webhookAttempt struct {
+	subscription entities.WebhookSubscription
+	event        entities.StatusChangeEvent
+	number       int
+}
+
+type webhookDispatcher struct {
+	webhookRepository repository.WebhookRepository
+	webhookSender     publisher.WebhookSender
+	policy            entities.WebhookPolicy
+	events            chan entities.StatusChangeEvent
+	retries           chan webhookAttempt
+}
+
+// NewWebhookDispatcher queues up to queueSize status changes, and as many
+// retries, in memory until a worker posts them. Queued changes and retries are
+// lost when the process exits.
+func NewWebhookDispatcher(webhookRepository repository.WebhookRepository, webhookSender publisher.WebhookSender, policy entities.WebhookPolicy, queueSize int) usecase.WebhookDispatcher {
+	return &webhookDispatcher{
+		webhookRepository: webhookRepository,
+		webhookSender:     webhookSender,
+		policy:            policy,
+		events:            make(chan entities.StatusChangeEvent, queueSize),
+		retries:           make(chan webhookAttempt, queueSize),
+	}
+}
+
+// PublishStatusChange queues the change without waiting for it to be posted,
+// so a slow subscriber never holds the request that changed the order.
+func (w *webhookDispatcher) PublishStatusChange(event entities.StatusChangeEvent) error {
+	select {
+	case w.events <- event:
+		return nil
+	default:
+		return errWebhookQueueFull
+	}
+}
+
+// Dispatch posts the queued changes and retries one after the other.
+func (w *webhookDispatcher) Dispatch() {
+	for {
+		select {
+		case event := <-w.events:
+			w.Deliver(event)
+		case attempt := <-w.retries:
+			w.post(attempt)
+		}
+	}
+}
+
+// Deliver posts the change to every enabled subscription of the store asking
+// for it. Failed posts are retried later by the workers.
+func (w *webhookDispatcher) Deliver(event entities.StatusChangeEvent) {
+	subscriptions, err := w.webhookRepository.GetSubscriptions(event.StoreId)
+
+	if err != nil {
+		log.Printf("could not read webhook subscriptions of store %s: %s", event.StoreId, err.Error())
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.Matches(event) {
+			w.post(webhookAttempt{subscription: subscription, event: event, number: 1})
+		}
+	}
+}
+
+// post makes the attempt and appends it to the delivery log. A failed attempt
+// is queued again once the retry delay of the policy passes, so the worker
+// goes on with other changes meanwhile, until the attempts run out.
+func (w *webhookDispatcher) post(attempt webhookAttempt) {
+	statusCode, err := w.webhookSender.Send(attempt.subscription, attempt.event)
+	w.recordDelivery(entities.NewWebhookDelivery(attempt.subscription, attempt.event, attempt.number, now(), statusCode, err))
+
+	if err == nil {
+		w.recordOutcome(attempt.subscription, true)
+		return
+	}
+
+	if attempt.number >= w.policy.MaxAttempts {
+		w.recordOutcome(attempt.subscription, false)
+		return
+	}
+
+	retry := attempt
+	retry.number++
+	schedule(w.policy.RetryDelay(attempt.number), func() {
+		w.retries <- retry
+	})
+}
+
+func (w *webhookDispatcher) recordDelivery(delivery entities.WebhookDelivery) {
+	err := w.webhookRepository.AppendDelivery(delivery)
+
+	if err != nil {
+		log.Printf("could not record webhook delivery %+v: %s", delivery, err.Error())
+	}
+}
+
+// recordOutcome counts the changes in a row given up on for the subscription,
+// disabling it once the policy says so. A delivered change clears the count.
+// The count is changed by the repository in a single write, so outcomes
+// recorded together for the subscription are never lost.
+func (w *webhookDispatcher) recordOutcome(subscription entities.WebhookSubscription, ok bool) {
+	if ok {
+		err := w.webhookRepository.ClearDeliveryFailures(subscription.StoreId, subscription.SubscriptionId)
+
+		if err != nil {
+			log.Printf("could not update webhook subscription %s: %s", subscription.SubscriptionId, err.Error())
+		}
+
+		return
+	}
+
+	failing, err := w.webhookRepository.AddDeliveryFailure(subscription.StoreId, subscription.SubscriptionId)
+
+	if err != nil {
+		log.Printf("could not update webhook subscription %s: %s", subscription.SubscriptionId, err.Error())
+		return
+	}
+
+	if failing == nil || !failing.Enabled || w.policy.DisableAfter <= 0 || failing.ConsecutiveFailures < w.policy.DisableAfter {
+		return
+	}
+
+	log.Printf("disabling webhook subscription %s of store %s after %d failed deliveries", failing.SubscriptionId, failing.StoreId, failing.ConsecutiveFailures)
+	err = w.webhookRepository.DisableSubscription(failing.StoreId, failing.SubscriptionId, now())
+
+	if err != nil {
+		log.Printf("could not disable webhook subscription %s: %s", failing.SubscriptionId, err.Error())
+	}
+}
@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_publisher "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/publisher/mock"
+	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/usecase"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var webhookPolicy = entities.WebhookPolicy{
+	MaxAttempts:    3,
+	RetryBaseDelay: time.Second,
+	RetryMaxDelay:  time.Minute,
+	DisableAfter:   2,
+}
+
+var readyEvent = entities.NewStatusChangeEvent(
+	entities.ProductionOrder{StoreId: currentStore, OrderId: 1, Status: entities.DONE_STATUS},
+	entities.IN_PREPARATION_STATUS,
+	fixedNow,
+)
+
+// recordRetries queues the retries without waiting, keeping the delays for the
+// test to check.
+func recordRetries(t *testing.T) *[]time.Duration {
+	delays := []time.Duration{}
+	previous := schedule
+	schedule = func(delay time.Duration, retry func()) {
+		delays = append(delays, delay)
+		retry()
+	}
+	t.Cleanup(func() { schedule = previous })
+
+	return &delays
+}
+
+// deliver delivers the event and then the retries it queued, as the workers
+// would.
+func deliver(dispatcher usecase.WebhookDispatcher, event entities.StatusChangeEvent) {
+	webhooks := dispatcher.(*webhookDispatcher)
+	webhooks.Deliver(event)
+
+	for len(webhooks.retries) > 0 {
+		webhooks.post(<-webhooks.retries)
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+	delays := recordRetries(t)
+
+	notSubscribed := partnerSubscription
+	notSubscribed.SubscriptionId = "sub-2"
+	notSubscribed.Events = []string{entities.ORDER_CANCELED_EVENT}
+	disabled := partnerSubscription
+	disabled.SubscriptionId = "sub-3"
+	disabled.Enabled = false
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscriptions(currentStore).Return([]entities.WebhookSubscription{partnerSubscription, notSubscribed, disabled}, nil).Times(1)
+	mockRepo.EXPECT().AppendDelivery(entities.NewWebhookDelivery(partnerSubscription, readyEvent, 1, fixedNow, 200, nil)).Return(nil).Times(1)
+	mockRepo.EXPECT().ClearDeliveryFailures(currentStore, "sub-1").Return(nil).Times(1)
+
+	mockSender := mock_publisher.NewMockWebhookSender(ctrl)
+	mockSender.EXPECT().Send(partnerSubscription, readyEvent).Return(200, nil).Times(1)
+
+	deliver(NewWebhookDispatcher(mockRepo, mockSender, webhookPolicy, 10), readyEvent)
+
+	assert.Empty(t, *delays)
+}
+
+func TestDeliverRetries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+	delays := recordRetries(t)
+
+	failing := partnerSubscription
+	failing.ConsecutiveFailures = 1
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscriptions(currentStore).Return([]entities.WebhookSubscription{failing}, nil).Times(1)
+	mockRepo.EXPECT().AppendDelivery(entities.NewWebhookDelivery(failing, readyEvent, 1, fixedNow, 503, errors.New("subscriber answered 503"))).Return(nil).Times(1)
+	mockRepo.EXPECT().AppendDelivery(entities.NewWebhookDelivery(failing, readyEvent, 2, fixedNow, 200, nil)).Return(nil).Times(1)
+	mockRepo.EXPECT().ClearDeliveryFailures(currentStore, "sub-1").Return(nil).Times(1)
+
+	mockSender := mock_publisher.NewMockWebhookSender(ctrl)
+	gomock.InOrder(
+		mockSender.EXPECT().Send(failing, readyEvent).Return(503, errors.New("subscriber answered 503")),
+		mockSender.EXPECT().Send(failing, readyEvent).Return(200, nil),
+	)
+
+	deliver(NewWebhookDispatcher(mockRepo, mockSender, webhookPolicy, 10), readyEvent)
+
+	assert.Equal(t, []time.Duration{time.Second}, *delays)
+}
+
+func TestDeliverDisablesFailingSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+	delays := recordRetries(t)
+
+	failing := partnerSubscription
+	failing.ConsecutiveFailures = 1
+	counted := partnerSubscription
+	counted.ConsecutiveFailures = 2
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscriptions(currentStore).Return([]entities.WebhookSubscription{failing}, nil).Times(1)
+	mockRepo.EXPECT().AppendDelivery(gomock.Any()).Return(nil).Times(3)
+	mockRepo.EXPECT().AddDeliveryFailure(currentStore, "sub-1").Return(&counted, nil).Times(1)
+	mockRepo.EXPECT().DisableSubscription(currentStore, "sub-1", fixedNow).Return(nil).Times(1)
+
+	mockSender := mock_publisher.NewMockWebhookSender(ctrl)
+	mockSender.EXPECT().Send(failing, readyEvent).Return(0, errors.New("connection refused")).Times(3)
+
+	deliver(NewWebhookDispatcher(mockRepo, mockSender, webhookPolicy, 10), readyEvent)
+
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *delays)
+}
+
+func TestDeliverCountsFailuresBelowTheLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+	recordRetries(t)
+
+	counted := partnerSubscription
+	counted.ConsecutiveFailures = 1
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscriptions(currentStore).Return([]entities.WebhookSubscription{partnerSubscription}, nil).Times(1)
+	mockRepo.EXPECT().AppendDelivery(gomock.Any()).Return(nil).Times(3)
+	mockRepo.EXPECT().AddDeliveryFailure(currentStore, "sub-1").Return(&counted, nil).Times(1)
+
+	mockSender := mock_publisher.NewMockWebhookSender(ctrl)
+	mockSender.EXPECT().Send(partnerSubscription, readyEvent).Return(0, errors.New("connection refused")).Times(3)
+
+	deliver(NewWebhookDispatcher(mockRepo, mockSender, webhookPolicy, 10), readyEvent)
+}
+
+func TestDeliverQueuesRetriesOnceTheDelayPasses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	scheduled := []func(){}
+	previous := schedule
+	schedule = func(delay time.Duration, retry func()) { scheduled = append(scheduled, retry) }
+	t.Cleanup(func() { schedule = previous })
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscriptions(currentStore).Return([]entities.WebhookSubscription{partnerSubscription}, nil).Times(1)
+	mockRepo.EXPECT().AppendDelivery(gomock.Any()).Return(nil).Times(1)
+
+	mockSender := mock_publisher.NewMockWebhookSender(ctrl)
+	mockSender.EXPECT().Send(partnerSubscription, readyEvent).Return(0, errors.New("connection refused")).Times(1)
+
+	dispatcher := NewWebhookDispatcher(mockRepo, mockSender, webhookPolicy, 10).(*webhookDispatcher)
+	dispatcher.Deliver(readyEvent)
+
+	assert.Len(t, scheduled, 1)
+	assert.Empty(t, dispatcher.retries)
+
+	scheduled[0]()
+
+	assert.Equal(t, webhookAttempt{subscription: partnerSubscription, event: readyEvent, number: 2}, <-dispatcher.retries)
+}
+
+func TestPublishStatusChangeQueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dispatcher := NewWebhookDispatcher(mock_repository.NewMockWebhookRepository(ctrl), mock_publisher.NewMockWebhookSender(ctrl), webhookPolicy, 1)
+
+	assert.NoError(t, dispatcher.PublishStatusChange(readyEvent))
+	assert.ErrorIs(t, dispatcher.PublishStatusChange(readyEvent), errWebhookQueueFull)
+}
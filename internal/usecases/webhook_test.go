@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	custom_errors "github.com/8soat-grupo35/fastfood-order-production/internal/api/errors"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/entities"
+	mock_repository "github.com/8soat-grupo35/fastfood-order-production/internal/interfaces/repository/mock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var partnerSubscription = entities.WebhookSubscription{
+	StoreId:        currentStore,
+	SubscriptionId: "sub-1",
+	URL:            "https://parceiro.example/webhook",
+	Events:         []string{entities.ORDER_READY_EVENT},
+	Secret:         "0123456789abcdef",
+	Enabled:        true,
+	CreatedAt:      fixedNow,
+}
+
+func TestSubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFixedClock(t)
+
+	var created entities.WebhookSubscription
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().CreateSubscription(gomock.Any()).DoAndReturn(func(subscription entities.WebhookSubscription) error {
+		created = subscription
+		return nil
+	}).Times(1)
+
+	result, err := NewWebhookUseCase(mockRepo, webhookPolicy).Subscribe(currentStore, entities.WebhookSubscription{
+		StoreId:             "outra-loja",
+		URL:                 partnerSubscription.URL,
+		Events:              partnerSubscription.Events,
+		Secret:              partnerSubscription.Secret,
+		ConsecutiveFailures: 3,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, created, *result)
+	assert.Equal(t, currentStore, result.StoreId)
+	assert.Len(t, result.SubscriptionId, 32)
+	assert.True(t, result.Enabled)
+	assert.Zero(t, result.ConsecutiveFailures)
+	assert.Equal(t, fixedNow, result.CreatedAt)
+}
+
+func TestSubscribeInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+
+	for name, subscription := range map[string]entities.WebhookSubscription{
+		"not http":      {URL: "ftp://parceiro.example", Events: partnerSubscription.Events, Secret: partnerSubscription.Secret},
+		"plain http":    {URL: "http://parceiro.example/webhook", Events: partnerSubscription.Events, Secret: partnerSubscription.Secret},
+		"loopback":      {URL: "https://127.0.0.1/webhook", Events: partnerSubscription.Events, Secret: partnerSubscription.Secret},
+		"localhost":     {URL: "https://localhost:8000/webhook", Events: partnerSubscription.Events, Secret: partnerSubscription.Secret},
+		"link-local":    {URL: "https://169.254.169.254/latest/meta-data", Events: partnerSubscription.Events, Secret: partnerSubscription.Secret},
+		"private":       {URL: "https://10.0.0.5/webhook", Events: partnerSubscription.Events, Secret: partnerSubscription.Secret},
+		"private ipv6":  {URL: "https://[fd00::1]/webhook", Events: partnerSubscription.Events, Secret: partnerSubscription.Secret},
+		"unknown event": {URL: partnerSubscription.URL, Events: []string{"order.eaten"}, Secret: partnerSubscription.Secret},
+		"short secret":  {URL: partnerSubscription.URL, Events: partnerSubscription.Events, Secret: "curto"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := NewWebhookUseCase(mockRepo, webhookPolicy).Subscribe(currentStore, subscription)
+
+			assert.Nil(t, result)
+			assert.IsType(t, &custom_errors.BadRequestError{}, err)
+		})
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	subscription := partnerSubscription
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscription(currentStore, "sub-1").Return(&subscription, nil).Times(1)
+	mockRepo.EXPECT().DeleteSubscription(currentStore, "sub-1").Return(nil).Times(1)
+	mockRepo.EXPECT().GetSubscription(currentStore, "sub-9").Return(nil, nil).Times(1)
+
+	assert.NoError(t, NewWebhookUseCase(mockRepo, webhookPolicy).Unsubscribe(currentStore, "sub-1"))
+	assert.EqualError(t, NewWebhookUseCase(mockRepo, webhookPolicy).Unsubscribe(currentStore, "sub-9"), "Cant find webhook subscription")
+}
+
+func TestEnableSubscription(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	disabled := partnerSubscription
+	disabled.Enabled = false
+	disabled.ConsecutiveFailures = 5
+	disabled.DisabledAt = &fixedNow
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscription(currentStore, "sub-1").Return(&disabled, nil).Times(1)
+	mockRepo.EXPECT().UpdateSubscription(partnerSubscription).Return(nil).Times(1)
+
+	result, err := NewWebhookUseCase(mockRepo, webhookPolicy).EnableSubscription(currentStore, "sub-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &partnerSubscription, result)
+}
+
+func TestGetDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	subscription := partnerSubscription
+	deliveries := []entities.WebhookDelivery{
+		entities.NewWebhookDelivery(partnerSubscription, entities.StatusChangeEvent{EventId: "evt-1"}, 1, fixedNow, 200, nil),
+	}
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscription(currentStore, "sub-1").Return(&subscription, nil).Times(1)
+	mockRepo.EXPECT().GetDeliveries(currentStore, "sub-1", 50).Return(deliveries, nil).Times(1)
+
+	result, err := NewWebhookUseCase(mockRepo, webhookPolicy).GetDeliveries(currentStore, "sub-1", 50)
+
+	assert.NoError(t, err)
+	assert.Equal(t, deliveries, result)
+
+	_, err = NewWebhookUseCase(mockRepo, webhookPolicy).GetDeliveries(currentStore, "sub-1", 101)
+
+	assert.IsType(t, &custom_errors.BadRequestError{}, err)
+}
+
+func TestGetSubscriptionsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetSubscriptions(currentStore).Return(nil, errors.New("mock error")).Times(1)
+
+	result, err := NewWebhookUseCase(mockRepo, webhookPolicy).GetSubscriptions(currentStore)
+
+	assert.Nil(t, result)
+	assert.IsType(t, &custom_errors.DatabaseError{}, err)
+}
@@ -1,12 +1,50 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"log"
+	"os"
 
+	"github.com/8soat-grupo35/fastfood-order-production/external"
 	"github.com/8soat-grupo35/fastfood-order-production/internal/api/server"
+	"github.com/8soat-grupo35/fastfood-order-production/internal/cli"
 )
 
 func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file, overridden by the environment")
+	printConfig := flag.Bool("print-config", false, "print the configuration with its secrets redacted and exit")
+	flag.Parse()
+
+	external.SetConfigFile(*configFile)
+	cfg, err := external.LoadConfig()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *printConfig {
+		fmt.Println(cfg)
+		return
+	}
+
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "export" {
+		if err := cli.Export(args[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if len(args) > 0 && args[0] == "backfill" {
+		if err := cli.Backfill(args[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
 	fmt.Println("Iniciado o servidor Rest com GO")
 	server.Start()
 }